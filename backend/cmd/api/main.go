@@ -1,17 +1,33 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
 
 	"go-ecommerce/internal/app"
 	"go-ecommerce/internal/config"
 	"go-ecommerce/internal/database"
 	"go-ecommerce/internal/modules/brand"
+	"go-ecommerce/internal/modules/cart"
 	"go-ecommerce/internal/modules/category"
+	"go-ecommerce/internal/modules/cod"
+	"go-ecommerce/internal/modules/currency"
+	"go-ecommerce/internal/modules/order"
+	"go-ecommerce/internal/modules/payment"
 	"go-ecommerce/internal/modules/product"
+	"go-ecommerce/internal/modules/purchasing"
+	"go-ecommerce/internal/modules/question"
+	"go-ecommerce/internal/modules/review"
 	"go-ecommerce/internal/modules/user"
+	"go-ecommerce/internal/modules/warehouse"
+	"go-ecommerce/internal/modules/wishlist"
+	"go-ecommerce/internal/shared/jobs"
+	"go-ecommerce/internal/shared/slug"
 	"go-ecommerce/pkg/cloudinary"
 	"go-ecommerce/pkg/logger"
+	"go-ecommerce/pkg/mailer"
+	"go-ecommerce/pkg/notify"
 )
 
 func main() {
@@ -42,6 +58,11 @@ func main() {
 
 	db.Exec("CREATE EXTENSION IF NOT EXISTS pgcrypto;")
 
+	// Prices used to be decimal; money.Money stores bigint minor units
+	if err := database.ConvertMoneyColumns(db); err != nil {
+		log.Fatalf("Convert money columns failed: %v", err)
+	}
+
 	err = db.AutoMigrate(
 		&user.User{},
 		&user.Address{},
@@ -49,12 +70,53 @@ func main() {
 		&category.Category{},
 		&brand.Brand{},
 		&product.Product{},
+		&product.ProductOption{},
+		&product.ProductOptionValue{},
 		&product.ProductVariant{},
 		&product.ProductImage{},
+		&product.ImportJob{},
+		&product.PriceHistory{},
+		&product.StockMovement{},
+		&slug.History{},
+		&review.Review{},
+		&review.ReviewImage{},
+		&review.ReviewVote{},
+		&review.RatingSummary{},
+		&question.Question{},
+		&question.Answer{},
+		&wishlist.WishlistItem{},
+		&wishlist.AlertPreference{},
+		&currency.ExchangeRate{},
+		&cart.Cart{},
+		&cart.CartItem{},
+		&order.Order{},
+		&order.OrderItem{},
+		&order.OrderStatusHistory{},
+		&order.OrderSequence{},
+		&product.StockReservation{},
+		&warehouse.Warehouse{},
+		&warehouse.Province{},
+		&product.VariantStock{},
+		&purchasing.Supplier{},
+		&purchasing.PurchaseOrder{},
+		&purchasing.PurchaseOrderLine{},
+		&purchasing.GoodsReceipt{},
+		&purchasing.GoodsReceiptLine{},
+		&payment.Payment{},
+		&cod.Rule{},
+		&cod.Remittance{},
+		&cod.RemittanceLine{},
 	)
 	if err != nil {
 		log.Fatalf("Migration failed: %v", err)
 	}
+
+	// Backfill materialized paths for categories created before the tree existed
+	db.Exec("UPDATE categories SET path = '/' || id || '/', depth = 0 WHERE path = '' AND parent_id IS NULL")
+
+	// Orders placed before payment methods existed were paid on delivery
+	db.Exec("UPDATE orders SET cod_amount = total WHERE payment_method = 'cod' AND cod_amount = 0 AND paid_at IS NULL")
+
 	log.Println("Database migration completed!")
 
 	// Initialize Cloudinary
@@ -63,10 +125,60 @@ func main() {
 		log.Fatalf("Cloudinary init failed: %v", err)
 	}
 
-	// Initialize User Module
+	// Initialize Product repository (needed by carts and checkers)
+	productRepo := product.NewRepository(db)
+
+	// Initialize Warehouse Module (stock kept before warehouses existed goes to the default one)
+	warehouseRepo := warehouse.NewRepository(db)
+	warehouseService := warehouse.NewService(warehouseRepo)
+	defaultWarehouse, err := warehouseService.EnsureDefault(context.Background())
+	if err != nil {
+		log.Fatalf("Default warehouse init failed: %v", err)
+	}
+	if err := productRepo.AssignToWarehouse(context.Background(), defaultWarehouse.ID); err != nil {
+		log.Fatalf("Assign stock to default warehouse failed: %v", err)
+	}
+	warehouseHandler := warehouse.NewHandler(warehouseService)
+
+	// Initialize Cart Module
+	cartService := cart.NewService(cart.NewRepository(db), cart.NewProductRepoAdapter(productRepo))
+	cartHandler := cart.NewHandler(cartService)
+
+	// Initialize User Module (merges guest carts on login)
 	userRepo := user.NewRepository(db)
 	userService := user.NewService(userRepo, cfg)
-	userHandler := user.NewHandler(userService)
+	userHandler := user.NewHandler(userService, cartService)
+
+	// Initialize Order Module (checkout reserves stock and empties the cart in one transaction)
+	codRepo := cod.NewRepository(db)
+	orderRepo := order.NewRepository(db)
+	orderService := order.NewService(orderRepo, order.NewUserRepoAdapter(userRepo), order.NewCartServiceAdapter(cartService), product.NewStockUpdater(), cod.NewPolicy(codRepo), cfg.Order.ReservationTTL)
+	orderHandler := order.NewHandler(orderService)
+	orderPurchases := order.NewPurchases(orderRepo)
+
+	// Initialize Payment Module (a paid order is confirmed; gateways without credentials are disabled)
+	paymentService := payment.NewService(payment.NewRepository(db), payment.NewOrderServiceAdapter(orderService), order.NewPaidMarker(), payment.NewGateways(&cfg.Payment), cfg.Payment.PublicURL)
+	paymentHandler := payment.NewHandler(paymentService, cfg.Payment.ResultURL)
+
+	// Initialize COD Module (fees and limits by province, carrier remittances mark orders paid)
+	codService := cod.NewService(codRepo, cod.NewOrderRepoAdapter(orderRepo), order.NewPaidMarker())
+	codHandler := cod.NewHandler(codService)
+
+	// Mail (logged only when MAIL_HOST is not set)
+	mailClient := mailer.New(&cfg.Mail)
+
+	// Initialize Question Module (customers with a delivered order can answer too)
+	questionService := question.NewService(question.NewRepository(db), orderPurchases, question.NewUserRepoAdapter(userRepo), mailClient)
+	questionHandler := question.NewHandler(questionService)
+
+	// Initialize Currency Module (display prices only, orders settle in VND)
+	currencyService := currency.NewService(currency.NewRepository(db))
+	currencyHandler := currency.NewHandler(currencyService)
+	if cfg.Currency.RatesFile != "" {
+		if err := loadRatesFile(currencyService, cfg.Currency.RatesFile); err != nil {
+			log.Printf("Load exchange rates from %s failed: %v", cfg.Currency.RatesFile, err)
+		}
+	}
 
 	// Initialize Category Module
 	categoryRepo := category.NewRepository(db)
@@ -75,23 +187,73 @@ func main() {
 	brandRepo := brand.NewRepository(db)
 
 	// Initialize Product Module (needed for checkers)
-	productRepo := product.NewRepository(db)
 	productChecker := product.NewProductChecker(db)
 
+	// Slugs are unique across live and trashed rows; renamed slugs keep redirecting
+	slugRegistry := slug.NewRegistry(db)
+
 	// Now initialize services with product checker
-	categoryService := category.NewService(categoryRepo, productChecker)
+	categoryService := category.NewService(categoryRepo, productChecker, slugRegistry, cfg.Scheduler.TrashRetention)
 	categoryHandler := category.NewHandler(categoryService)
 
-	brandService := brand.NewService(brandRepo, cloudinaryClient, productChecker)
+	brandService := brand.NewService(brandRepo, cloudinaryClient, productChecker, slugRegistry, cfg.Scheduler.TrashRetention)
 	brandHandler := brand.NewHandler(brandService)
 
-	productService := product.NewService(productRepo, cloudinaryClient)
+	productService := product.NewService(productRepo, cloudinaryClient, slugRegistry, cfg.Scheduler.TrashRetention)
 	categoryAdapter := product.NewCategoryRepoAdapter(categoryRepo)
 	brandAdapter := product.NewBrandRepoAdapter(brandRepo)
-	productHandler := product.NewHandler(productService, categoryAdapter, brandAdapter)
+	productImporter := product.NewImporter(productService, productRepo, categoryAdapter, brandAdapter)
+	if err := productImporter.FailInterrupted(context.Background()); err != nil {
+		log.Printf("Mark interrupted import jobs failed: %v", err)
+	}
+	productExporter := product.NewExporter(productRepo, categoryAdapter, brandAdapter)
+	productHandler := product.NewHandler(productService, categoryAdapter, brandAdapter, productImporter, productExporter, questionService, currencyService)
+
+	// Initialize Purchasing Module (receipts go through the stock ledger)
+	purchasingService := purchasing.NewService(purchasing.NewRepository(db), purchasing.NewProductRepoAdapter(productRepo), purchasing.NewWarehouseRepoAdapter(warehouseRepo), product.NewStockReceiver())
+	purchasingHandler := purchasing.NewHandler(purchasingService)
+
+	// Initialize Review Module (one review per delivered order line)
+	reviewRepo := review.NewRepository(db)
+	reviewService := review.NewService(reviewRepo, cloudinaryClient, orderPurchases, product.NewRatingUpdater())
+	reviewHandler := review.NewHandler(reviewService)
+
+	// Initialize Wishlist Module
+	wishlistRepo := wishlist.NewRepository(db)
+	wishlistProducts := wishlist.NewProductRepoAdapter(productRepo)
+	wishlistService := wishlist.NewService(wishlistRepo, wishlistProducts)
+	wishlistHandler := wishlist.NewHandler(wishlistService)
+
+	// Start background jobs
+	productScheduler := product.NewScheduler(productRepo, cfg.Scheduler.Interval)
+	productScheduler.Start(context.Background())
+
+	wishlistNotifier := wishlist.NewNotifier(wishlistRepo, wishlistProducts, wishlist.NewUserRepoAdapter(userRepo), mailClient, cfg.Scheduler.AlertInterval)
+	wishlistNotifier.Start(context.Background())
+
+	// Report variants at their reorder threshold to staff (daily by default)
+	lowStockAlerter := product.NewLowStockAlerter(productRepo, notify.New(&cfg.Notify, mailClient), cfg.Scheduler.LowStockInterval)
+	lowStockAlerter.Start(context.Background())
+
+	// Purge trash past the retention window (products first, they reference categories/brands)
+	jobs.Every(context.Background(), "trash-purge", cfg.Scheduler.PurgeInterval, func(ctx context.Context) error {
+		if err := productService.PurgeExpired(ctx); err != nil {
+			return err
+		}
+		if err := brandService.PurgeExpired(ctx); err != nil {
+			return err
+		}
+		return categoryService.PurgeExpired(ctx)
+	})
+
+	// Drop guest carts nobody has used for a month
+	jobs.Every(context.Background(), "guest-cart-purge", cfg.Scheduler.PurgeInterval, cartService.PurgeStaleGuestCarts)
+
+	// Cancel orders nobody confirmed in time so their stock goes back on sale
+	jobs.Every(context.Background(), "order-expiry", cfg.Scheduler.Interval, orderService.ExpireUnconfirmed)
 
 	// Setup Router
-	router := app.SetupRouter(cfg, zapLogger, userHandler, categoryHandler, brandHandler, productHandler)
+	router := app.SetupRouter(cfg, zapLogger, userHandler, categoryHandler, brandHandler, productHandler, reviewHandler, questionHandler, wishlistHandler, currencyHandler, cartHandler, orderHandler, warehouseHandler, purchasingHandler, paymentHandler, codHandler)
 
 	// Start Server
 	log.Println("Server is starting on :8080...")
@@ -99,3 +261,18 @@ func main() {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
+
+// loadRatesFile saves the exchange rates of a CSV file over existing ones
+func loadRatesFile(service currency.Service, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	rates, err := currency.ParseRatesFile(f)
+	if err != nil {
+		return err
+	}
+	return service.ImportRates(context.Background(), rates, "")
+}
@@ -0,0 +1,245 @@
+// Command payment-sim is a local stand-in for the VNPay and MoMo gateways.
+//
+// It signs with the credentials from .env, so point the API at it and pay
+// orders without a sandbox account:
+//
+//	VNPAY_URL=http://localhost:9090/vnpay/pay
+//	MOMO_ENDPOINT=http://localhost:9090/momo
+//
+// Each payment shows a page to pay, cancel or decline it; -auto picks the
+// outcome without the page, for scripted tests. The signed results come from
+// package sim, which the payment tests use as well. Like the real gateways the IPN
+// is sent first, then the customer is redirected to the return URL.
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/json"
+	"flag"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-ecommerce/internal/config"
+	"go-ecommerce/internal/modules/payment"
+	"go-ecommerce/internal/modules/payment/sim"
+	"go-ecommerce/pkg/money"
+)
+
+var page = template.Must(template.New("pay").Parse(`<!DOCTYPE html>
+<html lang="vi">
+<head><meta charset="utf-8"><title>{{.Gateway}} simulator</title></head>
+<body style="font-family: sans-serif; max-width: 480px; margin: 40px auto">
+<h2>{{.Gateway}} (giả lập)</h2>
+<p>{{.OrderInfo}}</p>
+<p>Số tiền: <strong>{{.Amount}}</strong></p>
+<form method="post" action="{{.Action}}">
+	{{range $name, $value := .Fields}}<input type="hidden" name="{{$name}}" value="{{$value}}">{{end}}
+	<button name="result" value="success">Thanh toán</button>
+	<button name="result" value="fail">Từ chối (không đủ số dư)</button>
+	<button name="result" value="cancel">Huỷ giao dịch</button>
+</form>
+</body>
+</html>`))
+
+type pageData struct {
+	Gateway   string
+	OrderInfo string
+	Amount    string
+	Action    string
+	Fields    map[string]string
+}
+
+type simulator struct {
+	vnpay    *payment.VNPay
+	vnpayIPN string
+	momo     *payment.MoMo
+	auto     string
+	client   *http.Client
+	lastTxn  atomic.Int64
+
+	mu          sync.Mutex
+	momoPending map[string]*payment.MoMoCreateRequest // By request ID
+}
+
+func main() {
+	addr := flag.String("addr", ":9090", "listen address")
+	vnpayIPN := flag.String("vnpay-ipn", "", "VNPay IPN URL (default PAYMENT_PUBLIC_URL/api/v1/payments/vnpay/ipn)")
+	auto := flag.String("auto", "", "complete payments without the page: success, fail or cancel")
+	flag.Parse()
+
+	// Load Config
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Load config failed: %v", err)
+	}
+
+	sim := &simulator{
+		vnpayIPN:    *vnpayIPN,
+		auto:        *auto,
+		client:      &http.Client{Timeout: 15 * time.Second},
+		momoPending: make(map[string]*payment.MoMoCreateRequest),
+	}
+	sim.lastTxn.Store(time.Now().Unix())
+	if sim.vnpayIPN == "" {
+		sim.vnpayIPN = cfg.Payment.PublicURL + "/api/v1/payments/vnpay/ipn"
+	}
+
+	mux := http.NewServeMux()
+	if cfg.Payment.VNPay.TmnCode != "" && cfg.Payment.VNPay.HashSecret != "" {
+		sim.vnpay = payment.NewVNPay(&cfg.Payment.VNPay)
+		mux.HandleFunc("GET /vnpay/pay", sim.vnpayPay)
+		mux.HandleFunc("POST /vnpay/complete", sim.vnpayComplete)
+		log.Printf("VNPay: set VNPAY_URL=http://localhost%s/vnpay/pay", *addr)
+	}
+	if cfg.Payment.MoMo.PartnerCode != "" && cfg.Payment.MoMo.SecretKey != "" {
+		sim.momo = payment.NewMoMo(&cfg.Payment.MoMo)
+		mux.HandleFunc("POST /momo/v2/gateway/api/create", sim.momoCreate)
+		mux.HandleFunc("GET /momo/pay", sim.momoPay)
+		mux.HandleFunc("POST /momo/complete", sim.momoComplete)
+		log.Printf("MoMo: set MOMO_ENDPOINT=http://localhost%s/momo", *addr)
+	}
+	if sim.vnpay == nil && sim.momo == nil {
+		log.Fatal("No gateway credentials configured (VNPAY_TMN_CODE/VNPAY_HASH_SECRET or MOMO_PARTNER_CODE/MOMO_SECRET_KEY)")
+	}
+
+	log.Printf("Payment simulator is listening on %s...", *addr)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		log.Fatalf("Simulator failed: %v", err)
+	}
+}
+
+func (s *simulator) vnpayPay(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if !s.vnpaySigned(q) {
+		http.Error(w, "Sai chữ ký", http.StatusBadRequest)
+		return
+	}
+	if s.auto != "" {
+		s.vnpayFinish(w, r, q, s.auto)
+		return
+	}
+
+	amount, _ := strconv.ParseInt(q.Get("vnp_Amount"), 10, 64)
+	page.Execute(w, pageData{
+		Gateway:   "VNPay",
+		OrderInfo: q.Get("vnp_OrderInfo"),
+		Amount:    money.FormatVND(amount / 100),
+		Action:    "/vnpay/complete",
+		Fields:    map[string]string{"query": r.URL.RawQuery},
+	})
+}
+
+func (s *simulator) vnpayComplete(w http.ResponseWriter, r *http.Request) {
+	q, err := url.ParseQuery(r.FormValue("query"))
+	if err != nil || !s.vnpaySigned(q) {
+		http.Error(w, "Sai chữ ký", http.StatusBadRequest)
+		return
+	}
+	s.vnpayFinish(w, r, q, r.FormValue("result"))
+}
+
+// vnpayFinish sends the IPN and redirects the customer with the same signed result
+func (s *simulator) vnpayFinish(w http.ResponseWriter, r *http.Request, q url.Values, outcome string) {
+	res := sim.VNPayResult(s.vnpay, q, outcome, s.lastTxn.Add(1), time.Now())
+	resp, err := s.client.Get(s.vnpayIPN + "?" + res.Encode())
+	logIPN("VNPay", q.Get("vnp_TxnRef"), resp, err)
+	http.Redirect(w, r, q.Get("vnp_ReturnUrl")+"?"+res.Encode(), http.StatusFound)
+}
+
+func (s *simulator) vnpaySigned(q url.Values) bool {
+	return hmac.Equal([]byte(q.Get("vnp_SecureHash")), []byte(s.vnpay.Sign(q)))
+}
+
+func (s *simulator) momoCreate(w http.ResponseWriter, r *http.Request) {
+	var req payment.MoMoCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	res := payment.MoMoCreateResponse{
+		PartnerCode:  req.PartnerCode,
+		RequestID:    req.RequestID,
+		OrderID:      req.OrderID,
+		Amount:       req.Amount,
+		ResponseTime: time.Now().UnixMilli(),
+	}
+	if !hmac.Equal([]byte(req.Signature), []byte(s.momo.SignCreate(&req))) {
+		res.ResultCode, res.Message = 11, "Sai chữ ký"
+	} else {
+		s.mu.Lock()
+		s.momoPending[req.RequestID] = &req
+		s.mu.Unlock()
+		res.Message = "Thành công."
+		res.PayURL = "http://" + r.Host + "/momo/pay?id=" + url.QueryEscape(req.RequestID)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (s *simulator) momoPay(w http.ResponseWriter, r *http.Request) {
+	req := s.momoRequest(r.URL.Query().Get("id"))
+	if req == nil {
+		http.Error(w, "Không tìm thấy giao dịch", http.StatusNotFound)
+		return
+	}
+	if s.auto != "" {
+		s.momoFinish(w, r, req, s.auto)
+		return
+	}
+
+	page.Execute(w, pageData{
+		Gateway:   "MoMo",
+		OrderInfo: req.OrderInfo,
+		Amount:    money.FormatVND(req.Amount),
+		Action:    "/momo/complete",
+		Fields:    map[string]string{"id": req.RequestID},
+	})
+}
+
+func (s *simulator) momoComplete(w http.ResponseWriter, r *http.Request) {
+	req := s.momoRequest(r.FormValue("id"))
+	if req == nil {
+		http.Error(w, "Không tìm thấy giao dịch", http.StatusNotFound)
+		return
+	}
+	s.momoFinish(w, r, req, r.FormValue("result"))
+}
+
+func (s *simulator) momoRequest(id string) *payment.MoMoCreateRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.momoPending[id]
+}
+
+// momoFinish posts the IPN and redirects the customer with the same signed result
+func (s *simulator) momoFinish(w http.ResponseWriter, r *http.Request, req *payment.MoMoCreateRequest, outcome string) {
+	res := sim.MoMoResult(s.momo, req, outcome, s.lastTxn.Add(1), time.Now())
+	body, _ := json.Marshal(res)
+	resp, err := s.client.Post(req.IPNURL, "application/json", bytes.NewReader(body))
+	logIPN("MoMo", req.OrderID, resp, err)
+
+	s.mu.Lock()
+	delete(s.momoPending, req.RequestID)
+	s.mu.Unlock()
+
+	http.Redirect(w, r, req.RedirectURL+"?"+sim.MoMoReturnQuery(res).Encode(), http.StatusFound)
+}
+
+func logIPN(gateway, txnRef string, resp *http.Response, err error) {
+	if err != nil {
+		log.Printf("%s IPN %s failed: %v", gateway, txnRef, err)
+		return
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	log.Printf("%s IPN %s: %s %s", gateway, txnRef, resp.Status, body)
+}
@@ -0,0 +1,66 @@
+// Command reconcile-stock checks variant stock, stock per warehouse and product
+// total stock against the stock movement ledger and exits with status 1 on any mismatch.
+//
+// Run it once with -opening after upgrading: stock that existed before the
+// ledger has no movements and is recorded as an opening balance.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"go-ecommerce/internal/config"
+	"go-ecommerce/internal/database"
+	"go-ecommerce/internal/modules/product"
+)
+
+func main() {
+	opening := flag.Bool("opening", false, "record opening balances for variants without ledger entries first")
+	flag.Parse()
+
+	// Load Config
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Load config failed: %v", err)
+	}
+
+	// Connect Database
+	db, err := database.NewPostgresDB(cfg)
+	if err != nil {
+		log.Fatalf("Connect database failed: %v", err)
+	}
+
+	ctx := context.Background()
+	repo := product.NewRepository(db)
+
+	if *opening {
+		count, err := repo.RecordOpeningBalances(ctx)
+		if err != nil {
+			log.Fatalf("Record opening balances failed: %v", err)
+		}
+		log.Printf("Recorded %d opening balances", count)
+	}
+
+	discrepancies, err := repo.GetStockDiscrepancies(ctx)
+	if err != nil {
+		log.Fatalf("Reconcile failed: %v", err)
+	}
+	for _, d := range discrepancies {
+		switch {
+		case d.VariantID == 0:
+			log.Printf("Product %d: total_stock %d, ledger %d", d.ProductID, d.Stock, d.Ledger)
+		case d.WarehouseID != 0:
+			log.Printf("Product %d variant %d warehouse %d: stock %d, ledger %d", d.ProductID, d.VariantID, d.WarehouseID, d.Stock, d.Ledger)
+		default:
+			log.Printf("Product %d variant %d: stock %d, ledger %d", d.ProductID, d.VariantID, d.Stock, d.Ledger)
+		}
+	}
+
+	if len(discrepancies) > 0 {
+		log.Printf("%d mismatches found", len(discrepancies))
+		os.Exit(1)
+	}
+	log.Println("Stock matches the ledger")
+}
@@ -4,15 +4,25 @@ import (
 	"go-ecommerce/internal/config"
 	"go-ecommerce/internal/middleware"
 	"go-ecommerce/internal/modules/brand"
+	"go-ecommerce/internal/modules/cart"
 	"go-ecommerce/internal/modules/category"
+	"go-ecommerce/internal/modules/cod"
+	"go-ecommerce/internal/modules/currency"
+	"go-ecommerce/internal/modules/order"
+	"go-ecommerce/internal/modules/payment"
 	"go-ecommerce/internal/modules/product"
+	"go-ecommerce/internal/modules/purchasing"
+	"go-ecommerce/internal/modules/question"
+	"go-ecommerce/internal/modules/review"
 	"go-ecommerce/internal/modules/user"
+	"go-ecommerce/internal/modules/warehouse"
+	"go-ecommerce/internal/modules/wishlist"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
-func SetupRouter(cfg *config.Config, logger *zap.Logger, userHandler *user.Handler, categoryHandler *category.Handler, brandHandler *brand.Handler, productHandler *product.Handler) *gin.Engine {
+func SetupRouter(cfg *config.Config, logger *zap.Logger, userHandler *user.Handler, categoryHandler *category.Handler, brandHandler *brand.Handler, productHandler *product.Handler, reviewHandler *review.Handler, questionHandler *question.Handler, wishlistHandler *wishlist.Handler, currencyHandler *currency.Handler, cartHandler *cart.Handler, orderHandler *order.Handler, warehouseHandler *warehouse.Handler, purchasingHandler *purchasing.Handler, paymentHandler *payment.Handler, codHandler *cod.Handler) *gin.Engine {
 	r := gin.Default()
 
 	// 1. Global Middlewares
@@ -31,6 +41,33 @@ func SetupRouter(cfg *config.Config, logger *zap.Logger, userHandler *user.Handl
 			auth.POST("/logout", userHandler.Logout)
 		}
 
+		// Storefront (chỉ thấy sản phẩm đã đăng bán)
+		api.GET("/products", productHandler.ListPublished)
+		api.GET("/products/:slug", productHandler.GetBySlug)
+		api.GET("/categories/tree", categoryHandler.GetTree)
+		api.GET("/categories/:slug", categoryHandler.GetBySlug)
+		api.GET("/categories/:slug/breadcrumbs", categoryHandler.GetBreadcrumbsBySlug)
+		api.GET("/brands/:slug", brandHandler.GetBySlug)
+		api.GET("/reviews", reviewHandler.GetProductReviews)
+		api.GET("/questions", questionHandler.GetByProduct)
+		api.GET("/currencies", currencyHandler.GetAll)
+
+		// Payment gateway callbacks (xác thực bằng chữ ký của cổng thanh toán)
+		api.GET("/payments/:gateway/return", paymentHandler.Return)
+		api.GET("/payments/:gateway/ipn", paymentHandler.IPN)
+		api.POST("/payments/:gateway/ipn", paymentHandler.IPN)
+		api.GET("/cod/quote", codHandler.Quote)
+
+		// Cart (khách dùng header X-Cart-Token, user đã đăng nhập dùng giỏ của mình)
+		carts := api.Group("/cart")
+		carts.Use(middleware.OptionalAuth(cfg))
+		{
+			carts.GET("", cartHandler.Get)
+			carts.POST("/items", cartHandler.AddItem)
+			carts.PUT("/items/:id", cartHandler.UpdateItem)
+			carts.DELETE("/items/:id", cartHandler.RemoveItem)
+		}
+
 		// PRIVATE ROUTES (Phải đăng nhập)
 		// Tạo một nhóm route có bảo vệ
 		protected := api.Group("/")
@@ -39,6 +76,36 @@ func SetupRouter(cfg *config.Config, logger *zap.Logger, userHandler *user.Handl
 			// Lấy thông tin cá nhân
 			protected.GET("/me", userHandler.GetProfile)
 
+			// Address book
+			protected.GET("/me/addresses", userHandler.GetAddresses)
+			protected.POST("/me/addresses", userHandler.CreateAddress)
+			protected.PUT("/me/addresses/:id", userHandler.UpdateAddress)
+			protected.DELETE("/me/addresses/:id", userHandler.DeleteAddress)
+
+			// Wishlist
+			protected.GET("/me/wishlist", wishlistHandler.GetMine)
+			protected.POST("/me/wishlist", wishlistHandler.Add)
+			protected.GET("/me/wishlist/preferences", wishlistHandler.GetPreference)
+			protected.PUT("/me/wishlist/preferences", wishlistHandler.UpdatePreference)
+			protected.DELETE("/me/wishlist/:id", wishlistHandler.Remove)
+
+			// Orders
+			protected.POST("/orders/checkout", orderHandler.Checkout)
+			protected.GET("/orders", orderHandler.GetMine)
+			protected.GET("/orders/:id", orderHandler.GetMineByID)
+			protected.POST("/orders/:id/cancel", orderHandler.Cancel)
+			protected.POST("/orders/:id/payments", paymentHandler.Create)
+			protected.GET("/orders/:id/payments", paymentHandler.GetMine)
+
+			// Reviews
+			protected.POST("/reviews", reviewHandler.Create)
+			protected.POST("/reviews/:id/helpful", reviewHandler.MarkHelpful)
+			protected.DELETE("/reviews/:id/helpful", reviewHandler.UnmarkHelpful)
+
+			// Questions & answers
+			protected.POST("/questions", questionHandler.Ask)
+			protected.POST("/questions/:id/answers", questionHandler.Answer)
+
 			// ADMIN ROUTES (Phải đăng nhập + Là Admin)
 			admin := protected.Group("/admin")
 			admin.Use(middleware.RequireRole("admin"))
@@ -46,23 +113,111 @@ func SetupRouter(cfg *config.Config, logger *zap.Logger, userHandler *user.Handl
 				// Category CRUD
 				admin.POST("/categories", categoryHandler.Create)
 				admin.GET("/categories", categoryHandler.GetAll)
+				admin.GET("/categories/tree", categoryHandler.GetTree)
+				admin.GET("/categories/trash", categoryHandler.GetTrash)
 				admin.GET("/categories/:id", categoryHandler.GetByID)
+				admin.GET("/categories/:id/breadcrumbs", categoryHandler.GetBreadcrumbs)
 				admin.PUT("/categories/:id", categoryHandler.Update)
+				admin.PUT("/categories/:id/move", categoryHandler.Move)
 				admin.DELETE("/categories/:id", categoryHandler.Delete)
+				admin.POST("/categories/:id/restore", categoryHandler.Restore)
+				admin.DELETE("/categories/:id/purge", categoryHandler.Purge)
 
 				// Brand CRUD
 				admin.POST("/brands", brandHandler.Create)
 				admin.GET("/brands", brandHandler.GetAll)
+				admin.GET("/brands/trash", brandHandler.GetTrash)
 				admin.GET("/brands/:id", brandHandler.GetByID)
 				admin.PUT("/brands/:id", brandHandler.Update)
 				admin.DELETE("/brands/:id", brandHandler.Delete)
+				admin.POST("/brands/:id/restore", brandHandler.Restore)
+				admin.DELETE("/brands/:id/purge", brandHandler.Purge)
 
 				// Product CRUD
 				admin.POST("/products", productHandler.Create)
 				admin.GET("/products", productHandler.GetAll)
+				admin.GET("/products/trash", productHandler.GetTrash)
+				admin.GET("/products/export", productHandler.Export)
+				admin.GET("/products/low-stock", productHandler.GetLowStock)
+				admin.POST("/products/import", productHandler.Import)
+				admin.GET("/products/import/:jobId", productHandler.GetImportJob)
 				admin.GET("/products/:id", productHandler.GetByID)
 				admin.PUT("/products/:id", productHandler.Update)
 				admin.DELETE("/products/:id", productHandler.Delete)
+				admin.POST("/products/:id/restore", productHandler.Restore)
+				admin.DELETE("/products/:id/purge", productHandler.Purge)
+				admin.GET("/products/:id/readiness", productHandler.CheckReadiness)
+				admin.POST("/products/:id/publish", productHandler.Publish)
+				admin.POST("/products/:id/unpublish", productHandler.Unpublish)
+				admin.POST("/products/:id/archive", productHandler.Archive)
+				admin.PUT("/products/:id/schedule", productHandler.Schedule)
+				admin.POST("/products/:id/variants", productHandler.AddVariant)
+				admin.POST("/products/:id/variants/generate", productHandler.GenerateVariants)
+				admin.PUT("/products/:id/variants/:variantId", productHandler.UpdateVariant)
+				admin.DELETE("/products/:id/variants/:variantId", productHandler.DeleteVariant)
+				admin.PUT("/products/:id/variants/:variantId/sale", productHandler.SetSale)
+				admin.DELETE("/products/:id/variants/:variantId/sale", productHandler.ClearSale)
+				admin.PUT("/products/:id/variants/:variantId/backorder", productHandler.SetBackorder)
+				admin.GET("/products/:id/variants/:variantId/price-history", productHandler.GetPriceHistory)
+				admin.GET("/products/:id/variants/:variantId/stock-movements", productHandler.GetStockMovements)
+				admin.POST("/products/:id/variants/:variantId/stock-movements", productHandler.MoveStock)
+				admin.GET("/products/:id/variants/:variantId/stock", productHandler.GetStockLevels)
+				admin.POST("/products/:id/variants/:variantId/transfers", productHandler.TransferStock)
+				admin.POST("/products/:id/images", productHandler.AddImages)
+				admin.PUT("/products/:id/images/order", productHandler.ReorderImages)
+				admin.PUT("/products/:id/images/:imageId", productHandler.ReplaceImage)
+				admin.DELETE("/products/:id/images/:imageId", productHandler.DeleteImage)
+
+				// Warehouses
+				admin.GET("/warehouses", warehouseHandler.GetAll)
+				admin.POST("/warehouses", warehouseHandler.Create)
+				admin.PUT("/warehouses/:id", warehouseHandler.Update)
+
+				// Purchasing
+				admin.GET("/suppliers", purchasingHandler.GetSuppliers)
+				admin.POST("/suppliers", purchasingHandler.CreateSupplier)
+				admin.PUT("/suppliers/:id", purchasingHandler.UpdateSupplier)
+				admin.GET("/purchase-orders", purchasingHandler.GetAll)
+				admin.POST("/purchase-orders", purchasingHandler.Create)
+				admin.GET("/purchase-orders/:id", purchasingHandler.GetByID)
+				admin.PUT("/purchase-orders/:id", purchasingHandler.Update)
+				admin.POST("/purchase-orders/:id/send", purchasingHandler.Send)
+				admin.POST("/purchase-orders/:id/receipts", purchasingHandler.Receive)
+				admin.POST("/purchase-orders/:id/close", purchasingHandler.Close)
+
+				// Orders
+				admin.GET("/orders", orderHandler.GetAll)
+				admin.GET("/orders/:id", orderHandler.GetByID)
+				admin.PUT("/orders/:id/status", orderHandler.UpdateStatus)
+				admin.GET("/orders/:id/payments", paymentHandler.GetByOrder)
+
+				// Cash on delivery
+				admin.GET("/cod/rules", codHandler.GetRules)
+				admin.POST("/cod/rules", codHandler.CreateRule)
+				admin.PUT("/cod/rules/:id", codHandler.UpdateRule)
+				admin.DELETE("/cod/rules/:id", codHandler.DeleteRule)
+				admin.GET("/cod/remittances", codHandler.GetRemittances)
+				admin.POST("/cod/remittances", codHandler.ImportRemittance)
+				admin.GET("/cod/remittances/:id", codHandler.GetRemittance)
+				admin.GET("/cod/mismatches", codHandler.GetMismatches)
+
+				// Review moderation
+				admin.GET("/reviews", reviewHandler.GetAll)
+				admin.PUT("/reviews/:id/approve", reviewHandler.Approve)
+				admin.PUT("/reviews/:id/hide", reviewHandler.Hide)
+				admin.PUT("/reviews/:id/reply", reviewHandler.Reply)
+
+				// Question moderation
+				admin.GET("/questions", questionHandler.GetAll)
+				admin.PUT("/questions/:id/approve", questionHandler.Approve)
+				admin.PUT("/questions/:id/hide", questionHandler.Hide)
+				admin.DELETE("/answers/:id", questionHandler.DeleteAnswer)
+
+				// Exchange rates
+				admin.GET("/currencies", currencyHandler.GetAll)
+				admin.POST("/currencies/import", currencyHandler.Import)
+				admin.PUT("/currencies/:code", currencyHandler.SetRate)
+				admin.DELETE("/currencies/:code", currencyHandler.DeleteRate)
 			}
 		}
 	}
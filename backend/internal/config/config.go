@@ -10,6 +10,12 @@ type Config struct {
 	Database   DatabaseConfig
 	JWT        JWTConfig
 	Cloudinary CloudinaryConfig
+	Scheduler  SchedulerConfig
+	Mail       MailConfig
+	Currency   CurrencyConfig
+	Order      OrderConfig
+	Notify     NotifyConfig
+	Payment    PaymentConfig
 }
 type JWTConfig struct {
 	Secret            string
@@ -31,6 +37,65 @@ type CloudinaryConfig struct {
 	APISecret string
 }
 
+// SchedulerConfig controls background jobs
+type SchedulerConfig struct {
+	Interval       time.Duration // How often scheduled publish/unpublish is processed
+	TrashRetention time.Duration // How long soft-deleted records can be restored
+	PurgeInterval  time.Duration // How often expired trash is purged
+	AlertInterval  time.Duration // How often wishlist back-in-stock/price-drop alerts are checked
+
+	LowStockInterval time.Duration // How often variants at their reorder threshold are reported
+}
+
+// MailConfig configures outgoing email; without Host mails are only logged
+type MailConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// CurrencyConfig configures display currencies
+type CurrencyConfig struct {
+	RatesFile string // Optional CSV of exchange rates loaded at startup
+}
+
+// OrderConfig configures checkout
+type OrderConfig struct {
+	ReservationTTL time.Duration // How long stock is held for an unconfirmed (unpaid) order
+}
+
+// NotifyConfig selects where staff alerts (e.g. low stock) are sent
+type NotifyConfig struct {
+	Channel    string // email, webhook or log (default)
+	Email      string // Recipient for the email channel
+	WebhookURL string // Endpoint for the webhook channel
+}
+
+// PaymentConfig configures online payment gateways
+// A gateway is enabled when its credentials are set.
+type PaymentConfig struct {
+	PublicURL string // Base URL of this API as the gateways reach it, for return and IPN callbacks
+	ResultURL string // Storefront page customers land on after paying; JSON is returned without it
+
+	VNPay VNPayConfig
+	MoMo  MoMoConfig
+}
+
+type VNPayConfig struct {
+	TmnCode    string
+	HashSecret string
+	URL        string // Payment page, the sandbox by default
+}
+
+type MoMoConfig struct {
+	PartnerCode string
+	AccessKey   string
+	SecretKey   string
+	Endpoint    string // API base URL, the test environment by default
+}
+
 // LoadConfig đọc file .env và map vào struct
 func LoadConfig() (*Config, error) {
 	viper.SetConfigFile(".env")
@@ -53,10 +118,10 @@ func LoadConfig() (*Config, error) {
 	cfg.JWT.RefreshExpiration = viper.GetDuration("JWT_REFRESH_EXPIRATION")
 
 	// Set defaults if not specified
-	if cfg.JWT.AccessExpiration == 0 {
+	if cfg.JWT.AccessExpiration <= 0 {
 		cfg.JWT.AccessExpiration = 15 * time.Minute
 	}
-	if cfg.JWT.RefreshExpiration == 0 {
+	if cfg.JWT.RefreshExpiration <= 0 {
 		cfg.JWT.RefreshExpiration = 7 * 24 * time.Hour
 	}
 
@@ -65,5 +130,71 @@ func LoadConfig() (*Config, error) {
 	cfg.Cloudinary.APIKey = viper.GetString("CLOUDINARY_API_KEY")
 	cfg.Cloudinary.APISecret = viper.GetString("CLOUDINARY_API_SECRET")
 
+	// Scheduler
+	cfg.Scheduler.Interval = viper.GetDuration("SCHEDULER_INTERVAL")
+	if cfg.Scheduler.Interval <= 0 {
+		cfg.Scheduler.Interval = time.Minute
+	}
+	cfg.Scheduler.TrashRetention = viper.GetDuration("TRASH_RETENTION")
+	if cfg.Scheduler.TrashRetention <= 0 {
+		cfg.Scheduler.TrashRetention = 30 * 24 * time.Hour
+	}
+	cfg.Scheduler.PurgeInterval = viper.GetDuration("PURGE_INTERVAL")
+	if cfg.Scheduler.PurgeInterval <= 0 {
+		cfg.Scheduler.PurgeInterval = time.Hour
+	}
+	cfg.Scheduler.AlertInterval = viper.GetDuration("ALERT_INTERVAL")
+	if cfg.Scheduler.AlertInterval <= 0 {
+		cfg.Scheduler.AlertInterval = 5 * time.Minute
+	}
+	cfg.Scheduler.LowStockInterval = viper.GetDuration("LOW_STOCK_INTERVAL")
+	if cfg.Scheduler.LowStockInterval <= 0 {
+		cfg.Scheduler.LowStockInterval = 24 * time.Hour
+	}
+
+	// Mail
+	cfg.Mail.Host = viper.GetString("MAIL_HOST")
+	cfg.Mail.Port = viper.GetInt("MAIL_PORT")
+	if cfg.Mail.Port == 0 {
+		cfg.Mail.Port = 587
+	}
+	cfg.Mail.Username = viper.GetString("MAIL_USERNAME")
+	cfg.Mail.Password = viper.GetString("MAIL_PASSWORD")
+	cfg.Mail.From = viper.GetString("MAIL_FROM")
+
+	// Currency
+	cfg.Currency.RatesFile = viper.GetString("CURRENCY_RATES_FILE")
+
+	// Order
+	cfg.Order.ReservationTTL = viper.GetDuration("RESERVATION_TTL")
+	if cfg.Order.ReservationTTL <= 0 {
+		cfg.Order.ReservationTTL = 30 * time.Minute
+	}
+
+	// Staff alerts
+	cfg.Notify.Channel = viper.GetString("NOTIFY_CHANNEL")
+	cfg.Notify.Email = viper.GetString("NOTIFY_EMAIL")
+	cfg.Notify.WebhookURL = viper.GetString("NOTIFY_WEBHOOK_URL")
+
+	// Payment
+	cfg.Payment.PublicURL = viper.GetString("PAYMENT_PUBLIC_URL")
+	if cfg.Payment.PublicURL == "" {
+		cfg.Payment.PublicURL = "http://localhost:8080"
+	}
+	cfg.Payment.ResultURL = viper.GetString("PAYMENT_RESULT_URL")
+	cfg.Payment.VNPay.TmnCode = viper.GetString("VNPAY_TMN_CODE")
+	cfg.Payment.VNPay.HashSecret = viper.GetString("VNPAY_HASH_SECRET")
+	cfg.Payment.VNPay.URL = viper.GetString("VNPAY_URL")
+	if cfg.Payment.VNPay.URL == "" {
+		cfg.Payment.VNPay.URL = "https://sandbox.vnpayment.vn/paymentv2/vpcpay.html"
+	}
+	cfg.Payment.MoMo.PartnerCode = viper.GetString("MOMO_PARTNER_CODE")
+	cfg.Payment.MoMo.AccessKey = viper.GetString("MOMO_ACCESS_KEY")
+	cfg.Payment.MoMo.SecretKey = viper.GetString("MOMO_SECRET_KEY")
+	cfg.Payment.MoMo.Endpoint = viper.GetString("MOMO_ENDPOINT")
+	if cfg.Payment.MoMo.Endpoint == "" {
+		cfg.Payment.MoMo.Endpoint = "https://test-payment.momo.vn"
+	}
+
 	return &cfg, nil
 }
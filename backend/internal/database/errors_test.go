@@ -0,0 +1,30 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestIsUniqueViolation(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "same index", err: &pgconn.PgError{Code: "23505", ConstraintName: "idx_reviews_purchase_id"}, want: true},
+		{name: "wrapped", err: fmt.Errorf("insert: %w", &pgconn.PgError{Code: "23505", ConstraintName: "idx_reviews_purchase_id"}), want: true},
+		{name: "other index", err: &pgconn.PgError{Code: "23505", ConstraintName: "reviews_pkey"}},
+		{name: "other error", err: &pgconn.PgError{Code: "23503", ConstraintName: "idx_reviews_purchase_id"}},
+		{name: "not postgres", err: fmt.Errorf("connection refused")},
+		{name: "nil"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsUniqueViolation(tt.err, "idx_reviews_purchase_id"); got != tt.want {
+				t.Errorf("IsUniqueViolation = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
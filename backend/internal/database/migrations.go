@@ -0,0 +1,46 @@
+package database
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// moneyColumns were stored as decimal before prices became money.Money
+var moneyColumns = []struct{ table, column string }{
+	{"product_variants", "price"},
+	{"product_variants", "compare_at_price"},
+	{"product_variants", "sale_price"},
+	{"product_price_histories", "price"},
+	{"product_price_histories", "regular_price"},
+	{"product_price_histories", "old_price"},
+	{"wishlist_items", "last_price"},
+}
+
+// ConvertMoneyColumns turns decimal price columns into bigint minor units
+// Must run before AutoMigrate. Prices are VND, which has no minor digits, so
+// values are rounded to whole đồng. Columns that are already bigint or do not
+// exist yet are skipped, so it is safe to run on every start.
+func ConvertMoneyColumns(db *gorm.DB) error {
+	for _, c := range moneyColumns {
+		var dataType string
+		err := db.Raw(
+			"SELECT data_type FROM information_schema.columns WHERE table_schema = CURRENT_SCHEMA() AND table_name = ? AND column_name = ?",
+			c.table, c.column,
+		).Scan(&dataType).Error
+		if err != nil {
+			return err
+		}
+		if dataType != "numeric" && dataType != "double precision" && dataType != "real" {
+			continue
+		}
+
+		sql := fmt.Sprintf("ALTER TABLE %q ALTER COLUMN %q TYPE bigint USING ROUND(%q)::bigint", c.table, c.column, c.column)
+		if err := db.Exec(sql).Error; err != nil {
+			return fmt.Errorf("convert %s.%s: %w", c.table, c.column, err)
+		}
+		log.Printf("Converted %s.%s to bigint", c.table, c.column)
+	}
+	return nil
+}
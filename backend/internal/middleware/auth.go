@@ -20,39 +20,63 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 			return
 		}
 
-		// Tách chữ "Bearer " ra
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		if authenticate(c, cfg, authHeader) {
+			c.Next() // Cho phép đi tiếp
+		}
+	}
+}
+
+// OptionalAuth cho phép khách chưa đăng nhập đi tiếp (ví dụ giỏ hàng của khách)
+// Nếu có header Authorization thì token vẫn phải hợp lệ.
+func OptionalAuth(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.Next()
 			return
 		}
 
-		tokenString := parts[1]
+		if authenticate(c, cfg, authHeader) {
+			c.Next()
+		}
+	}
+}
+
+// authenticate kiểm tra token và lưu userID, role vào context; abort nếu không hợp lệ
+func authenticate(c *gin.Context, cfg *config.Config, authHeader string) bool {
+	// Tách chữ "Bearer " ra
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		return false
+	}
 
-		// 2. Parse và Validate Token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Kiểm tra thuật toán ký (thường là HMAC)
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			// Trả về Secret Key (lấy từ Config)
-			return []byte(cfg.JWT.Secret), nil
-		})
+	tokenString := parts[1]
 
-		if err != nil || !token.Valid {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
-			return
+	// 2. Parse và Validate Token
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// Kiểm tra thuật toán ký (thường là HMAC)
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
 		}
+		// Trả về Secret Key (lấy từ Config)
+		return []byte(cfg.JWT.Secret), nil
+	})
 
-		// 3. Lấy thông tin user từ Claims (payload của token)
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			// Lưu user_id và role vào context để các handler phía sau dùng lại
-			c.Set("userID", claims["sub"]) // "sub" thường dùng lưu ID
-			c.Set("role", claims["role"])
+	if err != nil || !token.Valid {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+		return false
+	}
 
-			c.Next() // Cho phép đi tiếp
-		} else {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-		}
+	// 3. Lấy thông tin user từ Claims (payload của token)
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
+		return false
 	}
+
+	// Lưu user_id và role vào context để các handler phía sau dùng lại
+	c.Set("userID", claims["sub"]) // "sub" thường dùng lưu ID
+	c.Set("role", claims["role"])
+	return true
 }
@@ -24,10 +24,11 @@ func CorsConfig() gin.HandlerFunc {
 
 		// 3. Các Header được phép gửi lên
 		// "Authorization" là bắt buộc để gửi JWT Token
-		AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		// "X-Cart-Token" định danh giỏ hàng của khách, "Accept-Currency" chọn tiền tệ hiển thị
+		AllowHeaders: []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Cart-Token", "Accept-Currency"},
 
 		// 4. Các Header mà Frontend được phép đọc từ Response
-		ExposeHeaders: []string{"Content-Length"},
+		ExposeHeaders: []string{"Content-Length", "X-Cart-Token"},
 
 		// 5. Cho phép gửi Cookie/Credentials (nếu sau này bạn dùng Cookie)
 		AllowCredentials: true,
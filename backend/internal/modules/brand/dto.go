@@ -16,17 +16,23 @@ type UpdateBrandRequest struct {
 
 // BrandResponse - Response DTO
 type BrandResponse struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"`
-	Slug        string    `json:"slug"`
-	Description string    `json:"description"`
-	LogoURL     string    `json:"logo_url"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Slug        string     `json:"slug"`
+	Description string     `json:"description"`
+	LogoURL     string     `json:"logo_url"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
 
 // ToBrandResponse converts entity to response DTO
 func ToBrandResponse(b *Brand) *BrandResponse {
+	var deletedAt *time.Time
+	if b.DeletedAt.Valid {
+		deletedAt = &b.DeletedAt.Time
+	}
+
 	return &BrandResponse{
 		ID:          b.ID,
 		Name:        b.Name,
@@ -35,5 +41,6 @@ func ToBrandResponse(b *Brand) *BrandResponse {
 		LogoURL:     b.LogoURL,
 		CreatedAt:   b.CreatedAt,
 		UpdatedAt:   b.UpdatedAt,
+		DeletedAt:   deletedAt,
 	}
 }
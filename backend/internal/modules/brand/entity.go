@@ -1,17 +1,22 @@
 package brand
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // Brand entity
 type Brand struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	Name         string    `gorm:"type:text;not null" json:"name"`
-	Slug         string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"slug"`
-	Description  string    `gorm:"type:text" json:"description"`
-	LogoURL      string    `gorm:"type:text" json:"logo_url"`
-	LogoPublicID string    `gorm:"type:varchar(255)" json:"-"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	Name         string         `gorm:"type:text;not null" json:"name"`
+	Slug         string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"slug"`
+	Description  string         `gorm:"type:text" json:"description"`
+	LogoURL      string         `gorm:"type:text" json:"logo_url"`
+	LogoPublicID string         `gorm:"type:varchar(255)" json:"-"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete (trash)
 }
 
 func (Brand) TableName() string {
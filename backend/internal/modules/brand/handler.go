@@ -153,3 +153,78 @@ func (h *Handler) Delete(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Xóa thương hiệu thành công"})
 }
+
+// GetTrash handles GET /admin/brands/trash
+func (h *Handler) GetTrash(c *gin.Context) {
+	res, err := h.service.GetTrash(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy thùng rác"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// Restore handles POST /admin/brands/:id/restore
+func (h *Handler) Restore(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	res, err := h.service.Restore(c.Request.Context(), uint(id))
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy thương hiệu trong thùng rác"})
+		case errors.ErrRestoreExpired:
+			c.JSON(http.StatusGone, gin.H{"error": "Đã quá thời gian cho phép khôi phục"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Khôi phục thành công",
+		"data":    res,
+	})
+}
+
+// Purge handles DELETE /admin/brands/:id/purge
+func (h *Handler) Purge(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	err = h.service.Purge(c.Request.Context(), uint(id))
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy thương hiệu trong thùng rác"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Xóa vĩnh viễn thành công"})
+}
+
+// GetBySlug handles GET /brands/:slug (storefront), 301 for old slugs
+func (h *Handler) GetBySlug(c *gin.Context) {
+	res, newSlug, err := h.service.GetBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy thương hiệu"})
+		return
+	}
+	if newSlug != "" {
+		c.Header("Location", "/api/v1/brands/"+newSlug)
+		c.JSON(http.StatusMovedPermanently, gin.H{"redirect_to": newSlug})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
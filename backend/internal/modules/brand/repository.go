@@ -2,6 +2,7 @@ package brand
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -10,9 +11,21 @@ import (
 type Repository interface {
 	Create(ctx context.Context, brand *Brand) error
 	GetByID(ctx context.Context, id uint) (*Brand, error)
+	GetBySlug(ctx context.Context, slug string) (*Brand, error)
+	GetByName(ctx context.Context, name string) ([]Brand, error) // Case-insensitive, names may repeat
 	GetAll(ctx context.Context) ([]Brand, error)
 	Update(ctx context.Context, brand *Brand) error
 	Delete(ctx context.Context, id uint) error
+
+	// Trash operations (soft-deleted brands)
+	GetTrashed(ctx context.Context) ([]Brand, error)
+	GetTrashedByID(ctx context.Context, id uint) (*Brand, error)
+	GetTrashedBefore(ctx context.Context, before time.Time) ([]Brand, error)
+	Restore(ctx context.Context, id uint) error
+	HardDelete(ctx context.Context, id uint) error
+
+	// Transaction support
+	WithTransaction(fn func(*gorm.DB) error) error
 }
 
 type repository struct {
@@ -24,6 +37,11 @@ func NewRepository(db *gorm.DB) Repository {
 	return &repository{db: db}
 }
 
+// WithTransaction executes a function within a transaction
+func (r *repository) WithTransaction(fn func(*gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
 func (r *repository) Create(ctx context.Context, brand *Brand) error {
 	return r.db.WithContext(ctx).Create(brand).Error
 }
@@ -37,6 +55,21 @@ func (r *repository) GetByID(ctx context.Context, id uint) (*Brand, error) {
 	return &brand, nil
 }
 
+func (r *repository) GetBySlug(ctx context.Context, slug string) (*Brand, error) {
+	var brand Brand
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&brand).Error
+	if err != nil {
+		return nil, err
+	}
+	return &brand, nil
+}
+
+func (r *repository) GetByName(ctx context.Context, name string) ([]Brand, error) {
+	var brands []Brand
+	err := r.db.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name).Find(&brands).Error
+	return brands, err
+}
+
 func (r *repository) GetAll(ctx context.Context) ([]Brand, error) {
 	var brands []Brand
 	err := r.db.WithContext(ctx).Find(&brands).Error
@@ -53,3 +86,42 @@ func (r *repository) Update(ctx context.Context, brand *Brand) error {
 func (r *repository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&Brand{}, id).Error
 }
+
+func (r *repository) GetTrashed(ctx context.Context) ([]Brand, error) {
+	var items []Brand
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *repository) GetTrashedByID(ctx context.Context, id uint) (*Brand, error) {
+	var brand Brand
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL").
+		First(&brand, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &brand, nil
+}
+
+// GetTrashedBefore returns records deleted before the given time (retention expired)
+func (r *repository) GetTrashedBefore(ctx context.Context, before time.Time) ([]Brand, error) {
+	var items []Brand
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Find(&items).Error
+	return items, err
+}
+
+func (r *repository) Restore(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&Brand{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
+}
+
+func (r *repository) HardDelete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&Brand{}, id).Error
+}
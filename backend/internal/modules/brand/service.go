@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"mime/multipart"
-	"regexp"
-	"strings"
+	"time"
 
 	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/internal/shared/slug"
 	"go-ecommerce/pkg/cloudinary"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ProductChecker interface for checking product existence
@@ -16,17 +19,6 @@ type ProductChecker interface {
 	HasProductsWithBrand(ctx context.Context, brandID uint) (bool, error)
 }
 
-// generateSlug creates a URL-friendly slug from name
-func generateSlug(name string) string {
-	slug := strings.ToLower(strings.TrimSpace(name))
-	slug = strings.ReplaceAll(slug, " ", "-")
-	reg := regexp.MustCompile(`[^a-z0-9-]+`)
-	slug = reg.ReplaceAllString(slug, "")
-	reg = regexp.MustCompile(`-+`)
-	slug = reg.ReplaceAllString(slug, "-")
-	return strings.Trim(slug, "-")
-}
-
 // Service interface
 type Service interface {
 	Create(ctx context.Context, req CreateBrandRequest, logo multipart.File) (*BrandResponse, error)
@@ -34,23 +26,32 @@ type Service interface {
 	GetAll(ctx context.Context) ([]BrandResponse, error)
 	Update(ctx context.Context, id uint, req UpdateBrandRequest, logo multipart.File) (*BrandResponse, error)
 	Delete(ctx context.Context, id uint) error
+	GetBySlug(ctx context.Context, brandSlug string) (*BrandResponse, string, error)
+
+	// Trash
+	GetTrash(ctx context.Context) ([]BrandResponse, error)
+	Restore(ctx context.Context, id uint) (*BrandResponse, error)
+	Purge(ctx context.Context, id uint) error
+	PurgeExpired(ctx context.Context) error
 }
 
 type service struct {
 	repo           Repository
 	cloudinary     *cloudinary.Client
 	productChecker ProductChecker
+	slugs          *slug.Registry
+	trashRetention time.Duration
 }
 
 // NewService creates a new brand service
-func NewService(repo Repository, cloudinary *cloudinary.Client, productChecker ProductChecker) Service {
-	return &service{repo: repo, cloudinary: cloudinary, productChecker: productChecker}
+// Deleted brands stay restorable in trash for trashRetention.
+func NewService(repo Repository, cloudinary *cloudinary.Client, productChecker ProductChecker, slugs *slug.Registry, trashRetention time.Duration) Service {
+	return &service{repo: repo, cloudinary: cloudinary, productChecker: productChecker, slugs: slugs, trashRetention: trashRetention}
 }
 
 func (s *service) Create(ctx context.Context, req CreateBrandRequest, logo multipart.File) (*BrandResponse, error) {
 	brand := &Brand{
 		Name:        req.Name,
-		Slug:        generateSlug(req.Name),
 		Description: req.Description,
 	}
 
@@ -64,7 +65,16 @@ func (s *service) Create(ctx context.Context, req CreateBrandRequest, logo multi
 		brand.LogoPublicID = result.PublicID
 	}
 
-	if err := s.repo.Create(ctx, brand); err != nil {
+	err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		brandSlug, err := s.slugs.Unique(tx, slug.EntityBrand, slug.Make(req.Name), 0)
+		if err != nil {
+			return err
+		}
+		brand.Slug = brandSlug
+		return tx.Create(brand).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -98,10 +108,6 @@ func (s *service) Update(ctx context.Context, id uint, req UpdateBrandRequest, l
 		return nil, errors.ErrRecordNotFound
 	}
 
-	if req.Name != "" {
-		brand.Name = req.Name
-		brand.Slug = generateSlug(req.Name)
-	}
 	if req.Description != "" {
 		brand.Description = req.Description
 	}
@@ -120,15 +126,32 @@ func (s *service) Update(ctx context.Context, id uint, req UpdateBrandRequest, l
 		brand.LogoPublicID = result.PublicID
 	}
 
-	if err := s.repo.Update(ctx, brand); err != nil {
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		if req.Name != "" && req.Name != brand.Name {
+			// Renaming changes the URL; the old slug is kept for redirects
+			newSlug, err := s.slugs.Unique(tx, slug.EntityBrand, slug.Make(req.Name), brand.ID)
+			if err != nil {
+				return err
+			}
+			if err := s.slugs.Rename(tx, slug.EntityBrand, brand.ID, brand.Slug, newSlug); err != nil {
+				return err
+			}
+			brand.Name = req.Name
+			brand.Slug = newSlug
+		}
+		return tx.Omit(clause.Associations).Save(brand).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return ToBrandResponse(brand), nil
 }
 
+// Delete moves a brand to trash; the logo is kept until it is purged
 func (s *service) Delete(ctx context.Context, id uint) error {
-	brand, err := s.repo.GetByID(ctx, id)
+	_, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return errors.ErrRecordNotFound
 	}
@@ -144,10 +167,94 @@ func (s *service) Delete(ctx context.Context, id uint) error {
 		}
 	}
 
-	// Delete logo from Cloudinary
+	return s.repo.Delete(ctx, id)
+}
+
+// GetTrash lists soft-deleted brands
+func (s *service) GetTrash(ctx context.Context) ([]BrandResponse, error) {
+	brands, err := s.repo.GetTrashed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []BrandResponse
+	for _, b := range brands {
+		responses = append(responses, *ToBrandResponse(&b))
+	}
+	return responses, nil
+}
+
+// Restore brings a brand back from trash within the retention window
+func (s *service) Restore(ctx context.Context, id uint) (*BrandResponse, error) {
+	brand, err := s.repo.GetTrashedByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	if time.Since(brand.DeletedAt.Time) > s.trashRetention {
+		return nil, errors.ErrRestoreExpired
+	}
+
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Purge permanently deletes a trashed brand and its logo
+func (s *service) Purge(ctx context.Context, id uint) error {
+	brand, err := s.repo.GetTrashedByID(ctx, id)
+	if err != nil {
+		return errors.ErrRecordNotFound
+	}
+	return s.purge(ctx, brand)
+}
+
+// PurgeExpired permanently deletes brands whose retention window has passed
+func (s *service) PurgeExpired(ctx context.Context) error {
+	brands, err := s.repo.GetTrashedBefore(ctx, time.Now().Add(-s.trashRetention))
+	if err != nil {
+		return err
+	}
+
+	for i := range brands {
+		if err := s.purge(ctx, &brands[i]); err != nil {
+			return fmt.Errorf("failed to purge brand %d: %w", brands[i].ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *service) purge(ctx context.Context, brand *Brand) error {
+	if err := s.repo.HardDelete(ctx, brand.ID); err != nil {
+		return err
+	}
+	if err := s.slugs.Forget(ctx, slug.EntityBrand, brand.ID); err != nil {
+		return err
+	}
+
+	// Delete logo from Cloudinary only once the brand is gone for good
 	if brand.LogoPublicID != "" {
 		_ = s.cloudinary.Delete(ctx, brand.LogoPublicID)
 	}
+	return nil
+}
+
+// GetBySlug returns a brand by its current slug
+// For an old slug of a renamed brand it returns the current slug instead.
+func (s *service) GetBySlug(ctx context.Context, brandSlug string) (*BrandResponse, string, error) {
+	brand, err := s.repo.GetBySlug(ctx, brandSlug)
+	if err == nil {
+		return ToBrandResponse(brand), "", nil
+	}
 
-	return s.repo.Delete(ctx, id)
+	id, err := s.slugs.Resolve(ctx, slug.EntityBrand, brandSlug)
+	if err != nil {
+		return nil, "", errors.ErrRecordNotFound
+	}
+	brand, err = s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, "", errors.ErrRecordNotFound
+	}
+	return nil, brand.Slug, nil
 }
@@ -0,0 +1,53 @@
+package cart
+
+import (
+	"context"
+
+	"go-ecommerce/internal/modules/product"
+)
+
+// ProductRepoAdapter adapts product.Repository to ProductGetter
+type ProductRepoAdapter struct {
+	repo product.Repository
+}
+
+func NewProductRepoAdapter(repo product.Repository) *ProductRepoAdapter {
+	return &ProductRepoAdapter{repo: repo}
+}
+
+// GetVariants returns the variants of the given products with effective prices
+// Variants of trashed products are missing from the result.
+func (a *ProductRepoAdapter) GetVariants(ctx context.Context, productIDs []uint) (map[uint]*VariantInfo, error) {
+	products, err := a.repo.GetByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := make(map[uint]*VariantInfo)
+	for _, p := range products {
+		var imageURL string
+		if len(p.Images) > 0 {
+			imageURL = p.Images[0].ImageURL
+		}
+		for i := range p.Variants {
+			v := &p.Variants[i]
+			variants[v.ID] = &VariantInfo{
+				ProductID: p.ID,
+				VariantID: v.ID,
+				Name:      p.Name,
+				Variant:   v.Size,
+				Slug:      p.Slug,
+				SKU:       v.SKU,
+				ImageURL:  imageURL,
+				Price:     v.EffectivePrice(),
+				Stock:     v.Stock,
+				Available: p.Status == product.StatusPublished,
+
+				Backorderable: v.BackorderRoom(),
+				Preorder:      v.BackorderMode == product.BackorderPreorder,
+				AvailableAt:   v.AvailableAt,
+			}
+		}
+	}
+	return variants, nil
+}
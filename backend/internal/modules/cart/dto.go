@@ -0,0 +1,105 @@
+package cart
+
+import (
+	"time"
+
+	"go-ecommerce/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// Owner identifies the cart of a request: a logged-in user or a guest cart token
+type Owner struct {
+	UserID *uuid.UUID
+	Token  string
+}
+
+// AddItemRequest - Request body for adding a variant to the cart
+// Adding a variant already in the cart increases its quantity.
+type AddItemRequest struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	VariantID uint `json:"variant_id" binding:"required"`
+	Quantity  int  `json:"quantity" binding:"required,min=1,max=99"`
+}
+
+// UpdateItemRequest - Request body for changing a line quantity
+type UpdateItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,min=1,max=99"`
+}
+
+// VariantInfo - current state of a variant in the catalog
+type VariantInfo struct {
+	ProductID uint
+	VariantID uint
+	Name      string
+	Variant   string
+	Slug      string
+	SKU       string
+	ImageURL  string
+	Price     money.Money // Effective price
+	Stock     int
+	Available bool // Product published and not deleted
+
+	Backorderable int        // Units that can be ordered beyond Stock
+	Preorder      bool       // Units beyond Stock are pre-orders rather than backorders
+	AvailableAt   *time.Time // Expected availability of units beyond Stock
+}
+
+// Sellable is how many units can be ordered right now
+func (v *VariantInfo) Sellable() int {
+	return v.Stock + v.Backorderable
+}
+
+// Availability values of a cart line
+const (
+	AvailabilityInStock   = "in_stock"
+	AvailabilityBackorder = "backorder" // Part of the line ships when restocked
+	AvailabilityPreorder  = "preorder"  // Part of the line ships when released
+)
+
+// Warning codes
+const (
+	WarningPriceChanged      = "price_changed"
+	WarningUnavailable       = "unavailable"
+	WarningInsufficientStock = "insufficient_stock"
+)
+
+// CartWarning - Something that changed since a line was added
+type CartWarning struct {
+	ItemID  uint   `json:"item_id"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// CartItemResponse - One cart line priced at the current price
+type CartItemResponse struct {
+	ID         uint        `json:"id"`
+	ProductID  uint        `json:"product_id"`
+	VariantID  uint        `json:"variant_id"`
+	Name       string      `json:"name"`
+	Variant    string      `json:"variant"`
+	Slug       string      `json:"slug"`
+	SKU        string      `json:"sku"`
+	ImageURL   string      `json:"image_url"`
+	Quantity   int         `json:"quantity"`
+	UnitPrice  money.Money `json:"unit_price"`
+	AddedPrice money.Money `json:"added_price"`
+	LineTotal  money.Money `json:"line_total"`
+	Stock      int         `json:"stock"`
+	Available  bool        `json:"available"`
+
+	Availability      string     `json:"availability"`
+	BackorderQuantity int        `json:"backorder_quantity,omitempty"` // Units beyond stock
+	AvailableAt       *time.Time `json:"available_at,omitempty"`
+	AvailabilityNote  string     `json:"availability_note,omitempty"` // Label to show next to the line
+}
+
+// CartResponse - Cart with totals recalculated from current prices
+// Unavailable lines are listed but left out of Subtotal.
+type CartResponse struct {
+	Token     string             `json:"token,omitempty"` // Guest carts only, send back as X-Cart-Token
+	Items     []CartItemResponse `json:"items"`
+	ItemCount int                `json:"item_count"`
+	Subtotal  money.Money        `json:"subtotal"`
+	Warnings  []CartWarning      `json:"warnings"`
+}
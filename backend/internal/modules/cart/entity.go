@@ -0,0 +1,43 @@
+package cart
+
+import (
+	"time"
+
+	"go-ecommerce/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// Cart entity - the cart of a user, or a guest cart identified by Token
+// Guest carts have no UserID and are merged into the user's cart on login.
+type Cart struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	UserID    *uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"user_id"`
+	Token     *string    `gorm:"type:varchar(64);uniqueIndex" json:"-"` // Guest carts only
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `gorm:"index" json:"updated_at"`
+
+	Items []CartItem `gorm:"foreignKey:CartID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+}
+
+func (Cart) TableName() string {
+	return "carts"
+}
+
+// CartItem entity - one variant line of a cart
+// AddedPrice is the effective price when the line was added or last changed;
+// the cart warns when the current price differs from it.
+type CartItem struct {
+	ID         uint        `gorm:"primaryKey" json:"id"`
+	CartID     uint        `gorm:"not null;uniqueIndex:idx_cart_variant,priority:1" json:"cart_id"`
+	ProductID  uint        `gorm:"not null;index" json:"product_id"`
+	VariantID  uint        `gorm:"not null;uniqueIndex:idx_cart_variant,priority:2" json:"variant_id"`
+	Quantity   int         `gorm:"not null;check:quantity > 0" json:"quantity"`
+	AddedPrice money.Money `gorm:"not null" json:"added_price"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+func (CartItem) TableName() string {
+	return "cart_items"
+}
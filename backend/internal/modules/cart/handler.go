@@ -0,0 +1,160 @@
+package cart
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// TokenHeader carries the guest cart token in requests and responses
+const TokenHeader = "X-Cart-Token"
+
+// Handler handles cart HTTP requests
+// Routes use OptionalAuth: logged-in users get their own cart, guests the cart of X-Cart-Token.
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new cart handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Get handles GET /cart
+// @Summary Xem giỏ hàng
+// @Description Tổng tiền được tính lại theo giá hiện tại, kèm cảnh báo khi giá hoặc tình trạng hàng thay đổi
+// @Tags Cart
+// @Produce json
+// @Param X-Cart-Token header string false "Token giỏ hàng của khách"
+// @Success 200 {object} CartResponse
+// @Router /cart [get]
+func (h *Handler) Get(c *gin.Context) {
+	owner, ok := cartOwner(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.Get(c.Request.Context(), owner)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy giỏ hàng"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// AddItem handles POST /cart/items
+// @Summary Thêm vào giỏ hàng
+// @Description Khách chưa đăng nhập nhận token giỏ hàng trong header X-Cart-Token của response
+// @Tags Cart
+// @Accept json
+// @Produce json
+// @Param X-Cart-Token header string false "Token giỏ hàng của khách"
+// @Param request body AddItemRequest true "Sản phẩm"
+// @Success 200 {object} CartResponse
+// @Router /cart/items [post]
+func (h *Handler) AddItem(c *gin.Context) {
+	owner, ok := cartOwner(c)
+	if !ok {
+		return
+	}
+
+	var req AddItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.AddItem(c.Request.Context(), owner, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, "Đã thêm vào giỏ hàng", res)
+}
+
+// UpdateItem handles PUT /cart/items/:id
+func (h *Handler) UpdateItem(c *gin.Context) {
+	owner, ok := cartOwner(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	var req UpdateItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.UpdateItem(c.Request.Context(), owner, uint(id), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, "Cập nhật giỏ hàng thành công", res)
+}
+
+// RemoveItem handles DELETE /cart/items/:id
+func (h *Handler) RemoveItem(c *gin.Context) {
+	owner, ok := cartOwner(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	res, err := h.service.RemoveItem(c.Request.Context(), owner, uint(id))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, "Đã xóa khỏi giỏ hàng", res)
+}
+
+func respond(c *gin.Context, message string, res *CartResponse) {
+	if res.Token != "" {
+		c.Header(TokenHeader, res.Token)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    res,
+	})
+}
+
+func respondError(c *gin.Context, err error) {
+	switch err {
+	case errors.ErrRecordNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy sản phẩm trong giỏ hàng hoặc sản phẩm đã ngừng bán"})
+	case errors.ErrInsufficientStock:
+		c.JSON(http.StatusConflict, gin.H{"error": "Số lượng vượt quá tồn kho"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+	}
+}
+
+// cartOwner reads the user set by OptionalAuth, falling back to the guest token
+func cartOwner(c *gin.Context) (Owner, bool) {
+	if userIDStr := c.GetString("userID"); userIDStr != "" {
+		userID, err := uuid.Parse(userIDStr)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+			return Owner{}, false
+		}
+		return Owner{UserID: &userID}, true
+	}
+	return Owner{Token: c.GetHeader(TokenHeader)}, true
+}
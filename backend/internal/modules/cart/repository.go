@@ -0,0 +1,94 @@
+package cart
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Repository interface
+type Repository interface {
+	GetByUser(ctx context.Context, userID uuid.UUID) (*Cart, error)
+	GetByToken(ctx context.Context, token string) (*Cart, error)
+	Create(ctx context.Context, cart *Cart) error
+	Touch(ctx context.Context, cartID uint) error
+	SaveItem(ctx context.Context, item *CartItem) error
+	DeleteItem(ctx context.Context, cartID, itemID uint) (bool, error)
+	DeleteGuestCartsBefore(ctx context.Context, before time.Time) (int64, error)
+
+	// Transaction support
+	WithTransaction(fn func(*gorm.DB) error) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new cart repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// WithTransaction executes a function within a transaction
+func (r *repository) WithTransaction(fn func(*gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
+func preloadItems(db *gorm.DB) *gorm.DB {
+	return db.Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") })
+}
+
+func (r *repository) GetByUser(ctx context.Context, userID uuid.UUID) (*Cart, error) {
+	var cart Cart
+	err := r.db.WithContext(ctx).
+		Scopes(preloadItems).
+		Where("user_id = ?", userID).
+		First(&cart).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+// GetByToken returns a guest cart; carts already merged into a user are not found
+func (r *repository) GetByToken(ctx context.Context, token string) (*Cart, error) {
+	var cart Cart
+	err := r.db.WithContext(ctx).
+		Scopes(preloadItems).
+		Where("token = ? AND user_id IS NULL", token).
+		First(&cart).Error
+	if err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func (r *repository) Create(ctx context.Context, cart *Cart) error {
+	return r.db.WithContext(ctx).Create(cart).Error
+}
+
+// Touch marks the cart as used so guest carts are not purged while active
+func (r *repository) Touch(ctx context.Context, cartID uint) error {
+	return r.db.WithContext(ctx).Model(&Cart{}).
+		Where("id = ?", cartID).
+		Update("updated_at", time.Now()).Error
+}
+
+func (r *repository) SaveItem(ctx context.Context, item *CartItem) error {
+	return r.db.WithContext(ctx).Save(item).Error
+}
+
+func (r *repository) DeleteItem(ctx context.Context, cartID, itemID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("cart_id = ?", cartID).Delete(&CartItem{}, itemID)
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteGuestCartsBefore removes guest carts not used since before
+func (r *repository) DeleteGuestCartsBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("user_id IS NULL AND updated_at < ?", before).
+		Delete(&Cart{})
+	return result.RowsAffected, result.Error
+}
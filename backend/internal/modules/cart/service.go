@@ -0,0 +1,386 @@
+package cart
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/pkg/crypto"
+	"go-ecommerce/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// guestCartRetention is how long an unused guest cart is kept
+const guestCartRetention = 30 * 24 * time.Hour
+
+// ProductGetter interface for current prices and stock
+type ProductGetter interface {
+	// GetVariants returns the variants of the given products keyed by variant ID
+	GetVariants(ctx context.Context, productIDs []uint) (map[uint]*VariantInfo, error)
+}
+
+// Service interface
+type Service interface {
+	Get(ctx context.Context, owner Owner) (*CartResponse, error)
+	AddItem(ctx context.Context, owner Owner, req AddItemRequest) (*CartResponse, error)
+	UpdateItem(ctx context.Context, owner Owner, itemID uint, req UpdateItemRequest) (*CartResponse, error)
+	RemoveItem(ctx context.Context, owner Owner, itemID uint) (*CartResponse, error)
+
+	// MergeGuestCart moves a guest cart into the user's cart (called on login)
+	MergeGuestCart(ctx context.Context, token string, userID uuid.UUID) error
+	PurgeStaleGuestCarts(ctx context.Context) error
+}
+
+type service struct {
+	repo     Repository
+	products ProductGetter
+}
+
+// NewService creates a new cart service
+func NewService(repo Repository, products ProductGetter) Service {
+	return &service{repo: repo, products: products}
+}
+
+// Get returns the cart of owner; a missing cart is returned empty, not created
+func (s *service) Get(ctx context.Context, owner Owner) (*CartResponse, error) {
+	cart, err := s.find(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		return s.toResponse(ctx, &Cart{})
+	}
+	return s.toResponse(ctx, cart)
+}
+
+// AddItem adds a published variant, creating the cart (and a guest token) when needed
+func (s *service) AddItem(ctx context.Context, owner Owner, req AddItemRequest) (*CartResponse, error) {
+	info, err := s.variant(ctx, req.ProductID, req.VariantID)
+	if err != nil {
+		return nil, err
+	}
+
+	cart, err := s.findOrCreate(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+
+	item := findItem(cart, req.VariantID)
+	if item == nil {
+		item = &CartItem{CartID: cart.ID, ProductID: req.ProductID, VariantID: req.VariantID}
+	}
+	if item.Quantity+req.Quantity > info.Sellable() {
+		return nil, errors.ErrInsufficientStock
+	}
+	item.Quantity += req.Quantity
+	item.AddedPrice = info.Price
+
+	if err := s.repo.SaveItem(ctx, item); err != nil {
+		return nil, err
+	}
+	return s.reload(ctx, cart)
+}
+
+// UpdateItem sets the quantity of a line and accepts the current price
+func (s *service) UpdateItem(ctx context.Context, owner Owner, itemID uint, req UpdateItemRequest) (*CartResponse, error) {
+	cart, err := s.find(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	item := findItemByID(cart, itemID)
+	if item == nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	info, err := s.variant(ctx, item.ProductID, item.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Quantity > info.Sellable() {
+		return nil, errors.ErrInsufficientStock
+	}
+	item.Quantity = req.Quantity
+	item.AddedPrice = info.Price
+
+	if err := s.repo.SaveItem(ctx, item); err != nil {
+		return nil, err
+	}
+	return s.reload(ctx, cart)
+}
+
+func (s *service) RemoveItem(ctx context.Context, owner Owner, itemID uint) (*CartResponse, error) {
+	cart, err := s.find(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	if cart == nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	deleted, err := s.repo.DeleteItem(ctx, cart.ID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if !deleted {
+		return nil, errors.ErrRecordNotFound
+	}
+	return s.reload(ctx, cart)
+}
+
+// MergeGuestCart adds the guest lines to the user's cart and deletes the guest cart
+// Quantities of variants in both carts are added up, capped at the current stock
+// plus backorders (but never below what the user cart already had).
+func (s *service) MergeGuestCart(ctx context.Context, token string, userID uuid.UUID) error {
+	guest, err := s.repo.GetByToken(ctx, token)
+	if err == gorm.ErrRecordNotFound {
+		return nil // Nothing to merge
+	}
+	if err != nil {
+		return err
+	}
+	if len(guest.Items) == 0 {
+		return s.repo.WithTransaction(func(tx *gorm.DB) error {
+			return tx.WithContext(ctx).Delete(guest).Error
+		})
+	}
+
+	userCart, err := s.findOrCreate(ctx, Owner{UserID: &userID})
+	if err != nil {
+		return err
+	}
+
+	productIDs := make([]uint, 0, len(guest.Items))
+	for _, item := range guest.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	variants, err := s.products.GetVariants(ctx, productIDs)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		for _, guestItem := range guest.Items {
+			item := findItem(userCart, guestItem.VariantID)
+			if item == nil {
+				item = &CartItem{CartID: userCart.ID, ProductID: guestItem.ProductID, VariantID: guestItem.VariantID, AddedPrice: guestItem.AddedPrice}
+			}
+
+			quantity := item.Quantity + guestItem.Quantity
+			if info, ok := variants[guestItem.VariantID]; ok && quantity > info.Sellable() {
+				quantity = max(info.Sellable(), item.Quantity)
+			}
+			if quantity <= 0 {
+				continue
+			}
+			item.Quantity = quantity
+
+			if err := tx.Save(item).Error; err != nil {
+				return fmt.Errorf("failed to merge cart item: %w", err)
+			}
+		}
+		return tx.Delete(guest).Error
+	})
+}
+
+// PurgeStaleGuestCarts removes guest carts unused for guestCartRetention
+func (s *service) PurgeStaleGuestCarts(ctx context.Context) error {
+	_, err := s.repo.DeleteGuestCartsBefore(ctx, time.Now().Add(-guestCartRetention))
+	return err
+}
+
+// find returns the cart of owner, or nil when there is none yet
+func (s *service) find(ctx context.Context, owner Owner) (*Cart, error) {
+	var (
+		cart *Cart
+		err  error
+	)
+	switch {
+	case owner.UserID != nil:
+		cart, err = s.repo.GetByUser(ctx, *owner.UserID)
+	case owner.Token != "":
+		cart, err = s.repo.GetByToken(ctx, owner.Token)
+	default:
+		return nil, nil
+	}
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return cart, err
+}
+
+func (s *service) findOrCreate(ctx context.Context, owner Owner) (*Cart, error) {
+	cart, err := s.find(ctx, owner)
+	if err != nil || cart != nil {
+		return cart, err
+	}
+
+	cart = &Cart{UserID: owner.UserID}
+	if owner.UserID == nil {
+		// Unknown or expired tokens get a fresh cart with a new token
+		token, err := crypto.RandomToken(32)
+		if err != nil {
+			return nil, err
+		}
+		cart.Token = &token
+	}
+	if err := s.repo.Create(ctx, cart); err != nil {
+		return nil, err
+	}
+	return cart, nil
+}
+
+// variant returns a published variant or ErrRecordNotFound
+func (s *service) variant(ctx context.Context, productID, variantID uint) (*VariantInfo, error) {
+	variants, err := s.products.GetVariants(ctx, []uint{productID})
+	if err != nil {
+		return nil, err
+	}
+	info, ok := variants[variantID]
+	if !ok || info.ProductID != productID || !info.Available {
+		return nil, errors.ErrRecordNotFound
+	}
+	return info, nil
+}
+
+// reload re-reads a cart after a change and keeps guest carts alive
+func (s *service) reload(ctx context.Context, cart *Cart) (*CartResponse, error) {
+	if err := s.repo.Touch(ctx, cart.ID); err != nil {
+		return nil, err
+	}
+
+	var (
+		fresh *Cart
+		err   error
+	)
+	if cart.UserID != nil {
+		fresh, err = s.repo.GetByUser(ctx, *cart.UserID)
+	} else {
+		fresh, err = s.repo.GetByToken(ctx, *cart.Token)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, fresh)
+}
+
+// toResponse prices every line at the current price and collects warnings
+func (s *service) toResponse(ctx context.Context, cart *Cart) (*CartResponse, error) {
+	res := &CartResponse{
+		Items:    []CartItemResponse{},
+		Subtotal: money.New(0, money.Default),
+		Warnings: []CartWarning{},
+	}
+	if cart.UserID == nil && cart.Token != nil {
+		res.Token = *cart.Token
+	}
+	if len(cart.Items) == 0 {
+		return res, nil
+	}
+
+	productIDs := make([]uint, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	variants, err := s.products.GetVariants(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range cart.Items {
+		line := CartItemResponse{
+			ID:           item.ID,
+			ProductID:    item.ProductID,
+			VariantID:    item.VariantID,
+			Quantity:     item.Quantity,
+			AddedPrice:   item.AddedPrice,
+			UnitPrice:    item.AddedPrice,
+			Availability: AvailabilityInStock,
+		}
+		sellable := 0
+
+		info, ok := variants[item.VariantID]
+		if ok {
+			line.Name = info.Name
+			line.Variant = info.Variant
+			line.Slug = info.Slug
+			line.SKU = info.SKU
+			line.ImageURL = info.ImageURL
+			line.UnitPrice = info.Price
+			line.Stock = info.Stock
+			line.Available = info.Available
+			sellable = info.Sellable()
+			if item.Quantity > info.Stock && info.Backorderable > 0 {
+				line.BackorderQuantity = min(item.Quantity, sellable) - max(info.Stock, 0)
+				line.AvailableAt = info.AvailableAt
+				line.Availability = AvailabilityBackorder
+				if info.Preorder {
+					line.Availability = AvailabilityPreorder
+				}
+				line.AvailabilityNote = availabilityNote(line.Availability, line.BackorderQuantity, item.Quantity, info.AvailableAt)
+			}
+		}
+		line.LineTotal = line.UnitPrice.Mul(int64(item.Quantity))
+
+		switch {
+		case !line.Available:
+			res.Warnings = append(res.Warnings, CartWarning{ItemID: item.ID, Code: WarningUnavailable, Message: "Sản phẩm không còn được bán"})
+		case item.Quantity > sellable:
+			res.Warnings = append(res.Warnings, CartWarning{ItemID: item.ID, Code: WarningInsufficientStock, Message: fmt.Sprintf("Chỉ còn %d sản phẩm", sellable)})
+		}
+		if line.Available && !line.UnitPrice.Equal(item.AddedPrice) {
+			res.Warnings = append(res.Warnings, CartWarning{
+				ItemID:  item.ID,
+				Code:    WarningPriceChanged,
+				Message: fmt.Sprintf("Giá đã thay đổi từ %s thành %s", item.AddedPrice, line.UnitPrice),
+			})
+		}
+
+		if line.Available {
+			res.Subtotal = res.Subtotal.Add(line.LineTotal)
+			res.ItemCount += item.Quantity
+		}
+		res.Items = append(res.Items, line)
+	}
+	return res, nil
+}
+
+// availabilityNote labels a line with units beyond stock, e.g.
+// "Hàng đặt trước, dự kiến có hàng 20/11/2026"
+func availabilityNote(availability string, backordered, quantity int, availableAt *time.Time) string {
+	note := "Hàng về sau"
+	if availability == AvailabilityPreorder {
+		note = "Hàng đặt trước"
+	}
+	if backordered < quantity {
+		note += fmt.Sprintf(" (%d/%d sản phẩm)", backordered, quantity)
+	}
+	if availableAt != nil {
+		note += ", dự kiến có hàng " + availableAt.Format("02/01/2006")
+	}
+	return note
+}
+
+func findItem(cart *Cart, variantID uint) *CartItem {
+	for i := range cart.Items {
+		if cart.Items[i].VariantID == variantID {
+			return &cart.Items[i]
+		}
+	}
+	return nil
+}
+
+func findItemByID(cart *Cart, itemID uint) *CartItem {
+	if cart == nil {
+		return nil
+	}
+	for i := range cart.Items {
+		if cart.Items[i].ID == itemID {
+			return &cart.Items[i]
+		}
+	}
+	return nil
+}
@@ -6,6 +6,7 @@ import "time"
 type CreateCategoryRequest struct {
 	Name        string `json:"name" binding:"required,min=2,max=100"`
 	Description string `json:"description"`
+	ParentID    *uint  `json:"parent_id"`
 }
 
 // UpdateCategoryRequest - Request body for updating category
@@ -14,24 +15,54 @@ type UpdateCategoryRequest struct {
 	Description string `json:"description"`
 }
 
+// MoveCategoryRequest - Request body for moving a category (and its subtree)
+// A null parent_id moves the category to the root level.
+type MoveCategoryRequest struct {
+	ParentID *uint `json:"parent_id"`
+}
+
 // CategoryResponse - Response DTO
 type CategoryResponse struct {
-	ID          uint      `json:"id"`
-	Name        string    `json:"name"`
-	Slug        string    `json:"slug"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint       `json:"id"`
+	Name        string     `json:"name"`
+	Slug        string     `json:"slug"`
+	Description string     `json:"description"`
+	ParentID    *uint      `json:"parent_id"`
+	Depth       int        `json:"depth"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+// CategoryTreeNode - Category with nested children
+type CategoryTreeNode struct {
+	CategoryResponse
+	Children []*CategoryTreeNode `json:"children"`
+}
+
+// BreadcrumbItem - One step of a breadcrumb trail
+type BreadcrumbItem struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
 }
 
 // ToCategoryResponse converts entity to response DTO
 func ToCategoryResponse(c *Category) *CategoryResponse {
+	var deletedAt *time.Time
+	if c.DeletedAt.Valid {
+		deletedAt = &c.DeletedAt.Time
+	}
+
 	return &CategoryResponse{
 		ID:          c.ID,
 		Name:        c.Name,
 		Slug:        c.Slug,
 		Description: c.Description,
+		ParentID:    c.ParentID,
+		Depth:       c.Depth,
 		CreatedAt:   c.CreatedAt,
 		UpdatedAt:   c.UpdatedAt,
+		DeletedAt:   deletedAt,
 	}
 }
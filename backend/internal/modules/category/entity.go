@@ -1,15 +1,28 @@
 package category
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // Category entity
+// Categories form a tree. Path is a materialized path of ancestor IDs including
+// the category itself, e.g. "/1/4/9/" for "Thời trang nam > Áo > Áo thun".
 type Category struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Name        string    `gorm:"type:text;not null" json:"name"`
-	Slug        string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"slug"`
-	Description string    `gorm:"type:text" json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint           `gorm:"primaryKey" json:"id"`
+	Name        string         `gorm:"type:text;not null" json:"name"`
+	Slug        string         `gorm:"type:varchar(255);uniqueIndex;not null" json:"slug"`
+	Description string         `gorm:"type:text" json:"description"`
+	ParentID    *uint          `gorm:"index" json:"parent_id"`
+	Path        string         `gorm:"type:varchar(1024);not null;default:'';index" json:"path"`
+	Depth       int            `gorm:"not null;default:0" json:"depth"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete (trash)
+
+	// Relationships
+	Parent *Category `gorm:"foreignKey:ParentID;constraint:OnDelete:RESTRICT" json:"-"`
 }
 
 func (Category) TableName() string {
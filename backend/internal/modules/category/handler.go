@@ -40,6 +40,10 @@ func (h *Handler) Create(c *gin.Context) {
 
 	res, err := h.service.Create(c.Request.Context(), req)
 	if err != nil {
+		if err == errors.ErrInvalidParent {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Danh mục cha không hợp lệ"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi tạo danh mục"})
 		return
 	}
@@ -160,6 +164,60 @@ func (h *Handler) Delete(c *gin.Context) {
 	}
 
 	err = h.service.Delete(c.Request.Context(), uint(id))
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy danh mục"})
+		case errors.ErrCategoryHasChildren:
+			c.JSON(http.StatusConflict, gin.H{"error": "Danh mục vẫn còn danh mục con"})
+		case errors.ErrCategoryHasProducts:
+			c.JSON(http.StatusConflict, gin.H{"error": "Danh mục vẫn còn sản phẩm"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Xóa danh mục thành công"})
+}
+
+// GetTree handles GET /categories/tree and GET /admin/categories/tree
+// @Summary Lấy cây danh mục
+// @Description Lấy toàn bộ danh mục dưới dạng cây cha/con, dùng cho menu điều hướng
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Success 200 {array} CategoryTreeNode
+// @Router /categories/tree [get]
+func (h *Handler) GetTree(c *gin.Context) {
+	res, err := h.service.GetTree(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy cây danh mục"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// GetBreadcrumbs handles GET /admin/categories/:id/breadcrumbs
+// @Summary Lấy breadcrumb của danh mục
+// @Description Lấy đường dẫn từ danh mục gốc đến danh mục hiện tại (Admin only)
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Success 200 {array} BreadcrumbItem
+// @Failure 404 {object} map[string]string
+// @Router /admin/categories/{id}/breadcrumbs [get]
+func (h *Handler) GetBreadcrumbs(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	res, err := h.service.GetBreadcrumbs(c.Request.Context(), uint(id))
 	if err != nil {
 		if err == errors.ErrRecordNotFound {
 			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy danh mục"})
@@ -169,5 +227,179 @@ func (h *Handler) Delete(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Xóa danh mục thành công"})
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// Move handles PUT /admin/categories/:id/move
+// @Summary Di chuyển danh mục
+// @Description Chuyển danh mục (kèm toàn bộ danh mục con) sang danh mục cha khác (Admin only)
+// @Tags Categories
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Category ID"
+// @Param request body MoveCategoryRequest true "Parent category"
+// @Success 200 {object} CategoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /admin/categories/{id}/move [put]
+func (h *Handler) Move(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	var req MoveCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Move(c.Request.Context(), uint(id), req)
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy danh mục"})
+		case errors.ErrInvalidParent:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Không thể chuyển danh mục vào chính nó hoặc danh mục con của nó"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Di chuyển danh mục thành công",
+		"data":    res,
+	})
+}
+
+// GetTrash handles GET /admin/categories/trash
+// @Summary Thùng rác danh mục
+// @Description Lấy danh sách danh mục đã xóa, có thể khôi phục trong thời gian lưu trữ (Admin only)
+// @Tags Categories
+// @Produce json
+// @Security BearerAuth
+// @Router /admin/categories/trash [get]
+func (h *Handler) GetTrash(c *gin.Context) {
+	res, err := h.service.GetTrash(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy thùng rác"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// Restore handles POST /admin/categories/:id/restore
+// @Summary Khôi phục danh mục
+// @Description Khôi phục danh mục từ thùng rác (Admin only)
+// @Tags Categories
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID"
+// @Router /admin/categories/{id}/restore [post]
+func (h *Handler) Restore(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	res, err := h.service.Restore(c.Request.Context(), uint(id))
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy danh mục trong thùng rác"})
+		case errors.ErrRestoreExpired:
+			c.JSON(http.StatusGone, gin.H{"error": "Đã quá thời gian cho phép khôi phục"})
+		case errors.ErrParentDeleted:
+			c.JSON(http.StatusConflict, gin.H{"error": "Danh mục cha đang nằm trong thùng rác, hãy khôi phục trước"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Khôi phục thành công",
+		"data":    res,
+	})
+}
+
+// Purge handles DELETE /admin/categories/:id/purge
+// @Summary Xóa vĩnh viễn danh mục
+// @Description Xóa vĩnh viễn danh mục đang nằm trong thùng rác (Admin only)
+// @Tags Categories
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID"
+// @Router /admin/categories/{id}/purge [delete]
+func (h *Handler) Purge(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	err = h.service.Purge(c.Request.Context(), uint(id))
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy danh mục trong thùng rác"})
+		case errors.ErrCategoryHasChildren:
+			c.JSON(http.StatusConflict, gin.H{"error": "Danh mục vẫn còn danh mục con"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Xóa vĩnh viễn thành công"})
+}
+
+// GetBySlug handles GET /categories/:slug (storefront)
+// Slugs of renamed categories answer with 301 and the current slug.
+func (h *Handler) GetBySlug(c *gin.Context) {
+	res, newSlug, err := h.service.GetBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy danh mục"})
+		return
+	}
+	if newSlug != "" {
+		c.Header("Location", "/api/v1/categories/"+newSlug)
+		c.JSON(http.StatusMovedPermanently, gin.H{"redirect_to": newSlug})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// GetBreadcrumbsBySlug handles GET /categories/:slug/breadcrumbs (storefront)
+// @Summary Lấy breadcrumb của danh mục theo slug
+// @Description Lấy đường dẫn từ danh mục gốc đến danh mục hiện tại
+// @Tags Categories
+// @Produce json
+// @Param slug path string true "Category slug"
+// @Success 200 {array} BreadcrumbItem
+// @Failure 404 {object} map[string]string
+// @Router /categories/{slug}/breadcrumbs [get]
+func (h *Handler) GetBreadcrumbsBySlug(c *gin.Context) {
+	category, newSlug, err := h.service.GetBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy danh mục"})
+		return
+	}
+	if newSlug != "" {
+		c.Header("Location", "/api/v1/categories/"+newSlug+"/breadcrumbs")
+		c.JSON(http.StatusMovedPermanently, gin.H{"redirect_to": newSlug})
+		return
+	}
+
+	res, err := h.service.GetBreadcrumbs(c.Request.Context(), category.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
 }
@@ -2,6 +2,9 @@ package category
 
 import (
 	"context"
+	"strconv"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -10,9 +13,26 @@ import (
 type Repository interface {
 	Create(ctx context.Context, category *Category) error
 	GetByID(ctx context.Context, id uint) (*Category, error)
+	GetBySlug(ctx context.Context, slug string) (*Category, error)
+	GetByName(ctx context.Context, name string) ([]Category, error) // Case-insensitive, names may repeat
 	GetAll(ctx context.Context) ([]Category, error)
 	Update(ctx context.Context, category *Category) error
 	Delete(ctx context.Context, id uint) error
+
+	// Trash operations (soft-deleted categories)
+	GetTrashed(ctx context.Context) ([]Category, error)
+	GetTrashedByID(ctx context.Context, id uint) (*Category, error)
+	GetTrashedBefore(ctx context.Context, before time.Time) ([]Category, error) // Deepest first
+	Restore(ctx context.Context, id uint) error
+	HardDelete(ctx context.Context, id uint) error
+
+	// Tree operations
+	GetByIDs(ctx context.Context, ids []uint) ([]Category, error)
+	GetDescendantIDs(ctx context.Context, id uint) ([]uint, error)
+	HasChildren(ctx context.Context, id uint) (bool, error)
+
+	// Transaction support
+	WithTransaction(fn func(*gorm.DB) error) error
 }
 
 type repository struct {
@@ -24,6 +44,11 @@ func NewRepository(db *gorm.DB) Repository {
 	return &repository{db: db}
 }
 
+// WithTransaction executes a function within a transaction
+func (r *repository) WithTransaction(fn func(*gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
 func (r *repository) Create(ctx context.Context, category *Category) error {
 	return r.db.WithContext(ctx).Create(category).Error
 }
@@ -37,9 +62,24 @@ func (r *repository) GetByID(ctx context.Context, id uint) (*Category, error) {
 	return &category, nil
 }
 
+func (r *repository) GetBySlug(ctx context.Context, slug string) (*Category, error) {
+	var category Category
+	err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&category).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *repository) GetByName(ctx context.Context, name string) ([]Category, error) {
+	var categories []Category
+	err := r.db.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name).Find(&categories).Error
+	return categories, err
+}
+
 func (r *repository) GetAll(ctx context.Context) ([]Category, error) {
 	var categories []Category
-	err := r.db.WithContext(ctx).Find(&categories).Error
+	err := r.db.WithContext(ctx).Order("depth ASC, name ASC").Find(&categories).Error
 	if err != nil {
 		return nil, err
 	}
@@ -53,3 +93,91 @@ func (r *repository) Update(ctx context.Context, category *Category) error {
 func (r *repository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&Category{}, id).Error
 }
+
+func (r *repository) GetByIDs(ctx context.Context, ids []uint) ([]Category, error) {
+	var categories []Category
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Order("depth ASC").Find(&categories).Error
+	return categories, err
+}
+
+// GetDescendantIDs returns the category itself and all of its descendants
+func (r *repository) GetDescendantIDs(ctx context.Context, id uint) ([]uint, error) {
+	category, err := r.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []uint
+	err = r.db.WithContext(ctx).Model(&Category{}).
+		Where("path LIKE ?", category.Path+"%").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// HasChildren counts children in trash too, since restoring them needs the parent
+func (r *repository) HasChildren(ctx context.Context, id uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+// buildPath appends a category ID to its parent's materialized path
+func buildPath(parentPath string, id uint) string {
+	if parentPath == "" {
+		parentPath = "/"
+	}
+	return parentPath + strconv.FormatUint(uint64(id), 10) + "/"
+}
+
+// pathIDs extracts ancestor IDs (root first) from a materialized path
+func pathIDs(path string) []uint {
+	var ids []uint
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		id, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids
+}
+
+func (r *repository) GetTrashed(ctx context.Context) ([]Category, error) {
+	var items []Category
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *repository) GetTrashedByID(ctx context.Context, id uint) (*Category, error) {
+	var category Category
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL").
+		First(&category, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetTrashedBefore returns records deleted before the given time (retention expired)
+func (r *repository) GetTrashedBefore(ctx context.Context, before time.Time) ([]Category, error) {
+	var items []Category
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("depth DESC").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *repository) Restore(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&Category{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
+}
+
+func (r *repository) HardDelete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(&Category{}, id).Error
+}
@@ -3,10 +3,13 @@ package category
 import (
 	"context"
 	"fmt"
-	"regexp"
 	"strings"
+	"time"
 
 	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/internal/shared/slug"
+
+	"gorm.io/gorm"
 )
 
 // ProductChecker interface for checking product existence
@@ -14,20 +17,6 @@ type ProductChecker interface {
 	HasProductsWithCategory(ctx context.Context, categoryID uint) (bool, error)
 }
 
-// generateSlug creates a URL-friendly slug from name
-func generateSlug(name string) string {
-	// Convert to lowercase and replace spaces with hyphens
-	slug := strings.ToLower(strings.TrimSpace(name))
-	slug = strings.ReplaceAll(slug, " ", "-")
-	// Remove special characters
-	reg := regexp.MustCompile(`[^a-z0-9-]+`)
-	slug = reg.ReplaceAllString(slug, "")
-	// Remove multiple hyphens
-	reg = regexp.MustCompile(`-+`)
-	slug = reg.ReplaceAllString(slug, "-")
-	return strings.Trim(slug, "-")
-}
-
 // Service interface
 type Service interface {
 	Create(ctx context.Context, req CreateCategoryRequest) (*CategoryResponse, error)
@@ -35,26 +24,79 @@ type Service interface {
 	GetAll(ctx context.Context) ([]CategoryResponse, error)
 	Update(ctx context.Context, id uint, req UpdateCategoryRequest) (*CategoryResponse, error)
 	Delete(ctx context.Context, id uint) error
+	GetTree(ctx context.Context) ([]*CategoryTreeNode, error)
+	GetBreadcrumbs(ctx context.Context, id uint) ([]BreadcrumbItem, error)
+	Move(ctx context.Context, id uint, req MoveCategoryRequest) (*CategoryResponse, error)
+
+	GetBySlug(ctx context.Context, categorySlug string) (*CategoryResponse, string, error)
+
+	// Trash
+	GetTrash(ctx context.Context) ([]CategoryResponse, error)
+	Restore(ctx context.Context, id uint) (*CategoryResponse, error)
+	Purge(ctx context.Context, id uint) error
+	PurgeExpired(ctx context.Context) error
 }
 
 type service struct {
 	repo           Repository
 	productChecker ProductChecker
+	slugs          *slug.Registry
+	trashRetention time.Duration
 }
 
 // NewService creates a new category service
-func NewService(repo Repository, productChecker ProductChecker) Service {
-	return &service{repo: repo, productChecker: productChecker}
+// Deleted categories stay restorable in trash for trashRetention.
+func NewService(repo Repository, productChecker ProductChecker, slugs *slug.Registry, trashRetention time.Duration) Service {
+	return &service{repo: repo, productChecker: productChecker, slugs: slugs, trashRetention: trashRetention}
 }
 
 func (s *service) Create(ctx context.Context, req CreateCategoryRequest) (*CategoryResponse, error) {
 	category := &Category{
 		Name:        req.Name,
-		Slug:        generateSlug(req.Name),
 		Description: req.Description,
+		ParentID:    req.ParentID,
 	}
 
-	if err := s.repo.Create(ctx, category); err != nil {
+	err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+
+		// The parent's path is read under the tree lock so a concurrent move cannot leave it stale
+		var parent *Category
+		if req.ParentID != nil {
+			if err := lockTree(tx); err != nil {
+				return err
+			}
+			var p Category
+			if err := tx.First(&p, *req.ParentID).Error; err != nil {
+				return errors.ErrInvalidParent
+			}
+			parent = &p
+		}
+
+		categorySlug, err := s.slugs.Unique(tx, slug.EntityCategory, slug.Make(req.Name), 0)
+		if err != nil {
+			return err
+		}
+		category.Slug = categorySlug
+
+		if err := tx.Create(category).Error; err != nil {
+			return err
+		}
+
+		// Path needs the new ID, so it is set after insert
+		parentPath := ""
+		if parent != nil {
+			parentPath = parent.Path
+			category.Depth = parent.Depth + 1
+		}
+		category.Path = buildPath(parentPath, category.ID)
+
+		return tx.Model(category).Updates(map[string]interface{}{
+			"path":  category.Path,
+			"depth": category.Depth,
+		}).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -90,15 +132,28 @@ func (s *service) Update(ctx context.Context, id uint, req UpdateCategoryRequest
 		return nil, errors.ErrRecordNotFound
 	}
 
-	if req.Name != "" {
-		category.Name = req.Name
-		category.Slug = generateSlug(req.Name)
-	}
 	if req.Description != "" {
 		category.Description = req.Description
 	}
 
-	if err := s.repo.Update(ctx, category); err != nil {
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		if req.Name != "" && req.Name != category.Name {
+			// Renaming changes the URL; the old slug is kept for redirects
+			newSlug, err := s.slugs.Unique(tx, slug.EntityCategory, slug.Make(req.Name), category.ID)
+			if err != nil {
+				return err
+			}
+			if err := s.slugs.Rename(tx, slug.EntityCategory, category.ID, category.Slug, newSlug); err != nil {
+				return err
+			}
+			category.Name = req.Name
+			category.Slug = newSlug
+		}
+		// Path, depth and parent belong to Move; saving the copy read above could undo one
+		return tx.Model(category).Select("name", "slug", "description").Updates(category).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -111,6 +166,15 @@ func (s *service) Delete(ctx context.Context, id uint) error {
 		return errors.ErrRecordNotFound
 	}
 
+	// Check if this category still has children
+	hasChildren, err := s.repo.HasChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if hasChildren {
+		return errors.ErrCategoryHasChildren
+	}
+
 	// Check if any products use this category
 	if s.productChecker != nil {
 		hasProducts, err := s.productChecker.HasProductsWithCategory(ctx, id)
@@ -118,9 +182,241 @@ func (s *service) Delete(ctx context.Context, id uint) error {
 			return err
 		}
 		if hasProducts {
-			return fmt.Errorf("cannot delete category: products are using this category")
+			return errors.ErrCategoryHasProducts
 		}
 	}
 
 	return s.repo.Delete(ctx, id)
 }
+
+// GetTree returns all categories as a nested tree
+func (s *service) GetTree(ctx context.Context) ([]*CategoryTreeNode, error) {
+	categories, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Categories are ordered by depth, so parents are always seen before children
+	nodes := make(map[uint]*CategoryTreeNode, len(categories))
+	roots := []*CategoryTreeNode{}
+	for i := range categories {
+		node := &CategoryTreeNode{
+			CategoryResponse: *ToCategoryResponse(&categories[i]),
+			Children:         []*CategoryTreeNode{},
+		}
+		nodes[node.ID] = node
+
+		if node.ParentID == nil {
+			roots = append(roots, node)
+			continue
+		}
+		if parent, ok := nodes[*node.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	return roots, nil
+}
+
+// GetBreadcrumbs returns the trail from the root category down to the given one
+func (s *service) GetBreadcrumbs(ctx context.Context, id uint) ([]BreadcrumbItem, error) {
+	category, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	ancestors, err := s.repo.GetByIDs(ctx, pathIDs(category.Path))
+	if err != nil {
+		return nil, err
+	}
+
+	breadcrumbs := make([]BreadcrumbItem, 0, len(ancestors))
+	for _, a := range ancestors {
+		breadcrumbs = append(breadcrumbs, BreadcrumbItem{ID: a.ID, Name: a.Name, Slug: a.Slug})
+	}
+	return breadcrumbs, nil
+}
+
+// Move re-parents a category and rewrites the paths of its whole subtree
+// Moves take the tree lock before reading any path, so two concurrent moves
+// (A under a descendant of B, B under a descendant of A) cannot both pass the
+// cycle check on paths the other is about to rewrite.
+func (s *service) Move(ctx context.Context, id uint, req MoveCategoryRequest) (*CategoryResponse, error) {
+	var category *Category
+	newPath := ""
+	newDepth := 0
+
+	err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		if err := lockTree(tx); err != nil {
+			return err
+		}
+
+		ids := []uint{id}
+		if req.ParentID != nil {
+			ids = append(ids, *req.ParentID)
+		}
+		var rows []Category
+		err := tx.Where("id IN ?", ids).Find(&rows).Error
+		if err != nil {
+			return err
+		}
+		var parent *Category
+		for i := range rows {
+			if rows[i].ID == id {
+				category = &rows[i]
+			}
+			if req.ParentID != nil && rows[i].ID == *req.ParentID {
+				parent = &rows[i]
+			}
+		}
+		if category == nil {
+			return errors.ErrRecordNotFound
+		}
+
+		parentPath := ""
+		if req.ParentID != nil {
+			// A category cannot be moved under itself or one of its descendants
+			if parent == nil || strings.HasPrefix(parent.Path, category.Path) {
+				return errors.ErrInvalidParent
+			}
+			parentPath = parent.Path
+			newDepth = parent.Depth + 1
+		}
+
+		oldPath := category.Path
+		newPath = buildPath(parentPath, category.ID)
+		depthDelta := newDepth - category.Depth
+
+		if err := tx.Model(&Category{}).Where("id = ?", id).Update("parent_id", req.ParentID).Error; err != nil {
+			return fmt.Errorf("failed to update parent: %w", err)
+		}
+
+		err = tx.Exec(
+			"UPDATE categories SET path = ? || substring(path from ?), depth = depth + ? WHERE path LIKE ?",
+			newPath, len(oldPath)+1, depthDelta, oldPath+"%",
+		).Error
+		if err != nil {
+			return fmt.Errorf("failed to update subtree paths: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	category.ParentID = req.ParentID
+	category.Path = newPath
+	category.Depth = newDepth
+	return ToCategoryResponse(category), nil
+}
+
+// GetTrash lists soft-deleted categories
+func (s *service) GetTrash(ctx context.Context) ([]CategoryResponse, error) {
+	categories, err := s.repo.GetTrashed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []CategoryResponse
+	for _, c := range categories {
+		responses = append(responses, *ToCategoryResponse(&c))
+	}
+	return responses, nil
+}
+
+// Restore brings a category back from trash within the retention window
+// Its parent must not be in trash, otherwise the tree would have a hole.
+func (s *service) Restore(ctx context.Context, id uint) (*CategoryResponse, error) {
+	category, err := s.repo.GetTrashedByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	if time.Since(category.DeletedAt.Time) > s.trashRetention {
+		return nil, errors.ErrRestoreExpired
+	}
+	if category.ParentID != nil {
+		if _, err := s.repo.GetByID(ctx, *category.ParentID); err != nil {
+			return nil, errors.ErrParentDeleted
+		}
+	}
+
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Purge permanently deletes a trashed category
+func (s *service) Purge(ctx context.Context, id uint) error {
+	if _, err := s.repo.GetTrashedByID(ctx, id); err != nil {
+		return errors.ErrRecordNotFound
+	}
+
+	hasChildren, err := s.repo.HasChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if hasChildren {
+		return errors.ErrCategoryHasChildren
+	}
+
+	return s.purge(ctx, id)
+}
+
+// PurgeExpired permanently deletes categories whose retention window has passed
+// Children come first; a parent whose children are still in trash waits for them.
+func (s *service) PurgeExpired(ctx context.Context) error {
+	categories, err := s.repo.GetTrashedBefore(ctx, time.Now().Add(-s.trashRetention))
+	if err != nil {
+		return err
+	}
+
+	for _, c := range categories {
+		hasChildren, err := s.repo.HasChildren(ctx, c.ID)
+		if err != nil {
+			return err
+		}
+		if hasChildren {
+			continue
+		}
+		if err := s.purge(ctx, c.ID); err != nil {
+			return fmt.Errorf("failed to purge category %d: %w", c.ID, err)
+		}
+	}
+	return nil
+}
+
+func (s *service) purge(ctx context.Context, id uint) error {
+	if err := s.repo.HardDelete(ctx, id); err != nil {
+		return err
+	}
+	return s.slugs.Forget(ctx, slug.EntityCategory, id)
+}
+
+// GetBySlug returns a category by its current slug
+// For an old slug of a renamed category it returns the current slug instead.
+func (s *service) GetBySlug(ctx context.Context, categorySlug string) (*CategoryResponse, string, error) {
+	category, err := s.repo.GetBySlug(ctx, categorySlug)
+	if err == nil {
+		return ToCategoryResponse(category), "", nil
+	}
+
+	id, err := s.slugs.Resolve(ctx, slug.EntityCategory, categorySlug)
+	if err != nil {
+		return nil, "", errors.ErrRecordNotFound
+	}
+	category, err = s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, "", errors.ErrRecordNotFound
+	}
+	return nil, category.Slug, nil
+}
+
+// lockTree serializes path changes until the transaction ends
+// Paths are derived from the parent's, so a move must not interleave with another
+// move or with a create under the subtree being moved.
+func lockTree(tx *gorm.DB) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "categories/tree").Error
+}
@@ -0,0 +1,89 @@
+package category
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go-ecommerce/internal/database/dbtest"
+	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/internal/shared/slug"
+
+	"gorm.io/gorm"
+)
+
+func TestMoveCrossingSubtrees(t *testing.T) {
+	db := dbtest.Open(t, &Category{}, &slug.History{})
+	svc := NewService(NewRepository(db), nil, slug.NewRegistry(db), 30*24*time.Hour)
+	ctx := context.Background()
+
+	create := func(name string, parentID *uint) uint {
+		t.Helper()
+		c, err := svc.Create(ctx, CreateCategoryRequest{Name: name, ParentID: parentID})
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		return c.ID
+	}
+
+	// Each round: A/a1 and B/b1, then A under b1 and B under a1 at once.
+	// Only one move may win; the other would close a cycle.
+	for round := range 20 {
+		a := create("Nam "+string(rune('a'+round)), nil)
+		a1 := create("Áo nam "+string(rune('a'+round)), &a)
+		b := create("Nữ "+string(rune('a'+round)), nil)
+		b1 := create("Áo nữ "+string(rune('a'+round)), &b)
+
+		var errA, errB error
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, errA = svc.Move(ctx, a, MoveCategoryRequest{ParentID: &b1})
+		}()
+		go func() {
+			defer wg.Done()
+			<-start
+			_, errB = svc.Move(ctx, b, MoveCategoryRequest{ParentID: &a1})
+		}()
+		close(start)
+		wg.Wait()
+
+		if (errA == nil) == (errB == nil) {
+			t.Fatalf("round %d: moves returned %v and %v, want exactly one ErrInvalidParent", round, errA, errB)
+		}
+		for _, err := range []error{errA, errB} {
+			if err != nil && err != errors.ErrInvalidParent {
+				t.Fatalf("round %d: unexpected error: %v", round, err)
+			}
+		}
+	}
+
+	assertPathsMatchParents(t, db)
+}
+
+// assertPathsMatchParents checks every path is its parent's path plus its own ID
+func assertPathsMatchParents(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	var categories []Category
+	if err := db.Find(&categories).Error; err != nil {
+		t.Fatalf("load categories: %v", err)
+	}
+	byID := make(map[uint]Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+	for _, c := range categories {
+		parentPath, depth := "", 0
+		if c.ParentID != nil {
+			parent := byID[*c.ParentID]
+			parentPath, depth = parent.Path, parent.Depth+1
+		}
+		if want := buildPath(parentPath, c.ID); c.Path != want || c.Depth != depth {
+			t.Errorf("category %d: path %s depth %d, want %s depth %d", c.ID, c.Path, c.Depth, want, depth)
+		}
+	}
+}
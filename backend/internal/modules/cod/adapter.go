@@ -0,0 +1,49 @@
+package cod
+
+import (
+	"context"
+	"time"
+
+	"go-ecommerce/internal/modules/order"
+)
+
+// OrderRepoAdapter adapts order.Repository to OrderSource
+type OrderRepoAdapter struct {
+	repo order.Repository
+}
+
+func NewOrderRepoAdapter(repo order.Repository) *OrderRepoAdapter {
+	return &OrderRepoAdapter{repo: repo}
+}
+
+func (a *OrderRepoAdapter) GetByNumbers(ctx context.Context, numbers []string) (map[string]*OrderInfo, error) {
+	orders, err := a.repo.GetByNumbers(ctx, numbers)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]*OrderInfo, len(orders))
+	for _, o := range orders {
+		res[o.Number] = &OrderInfo{
+			ID:        o.ID,
+			Number:    o.Number,
+			COD:       o.PaymentMethod == order.PaymentCOD,
+			Delivered: o.Status == order.StatusDelivered,
+			CODAmount: o.CODAmount,
+		}
+	}
+	return res, nil
+}
+
+func (a *OrderRepoAdapter) GetUnremitted(ctx context.Context, deliveredBefore time.Time) ([]UnremittedOrder, error) {
+	orders, err := a.repo.GetUnpaidCOD(ctx, deliveredBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]UnremittedOrder, 0, len(orders))
+	for _, o := range orders {
+		res = append(res, UnremittedOrder{OrderID: o.ID, Number: o.Number, CODAmount: o.CODAmount, DeliveredAt: o.DeliveredAt})
+	}
+	return res, nil
+}
@@ -0,0 +1,62 @@
+package cod
+
+import (
+	"time"
+
+	"go-ecommerce/pkg/money"
+)
+
+// RuleRequest - Request body for creating or updating a COD rule
+// Leave Province empty for the default rule.
+type RuleRequest struct {
+	Province  string      `json:"province" binding:"max=100"`
+	Enabled   *bool       `json:"enabled"` // Default true
+	Fee       money.Money `json:"fee"`
+	MaxAmount money.Money `json:"max_amount"` // 0 for no limit
+}
+
+// QuoteResponse - COD terms for an order total shipped to a province
+type QuoteResponse struct {
+	Available bool        `json:"available"`
+	Fee       money.Money `json:"fee"`
+	MaxAmount money.Money `json:"max_amount"` // 0 for no limit
+	Reason    string      `json:"reason,omitempty"`
+}
+
+// RemittanceRow - one parsed row of a carrier remittance file
+type RemittanceRow struct {
+	LineNo       int
+	OrderNumber  string
+	TrackingCode string
+	Amount       money.Money
+}
+
+// OrderInfo - the order a remittance row refers to
+type OrderInfo struct {
+	ID        uint
+	Number    string
+	COD       bool
+	Delivered bool // Only then has the carrier collected the cash
+	CODAmount money.Money
+}
+
+// UnremittedOrder - delivered COD order the carrier has not paid for yet
+type UnremittedOrder struct {
+	OrderID     uint        `json:"order_id"`
+	Number      string      `json:"number"`
+	CODAmount   money.Money `json:"cod_amount"`
+	DeliveredAt time.Time   `json:"delivered_at"`
+}
+
+// MismatchFilter - Filters for the mismatch report
+type MismatchFilter struct {
+	Carrier string `form:"carrier"`
+	Days    int    `form:"days" binding:"min=0"` // Delivered this many days ago without remittance, default 7
+}
+
+// MismatchReport - remitted rows that did not match, and money not remitted at all
+type MismatchReport struct {
+	Lines      []RemittanceLine  `json:"lines"`
+	Unremitted []UnremittedOrder `json:"unremitted"`
+	Total      money.Money       `json:"unremitted_total"`
+}
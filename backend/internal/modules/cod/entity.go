@@ -0,0 +1,71 @@
+package cod
+
+import (
+	"time"
+
+	"go-ecommerce/pkg/money"
+)
+
+// Rule entity - cash-on-delivery terms for a province
+// The rule without a province is the default for provinces that have none.
+type Rule struct {
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	Province  string      `gorm:"type:varchar(100)" json:"province"`                          // As entered, empty for the default rule
+	Key       string      `gorm:"column:province_key;type:varchar(100);uniqueIndex" json:"-"` // warehouse.ProvinceKey(Province)
+	Enabled   bool        `gorm:"not null;default:true" json:"enabled"`                       // COD offered at all
+	Fee       money.Money `gorm:"not null;default:0" json:"fee"`                              // Added to the order total
+	MaxAmount money.Money `gorm:"not null;default:0" json:"max_amount"`                       // Largest total the carrier collects, 0 for no limit
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+func (Rule) TableName() string {
+	return "cod_rules"
+}
+
+// LineStatus enum - how a remitted line compares with the order
+type LineStatus string
+
+const (
+	LineMatched        LineStatus = "matched"         // Order marked as paid
+	LineAmountMismatch LineStatus = "amount_mismatch" // Remitted amount differs from the COD amount
+	LineUnknownOrder   LineStatus = "unknown_order"
+	LineNotCOD         LineStatus = "not_cod"       // Order was paid online
+	LineAlreadyPaid    LineStatus = "already_paid"  // Remitted twice, or by another file
+	LineNotDelivered   LineStatus = "not_delivered" // Order not delivered (yet) or returned, the carrier should not hold money for it
+)
+
+// Remittance entity - one carrier remittance file
+type Remittance struct {
+	ID         uint             `gorm:"primaryKey" json:"id"`
+	Carrier    string           `gorm:"type:varchar(50);not null;index" json:"carrier"`
+	FileName   string           `gorm:"type:varchar(255)" json:"file_name"`
+	Total      money.Money      `gorm:"not null" json:"total"` // Sum remitted in the file
+	Matched    int              `gorm:"not null" json:"matched"`
+	Mismatched int              `gorm:"not null" json:"mismatched"`
+	ImportedBy string           `gorm:"type:varchar(36)" json:"imported_by"`
+	CreatedAt  time.Time        `gorm:"index" json:"created_at"`
+	Lines      []RemittanceLine `gorm:"foreignKey:RemittanceID;constraint:OnDelete:CASCADE" json:"lines,omitempty"`
+}
+
+func (Remittance) TableName() string {
+	return "cod_remittances"
+}
+
+// RemittanceLine entity - one row of a remittance file
+type RemittanceLine struct {
+	ID           uint        `gorm:"primaryKey" json:"id"`
+	RemittanceID uint        `gorm:"not null;index" json:"remittance_id"`
+	LineNo       int         `gorm:"not null" json:"line_no"` // Line in the file, for finding it again
+	OrderNumber  string      `gorm:"type:varchar(20);index" json:"order_number"`
+	TrackingCode string      `gorm:"type:varchar(50)" json:"tracking_code"`
+	Amount       money.Money `gorm:"not null" json:"amount"` // Remitted by the carrier
+	OrderID      *uint       `gorm:"index" json:"order_id"`
+	Expected     money.Money `gorm:"not null" json:"expected"` // COD amount of the order
+	Status       LineStatus  `gorm:"type:varchar(20);not null;index" json:"status"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+func (RemittanceLine) TableName() string {
+	return "cod_remittance_lines"
+}
@@ -0,0 +1,239 @@
+package cod
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/pkg/money"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles cash-on-delivery HTTP requests
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new COD handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Quote handles GET /cod/quote?province=&amount=
+// @Summary Phí và hạn mức thanh toán khi nhận hàng
+// @Tags COD
+// @Produce json
+// @Param province query string true "Tỉnh/thành giao hàng"
+// @Param amount query number true "Giá trị đơn hàng (VND)"
+// @Success 200 {object} QuoteResponse
+// @Router /cod/quote [get]
+func (h *Handler) Quote(c *gin.Context) {
+	var amount money.Money
+	if err := amount.UnmarshalParam(c.Query("amount")); err != nil || amount.IsNegative() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Số tiền không hợp lệ"})
+		return
+	}
+
+	res, err := h.service.Quote(c.Request.Context(), c.Query("province"), amount)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// GetRules handles GET /admin/cod/rules
+func (h *Handler) GetRules(c *gin.Context) {
+	res, err := h.service.GetRules(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách quy định COD"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// CreateRule handles POST /admin/cod/rules
+// @Summary Thêm quy định COD
+// @Description Phí thu hộ và hạn mức theo tỉnh/thành; để trống province cho quy định mặc định
+// @Tags COD
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RuleRequest true "Quy định COD"
+// @Success 201 {object} Rule
+// @Router /admin/cod/rules [post]
+func (h *Handler) CreateRule(c *gin.Context) {
+	var req RuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.CreateRule(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Thêm quy định COD thành công",
+		"data":    res,
+	})
+}
+
+// UpdateRule handles PUT /admin/cod/rules/:id
+func (h *Handler) UpdateRule(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req RuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.UpdateRule(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cập nhật quy định COD thành công",
+		"data":    res,
+	})
+}
+
+// DeleteRule handles DELETE /admin/cod/rules/:id
+func (h *Handler) DeleteRule(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteRule(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Xóa quy định COD thành công"})
+}
+
+// ImportRemittance handles POST /admin/cod/remittances
+// @Summary Nhập file đối soát COD của đơn vị vận chuyển
+// @Description CSV với các cột order_number, amount và tracking_code (tuỳ chọn); đơn khớp số tiền được đánh dấu đã thanh toán
+// @Tags COD
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param carrier formData string true "Đơn vị vận chuyển"
+// @Param file formData file true "File CSV"
+// @Success 201 {object} Remittance
+// @Router /admin/cod/remittances [post]
+func (h *Handler) ImportRemittance(c *gin.Context) {
+	carrier := strings.TrimSpace(c.PostForm("carrier"))
+	if carrier == "" || len(carrier) > 50 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "carrier is required"})
+		return
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to open file"})
+		return
+	}
+	defer file.Close()
+
+	rows, err := ParseRemittanceFile(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Import(c.Request.Context(), carrier, fileHeader.Filename, rows, c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi nhập file đối soát"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Nhập file đối soát thành công",
+		"data":    res,
+	})
+}
+
+// GetRemittances handles GET /admin/cod/remittances
+func (h *Handler) GetRemittances(c *gin.Context) {
+	res, err := h.service.GetRemittances(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách đối soát"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// GetRemittance handles GET /admin/cod/remittances/:id
+func (h *Handler) GetRemittance(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GetRemittance(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy file đối soát"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// GetMismatches handles GET /admin/cod/mismatches
+// @Summary Báo cáo chênh lệch COD
+// @Description Dòng đối soát không khớp và đơn COD đã giao quá số ngày (days, mặc định 7) chưa được chuyển tiền
+// @Tags COD
+// @Produce json
+// @Security BearerAuth
+// @Param carrier query string false "Đơn vị vận chuyển"
+// @Param days query int false "Số ngày"
+// @Success 200 {object} MismatchReport
+// @Router /admin/cod/mismatches [get]
+func (h *Handler) GetMismatches(c *gin.Context) {
+	var filter MismatchFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.GetMismatches(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy báo cáo chênh lệch"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+func respondError(c *gin.Context, err error) {
+	switch err {
+	case errors.ErrRecordNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy quy định COD"})
+	case errors.ErrDuplicateCODRule:
+		c.JSON(http.StatusConflict, gin.H{"error": "Tỉnh/thành đã có quy định COD"})
+	case errors.ErrInvalidCODRule:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Phí và hạn mức phải là số tiền VND không âm"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+	}
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return 0, false
+	}
+	return uint(id), true
+}
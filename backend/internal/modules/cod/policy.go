@@ -0,0 +1,53 @@
+package cod
+
+import (
+	"context"
+
+	"go-ecommerce/internal/modules/warehouse"
+	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+// Policy applies the COD rules at checkout
+type Policy struct {
+	repo Repository
+}
+
+func NewPolicy(repo Repository) *Policy {
+	return &Policy{repo: repo}
+}
+
+// Quote returns the COD fee for an order of amount (before the fee) shipped to province
+// Without any rule COD is free and unlimited. The limit applies to what the
+// carrier collects, fee included.
+func (p *Policy) Quote(ctx context.Context, province string, amount money.Money) (money.Money, error) {
+	rule, err := p.repo.GetRuleForProvince(ctx, warehouse.ProvinceKey(province))
+	if err == gorm.ErrRecordNotFound {
+		return money.New(0, money.Default), nil
+	}
+	if err != nil {
+		return money.Money{}, err
+	}
+
+	if !rule.Enabled {
+		return money.Money{}, errors.ErrCODUnavailable
+	}
+	if rule.MaxAmount.IsZero() {
+		return rule.Fee, nil
+	}
+	// The amount may come from a quote request
+	total, err := amount.Plus(rule.Fee)
+	if err != nil {
+		return money.Money{}, err
+	}
+	over, err := total.Compare(rule.MaxAmount)
+	if err != nil {
+		return money.Money{}, err
+	}
+	if over > 0 {
+		return money.Money{}, errors.ErrCODLimit
+	}
+	return rule.Fee, nil
+}
@@ -0,0 +1,114 @@
+package cod
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repository interface
+type Repository interface {
+	// Rules
+	GetRules(ctx context.Context) ([]Rule, error)
+	GetRuleByID(ctx context.Context, id uint) (*Rule, error)
+	GetRuleByKey(ctx context.Context, key string) (*Rule, error)
+	// GetRuleForProvince returns the province's rule, or the default rule
+	GetRuleForProvince(ctx context.Context, key string) (*Rule, error)
+	SaveRule(ctx context.Context, rule *Rule) error
+	DeleteRule(ctx context.Context, id uint) error
+
+	// Remittances
+	GetRemittances(ctx context.Context) ([]Remittance, error)
+	GetRemittanceByID(ctx context.Context, id uint) (*Remittance, error)
+	GetMismatchedLines(ctx context.Context, carrier string) ([]RemittanceLine, error)
+
+	// Transaction support
+	WithTransaction(fn func(*gorm.DB) error) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new COD repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// WithTransaction executes a function within a transaction
+func (r *repository) WithTransaction(fn func(*gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
+func (r *repository) GetRules(ctx context.Context) ([]Rule, error) {
+	var rules []Rule
+	err := r.db.WithContext(ctx).Order("province_key ASC").Find(&rules).Error
+	return rules, err
+}
+
+func (r *repository) GetRuleByID(ctx context.Context, id uint) (*Rule, error) {
+	var rule Rule
+	if err := r.db.WithContext(ctx).First(&rule, id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *repository) GetRuleByKey(ctx context.Context, key string) (*Rule, error) {
+	var rule Rule
+	if err := r.db.WithContext(ctx).Where("province_key = ?", key).First(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *repository) GetRuleForProvince(ctx context.Context, key string) (*Rule, error) {
+	var rule Rule
+	err := r.db.WithContext(ctx).
+		Where("province_key IN ?", []string{key, ""}).
+		Order("province_key DESC"). // The province's own rule sorts before the default
+		First(&rule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *repository) SaveRule(ctx context.Context, rule *Rule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+func (r *repository) DeleteRule(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&Rule{}, id).Error
+}
+
+// GetRemittances returns imported files newest first, without lines
+func (r *repository) GetRemittances(ctx context.Context) ([]Remittance, error) {
+	var remittances []Remittance
+	err := r.db.WithContext(ctx).Order("created_at DESC, id DESC").Find(&remittances).Error
+	return remittances, err
+}
+
+func (r *repository) GetRemittanceByID(ctx context.Context, id uint) (*Remittance, error) {
+	var remittance Remittance
+	err := r.db.WithContext(ctx).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no ASC") }).
+		First(&remittance, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &remittance, nil
+}
+
+// GetMismatchedLines returns remitted rows that did not mark an order as paid, newest first
+func (r *repository) GetMismatchedLines(ctx context.Context, carrier string) ([]RemittanceLine, error) {
+	var lines []RemittanceLine
+	query := r.db.WithContext(ctx).Model(&RemittanceLine{}).
+		Joins("JOIN cod_remittances ON cod_remittances.id = cod_remittance_lines.remittance_id").
+		Where("cod_remittance_lines.status <> ?", LineMatched)
+	if carrier != "" {
+		query = query.Where("LOWER(cod_remittances.carrier) = LOWER(?)", carrier)
+	}
+	err := query.Order("cod_remittance_lines.id DESC").Find(&lines).Error
+	return lines, err
+}
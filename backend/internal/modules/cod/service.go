@@ -0,0 +1,310 @@
+package cod
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"go-ecommerce/internal/modules/warehouse"
+	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+// OrderSource interface for the orders a carrier remits for
+type OrderSource interface {
+	// GetByNumbers returns the orders found, keyed by number
+	GetByNumbers(ctx context.Context, numbers []string) (map[string]*OrderInfo, error)
+	// GetUnremitted returns delivered COD orders not paid for since before the cutoff
+	GetUnremitted(ctx context.Context, deliveredBefore time.Time) ([]UnremittedOrder, error)
+}
+
+// PaidMarker interface for recording orders as paid with the remittance
+// ErrOrderNotDelivered when the order is not delivered; it is then not marked.
+type PaidMarker interface {
+	MarkCollected(tx *gorm.DB, orderID uint, paidAt time.Time) (bool, error)
+}
+
+// Service interface
+type Service interface {
+	// Rules
+	GetRules(ctx context.Context) ([]Rule, error)
+	CreateRule(ctx context.Context, req RuleRequest) (*Rule, error)
+	UpdateRule(ctx context.Context, id uint, req RuleRequest) (*Rule, error)
+	DeleteRule(ctx context.Context, id uint) error
+	Quote(ctx context.Context, province string, amount money.Money) (*QuoteResponse, error)
+
+	// Remittances
+	Import(ctx context.Context, carrier, fileName string, rows []RemittanceRow, actor string) (*Remittance, error)
+	GetRemittances(ctx context.Context) ([]Remittance, error)
+	GetRemittance(ctx context.Context, id uint) (*Remittance, error)
+	GetMismatches(ctx context.Context, filter MismatchFilter) (*MismatchReport, error)
+}
+
+type service struct {
+	repo   Repository
+	policy *Policy
+	orders OrderSource
+	paid   PaidMarker
+}
+
+// NewService creates a new COD service
+func NewService(repo Repository, orders OrderSource, paid PaidMarker) Service {
+	return &service{repo: repo, policy: NewPolicy(repo), orders: orders, paid: paid}
+}
+
+func (s *service) GetRules(ctx context.Context) ([]Rule, error) {
+	return s.repo.GetRules(ctx)
+}
+
+func (s *service) CreateRule(ctx context.Context, req RuleRequest) (*Rule, error) {
+	return s.saveRule(ctx, &Rule{}, req)
+}
+
+func (s *service) UpdateRule(ctx context.Context, id uint, req RuleRequest) (*Rule, error) {
+	rule, err := s.repo.GetRuleByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	return s.saveRule(ctx, rule, req)
+}
+
+// saveRule applies req and saves; each province, and the default, has at most one rule
+func (s *service) saveRule(ctx context.Context, rule *Rule, req RuleRequest) (*Rule, error) {
+	if !req.Fee.IsValidAmount() || !req.MaxAmount.IsValidAmount() {
+		return nil, errors.ErrInvalidCODRule
+	}
+	key := warehouse.ProvinceKey(req.Province)
+	if other, err := s.repo.GetRuleByKey(ctx, key); err == nil && other.ID != rule.ID {
+		return nil, errors.ErrDuplicateCODRule
+	}
+
+	rule.Province = strings.TrimSpace(req.Province)
+	if key == "" {
+		rule.Province = ""
+	}
+	rule.Key = key
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	rule.Fee = req.Fee
+	rule.MaxAmount = req.MaxAmount
+	if err := s.repo.SaveRule(ctx, rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+func (s *service) DeleteRule(ctx context.Context, id uint) error {
+	if _, err := s.repo.GetRuleByID(ctx, id); err != nil {
+		return errors.ErrRecordNotFound
+	}
+	return s.repo.DeleteRule(ctx, id)
+}
+
+// Quote tells the storefront whether COD is offered and what it costs
+func (s *service) Quote(ctx context.Context, province string, amount money.Money) (*QuoteResponse, error) {
+	res := &QuoteResponse{MaxAmount: money.New(0, money.Default)}
+	if rule, err := s.repo.GetRuleForProvince(ctx, warehouse.ProvinceKey(province)); err == nil {
+		res.MaxAmount = rule.MaxAmount
+	}
+
+	fee, err := s.policy.Quote(ctx, province, amount)
+	switch err {
+	case nil:
+		res.Available = true
+		res.Fee = fee
+	case errors.ErrCODUnavailable:
+		res.Reason = "Tỉnh/thành này không hỗ trợ thanh toán khi nhận hàng"
+	case errors.ErrCODLimit:
+		res.Reason = "Giá trị đơn hàng vượt hạn mức thanh toán khi nhận hàng"
+	default:
+		return nil, err
+	}
+	return res, nil
+}
+
+// Import records a carrier remittance and marks the matching orders as paid
+// Rows that do not match (unknown order, wrong amount, already paid, ...) are
+// kept with their status for the mismatch report; the file is imported as a whole.
+func (s *service) Import(ctx context.Context, carrier, fileName string, rows []RemittanceRow, actor string) (*Remittance, error) {
+	numbers := make([]string, 0, len(rows))
+	for _, row := range rows {
+		numbers = append(numbers, row.OrderNumber)
+	}
+	orders, err := s.orders.GetByNumbers(ctx, numbers)
+	if err != nil {
+		return nil, err
+	}
+
+	remittance := &Remittance{
+		Carrier:    strings.TrimSpace(carrier),
+		FileName:   fileName,
+		Total:      money.New(0, money.Default),
+		ImportedBy: actor,
+	}
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		now := time.Now()
+
+		for _, row := range rows {
+			line := RemittanceLine{
+				LineNo:       row.LineNo,
+				OrderNumber:  row.OrderNumber,
+				TrackingCode: row.TrackingCode,
+				Amount:       row.Amount,
+				Expected:     money.New(0, money.Default),
+			}
+			remittance.Total = remittance.Total.Add(row.Amount)
+
+			order, ok := orders[row.OrderNumber]
+			switch {
+			case !ok:
+				line.Status = LineUnknownOrder
+			case !order.COD:
+				line.Status = LineNotCOD
+			case !order.Delivered:
+				line.Status = LineNotDelivered
+			case !row.Amount.Equal(order.CODAmount):
+				line.Status = LineAmountMismatch
+			default:
+				// The order may have been returned since it was loaded
+				marked, err := s.paid.MarkCollected(tx, order.ID, now)
+				switch {
+				case err == errors.ErrOrderNotDelivered:
+					line.Status = LineNotDelivered
+				case err != nil:
+					return err
+				case marked:
+					line.Status = LineMatched
+				default:
+					line.Status = LineAlreadyPaid
+				}
+			}
+			if ok {
+				line.OrderID = &order.ID
+				line.Expected = order.CODAmount
+			}
+
+			if line.Status == LineMatched {
+				remittance.Matched++
+			} else {
+				remittance.Mismatched++
+			}
+			remittance.Lines = append(remittance.Lines, line)
+		}
+
+		if err := tx.Create(remittance).Error; err != nil {
+			return fmt.Errorf("failed to save remittance: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetRemittanceByID(ctx, remittance.ID)
+}
+
+func (s *service) GetRemittances(ctx context.Context) ([]Remittance, error) {
+	return s.repo.GetRemittances(ctx)
+}
+
+func (s *service) GetRemittance(ctx context.Context, id uint) (*Remittance, error) {
+	remittance, err := s.repo.GetRemittanceByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	return remittance, nil
+}
+
+// GetMismatches lists remitted rows that did not match and COD orders delivered
+// more than filter.Days ago that no carrier has remitted for
+func (s *service) GetMismatches(ctx context.Context, filter MismatchFilter) (*MismatchReport, error) {
+	days := filter.Days
+	if days == 0 {
+		days = 7
+	}
+
+	lines, err := s.repo.GetMismatchedLines(ctx, filter.Carrier)
+	if err != nil {
+		return nil, err
+	}
+	unremitted, err := s.orders.GetUnremitted(ctx, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &MismatchReport{Lines: lines, Unremitted: unremitted, Total: money.New(0, money.Default)}
+	for _, o := range unremitted {
+		report.Total = report.Total.Add(o.CODAmount)
+	}
+	return report, nil
+}
+
+// thousands matches amounts written with separators, e.g. 1.250.000 or 350,000
+var thousands = regexp.MustCompile(`^\d{1,3}([.,]\d{3})+$`)
+
+// ParseRemittanceFile reads a carrier remittance CSV
+// Columns (header row, any order): order_number, amount, and optionally tracking_code.
+// Amounts are in đồng; carriers often write them with thousands separators.
+func ParseRemittanceFile(r io.Reader) ([]RemittanceRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read remittance file: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("remittance file has no data rows")
+	}
+
+	header := make(map[string]int)
+	for i, name := range records[0] {
+		header[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := header["order_number"]; !ok {
+		return nil, fmt.Errorf("remittance file must have an order_number column")
+	}
+	if _, ok := header["amount"]; !ok {
+		return nil, fmt.Errorf("remittance file must have an amount column")
+	}
+	get := func(record []string, col string) string {
+		if i, ok := header[col]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []RemittanceRow
+	for i, record := range records[1:] {
+		line := i + 2
+
+		number := strings.ToUpper(get(record, "order_number"))
+		if number == "" {
+			continue // Blank line or carrier subtotal
+		}
+		raw := strings.ReplaceAll(get(record, "amount"), " ", "")
+		if thousands.MatchString(raw) {
+			raw = strings.NewReplacer(".", "", ",", "").Replace(raw)
+		}
+		amount, err := money.Parse(raw, money.Default)
+		if err != nil || amount.IsNegative() {
+			return nil, fmt.Errorf("line %d: invalid amount", line)
+		}
+		rows = append(rows, RemittanceRow{
+			LineNo:       line,
+			OrderNumber:  number,
+			TrackingCode: get(record, "tracking_code"),
+			Amount:       amount,
+		})
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("remittance file has no data rows")
+	}
+	return rows, nil
+}
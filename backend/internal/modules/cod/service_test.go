@@ -0,0 +1,122 @@
+package cod_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go-ecommerce/internal/database/dbtest"
+	"go-ecommerce/internal/modules/cod"
+	"go-ecommerce/internal/modules/order"
+	"go-ecommerce/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// staleOrders reports every order as delivered, as if it changed after being loaded
+type staleOrders struct {
+	cod.OrderSource
+}
+
+func (s staleOrders) GetByNumbers(ctx context.Context, numbers []string) (map[string]*cod.OrderInfo, error) {
+	orders, err := s.OrderSource.GetByNumbers(ctx, numbers)
+	for _, o := range orders {
+		o.Delivered = true
+	}
+	return orders, err
+}
+
+func TestImport(t *testing.T) {
+	const codAmount = 215000
+	tests := []struct {
+		name     string
+		status   order.OrderStatus
+		method   order.PaymentMethod
+		amount   int64 // Remitted
+		stale    bool  // Order status read before it changed
+		twice    bool  // Same line imported again
+		want     cod.LineStatus
+		wantPaid bool
+	}{
+		{name: "delivered", status: order.StatusDelivered, want: cod.LineMatched, wantPaid: true},
+		{name: "pending", status: order.StatusPending, want: cod.LineNotDelivered},
+		{name: "confirmed", status: order.StatusConfirmed, want: cod.LineNotDelivered},
+		{name: "packed", status: order.StatusPacked, want: cod.LineNotDelivered},
+		{name: "shipped", status: order.StatusShipped, want: cod.LineNotDelivered},
+		{name: "cancelled", status: order.StatusCancelled, want: cod.LineNotDelivered},
+		{name: "returned", status: order.StatusReturned, want: cod.LineNotDelivered},
+		{name: "shipped but read as delivered", status: order.StatusShipped, stale: true, want: cod.LineNotDelivered},
+		{name: "wrong amount", status: order.StatusDelivered, amount: codAmount - 1000, want: cod.LineAmountMismatch},
+		{name: "paid online", status: order.StatusDelivered, method: order.PaymentOnline, want: cod.LineNotCOD},
+		{name: "remitted twice", status: order.StatusDelivered, twice: true, want: cod.LineAlreadyPaid, wantPaid: true},
+	}
+
+	for idx, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := dbtest.Open(t, &order.Order{}, &order.OrderItem{}, &order.OrderStatusHistory{},
+				&cod.Rule{}, &cod.Remittance{}, &cod.RemittanceLine{})
+
+			method := tt.method
+			if method == "" {
+				method = order.PaymentCOD
+			}
+			o := order.Order{
+				Number:         fmt.Sprintf("DH261019-%05d", idx+1),
+				UserID:         uuid.New(),
+				Status:         tt.status,
+				ShippingName:   "Nguyễn Văn A",
+				ShippingPhone:  "0901234567",
+				ShippingStreet: "1 Lê Lợi",
+				ShippingCity:   "Hồ Chí Minh",
+				Subtotal:       money.VNDOf(200000),
+				Total:          money.VNDOf(codAmount),
+				PaymentMethod:  method,
+				CODFee:         money.VNDOf(15000),
+				CODAmount:      money.VNDOf(codAmount),
+			}
+			if method != order.PaymentCOD {
+				o.CODFee, o.CODAmount = money.VNDOf(0), money.VNDOf(0)
+			}
+			if err := db.Create(&o).Error; err != nil {
+				t.Fatalf("create order: %v", err)
+			}
+
+			var orders cod.OrderSource = cod.NewOrderRepoAdapter(order.NewRepository(db))
+			if tt.stale {
+				orders = staleOrders{orders}
+			}
+			svc := cod.NewService(cod.NewRepository(db), orders, order.NewPaidMarker())
+
+			amount := tt.amount
+			if amount == 0 {
+				amount = codAmount
+			}
+			rows := []cod.RemittanceRow{{LineNo: 2, OrderNumber: o.Number, TrackingCode: "GHN123", Amount: money.VNDOf(amount)}}
+			imports := 1
+			if tt.twice {
+				imports = 2
+			}
+			var remittance *cod.Remittance
+			for range imports {
+				var err error
+				if remittance, err = svc.Import(context.Background(), "GHN", "ghn.csv", rows, "admin"); err != nil {
+					t.Fatalf("Import: %v", err)
+				}
+			}
+
+			if len(remittance.Lines) != 1 || remittance.Lines[0].Status != tt.want {
+				t.Fatalf("lines = %+v, want one %s", remittance.Lines, tt.want)
+			}
+			var got order.Order
+			if err := db.First(&got, o.ID).Error; err != nil {
+				t.Fatalf("load order: %v", err)
+			}
+			if (got.PaidAt != nil) != tt.wantPaid {
+				t.Errorf("paid_at = %v, want paid: %v", got.PaidAt, tt.wantPaid)
+			}
+			if got.Status != tt.status {
+				t.Errorf("status = %s, want %s unchanged", got.Status, tt.status)
+			}
+		})
+	}
+}
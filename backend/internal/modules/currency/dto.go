@@ -0,0 +1,17 @@
+package currency
+
+import "go-ecommerce/pkg/money"
+
+// SetRateRequest - Request body for setting an exchange rate
+// Rounding defaults to the nearest minor unit.
+type SetRateRequest struct {
+	Rate         float64      `json:"rate" binding:"required,gt=0"`
+	RoundingMode RoundingMode `json:"rounding_mode" binding:"omitempty,oneof=nearest up down"`
+	RoundingStep int64        `json:"rounding_step" binding:"omitempty,min=1"`
+}
+
+// CurrencyListResponse - Currencies a customer can pick on the storefront
+type CurrencyListResponse struct {
+	Base  money.Currency `json:"base"`
+	Rates []ExchangeRate `json:"rates"`
+}
@@ -0,0 +1,39 @@
+package currency
+
+import (
+	"time"
+
+	"go-ecommerce/pkg/money"
+)
+
+// RoundingMode Enum
+type RoundingMode string
+
+const (
+	RoundNearest RoundingMode = "nearest"
+	RoundUp      RoundingMode = "up"
+	RoundDown    RoundingMode = "down"
+)
+
+// Rate sources
+const (
+	SourceManual = "manual"
+	SourceFile   = "file"
+)
+
+// ExchangeRate entity - value of one unit of a foreign currency in the base currency
+// Rate 25400 for USD means 1 USD = 25.400 ₫. Converted prices are rounded with
+// RoundingMode to a multiple of RoundingStep minor units (1 = cent, 100 = whole dollar).
+type ExchangeRate struct {
+	Code         money.Currency `gorm:"type:varchar(3);primaryKey" json:"code"`
+	Rate         float64        `gorm:"type:numeric(20,8);not null;check:rate > 0" json:"rate"`
+	RoundingMode RoundingMode   `gorm:"type:varchar(10);not null" json:"rounding_mode"`
+	RoundingStep int64          `gorm:"not null;check:rounding_step > 0" json:"rounding_step"`
+	Source       string         `gorm:"type:varchar(10);not null" json:"source"`
+	UpdatedBy    string         `gorm:"type:varchar(36)" json:"updated_by"` // Admin user ID, empty when loaded at startup
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
@@ -0,0 +1,122 @@
+package currency
+
+import (
+	"net/http"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles currency HTTP requests
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new currency handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetAll handles GET /currencies and GET /admin/currencies
+// @Summary Danh sách tiền tệ
+// @Description Tiền tệ hiển thị được trên cửa hàng; giá vẫn thanh toán bằng VND
+// @Tags Currencies
+// @Produce json
+// @Success 200 {object} CurrencyListResponse
+// @Router /currencies [get]
+func (h *Handler) GetAll(c *gin.Context) {
+	res, err := h.service.GetAll(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách tiền tệ"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// SetRate handles PUT /admin/currencies/:code
+// @Summary Cập nhật tỷ giá
+// @Tags Currencies
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Mã tiền tệ, ví dụ USD"
+// @Param request body SetRateRequest true "Tỷ giá"
+// @Success 200 {object} ExchangeRate
+// @Router /admin/currencies/{code} [put]
+func (h *Handler) SetRate(c *gin.Context) {
+	var req SetRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.SetRate(c.Request.Context(), c.Param("code"), req, c.GetString("userID"))
+	if err != nil {
+		if err == errors.ErrUnsupportedCurrency {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Mã tiền tệ không hợp lệ"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cập nhật tỷ giá thành công",
+		"data":    res,
+	})
+}
+
+// DeleteRate handles DELETE /admin/currencies/:code
+func (h *Handler) DeleteRate(c *gin.Context) {
+	if err := h.service.DeleteRate(c.Request.Context(), c.Param("code")); err != nil {
+		if err == errors.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy tiền tệ"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Xóa tiền tệ thành công"})
+}
+
+// Import handles POST /admin/currencies/import (multipart/form-data, field "file")
+// @Summary Nhập tỷ giá từ file CSV
+// @Description Cột: code, rate, rounding_mode (tùy chọn), rounding_step (tùy chọn)
+// @Tags Currencies
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "File .csv"
+// @Success 200 {array} ExchangeRate
+// @Router /admin/currencies/import [post]
+func (h *Handler) Import(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to open file"})
+		return
+	}
+	defer file.Close()
+
+	rates, err := ParseRatesFile(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.service.ImportRates(c.Request.Context(), rates, c.GetString("userID")); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Nhập tỷ giá thành công",
+		"data":    rates,
+	})
+}
@@ -0,0 +1,55 @@
+package currency
+
+import (
+	"context"
+
+	"go-ecommerce/pkg/money"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository interface
+type Repository interface {
+	GetAll(ctx context.Context) ([]ExchangeRate, error)
+	GetByCode(ctx context.Context, code money.Currency) (*ExchangeRate, error)
+	Save(ctx context.Context, rates ...ExchangeRate) error
+	Delete(ctx context.Context, code money.Currency) (bool, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new exchange rate repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) GetAll(ctx context.Context) ([]ExchangeRate, error) {
+	var rates []ExchangeRate
+	err := r.db.WithContext(ctx).Order("code ASC").Find(&rates).Error
+	return rates, err
+}
+
+func (r *repository) GetByCode(ctx context.Context, code money.Currency) (*ExchangeRate, error) {
+	var rate ExchangeRate
+	err := r.db.WithContext(ctx).Where("code = ?", code).First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+// Save inserts or replaces rates in one statement, so a file loads all or nothing
+func (r *repository) Save(ctx context.Context, rates ...ExchangeRate) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		UpdateAll: true,
+	}).Create(&rates).Error
+}
+
+func (r *repository) Delete(ctx context.Context, code money.Currency) (bool, error) {
+	result := r.db.WithContext(ctx).Where("code = ?", code).Delete(&ExchangeRate{})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,229 @@
+package currency
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/pkg/money"
+)
+
+var codePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Service interface
+// Prices are only converted for display; orders always settle in money.Default.
+type Service interface {
+	GetAll(ctx context.Context) (*CurrencyListResponse, error)
+	SetRate(ctx context.Context, code string, req SetRateRequest, actor string) (*ExchangeRate, error)
+	DeleteRate(ctx context.Context, code string) error
+	ImportRates(ctx context.Context, rates []ExchangeRate, actor string) error
+
+	// Converter returns a function converting base prices to the given currency
+	Converter(ctx context.Context, code string) (func(money.Money) money.Money, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new currency service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) GetAll(ctx context.Context) (*CurrencyListResponse, error) {
+	rates, err := s.repo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &CurrencyListResponse{Base: money.Default, Rates: rates}, nil
+}
+
+func (s *service) SetRate(ctx context.Context, code string, req SetRateRequest, actor string) (*ExchangeRate, error) {
+	rate, err := newRate(code, req.Rate, req.RoundingMode, req.RoundingStep)
+	if err != nil {
+		return nil, errors.ErrUnsupportedCurrency
+	}
+	rate.Source = SourceManual
+	rate.UpdatedBy = actor
+
+	if err := s.repo.Save(ctx, *rate); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByCode(ctx, rate.Code)
+}
+
+func (s *service) DeleteRate(ctx context.Context, code string) error {
+	deleted, err := s.repo.Delete(ctx, money.Currency(strings.ToUpper(code)))
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ImportRates saves rates read by ParseRatesFile, all or nothing
+func (s *service) ImportRates(ctx context.Context, rates []ExchangeRate, actor string) error {
+	for i := range rates {
+		rates[i].Source = SourceFile
+		rates[i].UpdatedBy = actor
+	}
+	return s.repo.Save(ctx, rates...)
+}
+
+// ParseRatesFile reads rates from CSV with the header "code,rate[,rounding_mode,rounding_step]"
+// Any invalid row rejects the whole file.
+func ParseRatesFile(r io.Reader) ([]ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read rates file: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, fmt.Errorf("rates file has no data rows")
+	}
+
+	header := make(map[string]int)
+	for i, name := range records[0] {
+		header[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := header["code"]; !ok {
+		return nil, fmt.Errorf("rates file must have a code column")
+	}
+	if _, ok := header["rate"]; !ok {
+		return nil, fmt.Errorf("rates file must have a rate column")
+	}
+	get := func(record []string, col string) string {
+		if i, ok := header[col]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rates []ExchangeRate
+	seen := make(map[money.Currency]bool)
+	for i, record := range records[1:] {
+		line := i + 2
+
+		value, err := strconv.ParseFloat(get(record, "rate"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid rate", line)
+		}
+		var step int64
+		if v := get(record, "rounding_step"); v != "" {
+			if step, err = strconv.ParseInt(v, 10, 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid rounding_step", line)
+			}
+		}
+
+		rate, err := newRate(get(record, "code"), value, RoundingMode(get(record, "rounding_mode")), step)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if seen[rate.Code] {
+			return nil, fmt.Errorf("line %d: duplicate currency %s", line, rate.Code)
+		}
+		seen[rate.Code] = true
+		rates = append(rates, *rate)
+	}
+	return rates, nil
+}
+
+// Converter looks the rate up once so a whole product list converts with one query
+func (s *service) Converter(ctx context.Context, code string) (func(money.Money) money.Money, error) {
+	to := money.Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if to == "" || to == money.Default {
+		return func(m money.Money) money.Money { return m }, nil
+	}
+
+	rate, err := s.repo.GetByCode(ctx, to)
+	if err != nil {
+		return nil, errors.ErrUnsupportedCurrency
+	}
+	return func(m money.Money) money.Money { return Convert(m, rate) }, nil
+}
+
+// Convert turns a base-currency amount into rate.Code, rounded by the rate's rules
+func Convert(amount money.Money, rate *ExchangeRate) money.Money {
+	// minor units in target = amount / 10^baseExp / rate * 10^targetExp
+	r := new(big.Rat).SetInt64(amount.Amount)
+	r.Mul(r, pow10(rate.Code.Exponent()-money.Default.Exponent()))
+	r.Quo(r, new(big.Rat).SetFloat64(rate.Rate))
+
+	step := big.NewInt(rate.RoundingStep)
+	r.Quo(r, new(big.Rat).SetInt(step))
+
+	// Div is Euclidean, i.e. floor for a positive denominator
+	num, den := r.Num(), r.Denom()
+	var steps *big.Int
+	switch rate.RoundingMode {
+	case RoundDown:
+		steps = new(big.Int).Div(num, den)
+	case RoundUp:
+		steps = new(big.Int).Div(num, den)
+		if new(big.Int).Mod(num, den).Sign() != 0 {
+			steps.Add(steps, big.NewInt(1))
+		}
+	default:
+		// floor(x + 1/2)
+		twice := new(big.Int).Add(new(big.Int).Mul(num, big.NewInt(2)), den)
+		steps = new(big.Int).Div(twice, new(big.Int).Mul(den, big.NewInt(2)))
+	}
+
+	return money.New(steps.Mul(steps, step).Int64(), rate.Code)
+}
+
+// newRate validates a rate and fills in rounding defaults
+func newRate(code string, value float64, mode RoundingMode, step int64) (*ExchangeRate, error) {
+	cur := money.Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if !codePattern.MatchString(string(cur)) || cur == money.Default {
+		return nil, fmt.Errorf("code must be a 3-letter currency code other than %s", money.Default)
+	}
+	// ParseFloat accepts NaN and Inf, which big.Rat cannot convert with
+	if math.IsNaN(value) || math.IsInf(value, 0) || value <= 0 {
+		return nil, fmt.Errorf("rate must be greater than 0")
+	}
+
+	switch mode {
+	case "":
+		mode = RoundNearest
+	case RoundNearest, RoundUp, RoundDown:
+	default:
+		return nil, fmt.Errorf("rounding_mode must be nearest, up or down")
+	}
+	if step == 0 {
+		step = 1
+	}
+	if step < 0 {
+		return nil, fmt.Errorf("rounding_step must be at least 1")
+	}
+
+	return &ExchangeRate{Code: cur, Rate: value, RoundingMode: mode, RoundingStep: step}, nil
+}
+
+func pow10(exp int) *big.Rat {
+	p := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(exp))), nil)
+	if exp < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), p)
+	}
+	return new(big.Rat).SetInt(p)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
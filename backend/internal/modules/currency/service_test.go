@@ -0,0 +1,90 @@
+package currency
+
+import (
+	"strings"
+	"testing"
+
+	"go-ecommerce/pkg/money"
+)
+
+func TestParseRatesFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    []ExchangeRate
+		wantErr string
+	}{
+		{
+			name: "defaults",
+			file: "code,rate\nusd,25000\n",
+			want: []ExchangeRate{{Code: "USD", Rate: 25000, RoundingMode: RoundNearest, RoundingStep: 1}},
+		},
+		{
+			name: "rounding columns",
+			file: "code,rate,rounding_mode,rounding_step\nUSD,25000,up,100\nJPY,170,down,\n",
+			want: []ExchangeRate{
+				{Code: "USD", Rate: 25000, RoundingMode: RoundUp, RoundingStep: 100},
+				{Code: "JPY", Rate: 170, RoundingMode: RoundDown, RoundingStep: 1},
+			},
+		},
+		{name: "no rate column", file: "code\nUSD\n", wantErr: "must have a rate column"},
+		{name: "no data rows", file: "code,rate\n", wantErr: "no data rows"},
+		{name: "zero rate", file: "code,rate\nUSD,0\n", wantErr: "line 2: rate must be greater than 0"},
+		{name: "negative rate", file: "code,rate\nUSD,-1\n", wantErr: "line 2: rate must be greater than 0"},
+		{name: "NaN rate", file: "code,rate\nUSD,NaN\n", wantErr: "line 2: rate must be greater than 0"},
+		{name: "Inf rate", file: "code,rate\nUSD,25000\nEUR,Inf\n", wantErr: "line 3: rate must be greater than 0"},
+		{name: "+Inf rate", file: "code,rate\nUSD,+Inf\n", wantErr: "line 2: rate must be greater than 0"},
+		{name: "-Inf rate", file: "code,rate\nUSD,-Inf\n", wantErr: "line 2: rate must be greater than 0"},
+		{name: "base currency", file: "code,rate\nVND,1\n", wantErr: "line 2: code must be"},
+		{name: "duplicate currency", file: "code,rate\nUSD,25000\nusd,25100\n", wantErr: "line 3: duplicate currency USD"},
+		{name: "bad rounding mode", file: "code,rate,rounding_mode\nUSD,25000,half\n", wantErr: "line 2: rounding_mode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRatesFile(strings.NewReader(tt.file))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d rates, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				g, w := got[i], tt.want[i]
+				if g.Code != w.Code || g.Rate != w.Rate || g.RoundingMode != w.RoundingMode || g.RoundingStep != w.RoundingStep {
+					t.Errorf("rate %d = %+v, want %+v", i, g, w)
+				}
+			}
+		})
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount int64
+		rate   ExchangeRate
+		want   int64
+	}{
+		{name: "nearest cent", amount: 150000, rate: ExchangeRate{Code: "USD", Rate: 25000, RoundingMode: RoundNearest, RoundingStep: 1}, want: 600},
+		{name: "half rounds up", amount: 125, rate: ExchangeRate{Code: "USD", Rate: 25000, RoundingMode: RoundNearest, RoundingStep: 1}, want: 1},
+		{name: "up to whole dollar", amount: 150001, rate: ExchangeRate{Code: "USD", Rate: 25000, RoundingMode: RoundUp, RoundingStep: 100}, want: 700},
+		{name: "down to whole dollar", amount: 174999, rate: ExchangeRate{Code: "USD", Rate: 25000, RoundingMode: RoundDown, RoundingStep: 100}, want: 600},
+		{name: "no minor units", amount: 17000, rate: ExchangeRate{Code: "JPY", Rate: 170, RoundingMode: RoundNearest, RoundingStep: 1}, want: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Convert(money.VNDOf(tt.amount), &tt.rate)
+			if got.Amount != tt.want || got.Currency != tt.rate.Code {
+				t.Errorf("Convert = %d %s, want %d %s", got.Amount, got.Currency, tt.want, tt.rate.Code)
+			}
+		})
+	}
+}
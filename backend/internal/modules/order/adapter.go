@@ -0,0 +1,92 @@
+package order
+
+import (
+	"context"
+
+	"go-ecommerce/internal/modules/cart"
+	"go-ecommerce/internal/modules/user"
+	"go-ecommerce/internal/shared/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserRepoAdapter adapts user.Repository to AddressGetter
+type UserRepoAdapter struct {
+	repo user.Repository
+}
+
+func NewUserRepoAdapter(repo user.Repository) *UserRepoAdapter {
+	return &UserRepoAdapter{repo: repo}
+}
+
+func (a *UserRepoAdapter) GetAddress(ctx context.Context, userID uuid.UUID, id uint) (*AddressInfo, error) {
+	var (
+		address *user.Address
+		err     error
+	)
+	if id == 0 {
+		address, err = a.repo.GetDefaultAddress(ctx, userID)
+	} else {
+		address, err = a.repo.GetAddress(ctx, userID, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &AddressInfo{
+		ID:             address.ID,
+		RecipientName:  address.RecipientName,
+		RecipientPhone: address.RecipientPhone,
+		Street:         address.Street,
+		Ward:           address.Ward,
+		District:       address.District,
+		City:           address.City,
+	}, nil
+}
+
+// CartServiceAdapter adapts cart.Service to CartSource
+type CartServiceAdapter struct {
+	service cart.Service
+}
+
+func NewCartServiceAdapter(service cart.Service) *CartServiceAdapter {
+	return &CartServiceAdapter{service: service}
+}
+
+// GetLines prices the cart like GET /cart does; any warning blocks checkout so the
+// customer never pays a price or gets a quantity they have not seen.
+func (a *CartServiceAdapter) GetLines(ctx context.Context, userID uuid.UUID) ([]CartLine, error) {
+	res, err := a.service.Get(ctx, cart.Owner{UserID: &userID})
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Warnings) > 0 {
+		return nil, errors.ErrCartChanged
+	}
+
+	lines := make([]CartLine, 0, len(res.Items))
+	for _, item := range res.Items {
+		lines = append(lines, CartLine{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Name:      item.Name,
+			Size:      item.Variant,
+			SKU:       item.SKU,
+			ImageURL:  item.ImageURL,
+			UnitPrice: item.UnitPrice,
+			Quantity:  item.Quantity,
+
+			Availability:      item.Availability,
+			BackorderQuantity: item.BackorderQuantity,
+			AvailableAt:       item.AvailableAt,
+		})
+	}
+	return lines, nil
+}
+
+// Clear deletes the lines of the user's cart; the cart row itself is kept
+func (a *CartServiceAdapter) Clear(tx *gorm.DB, userID uuid.UUID) error {
+	carts := tx.Session(&gorm.Session{NewDB: true}).Model(&cart.Cart{}).Select("id").Where("user_id = ?", userID)
+	return tx.Where("cart_id IN (?)", carts).Delete(&cart.CartItem{}).Error
+}
@@ -0,0 +1,195 @@
+package order
+
+import (
+	"time"
+
+	"go-ecommerce/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// CheckoutRequest - Request body for placing an order from the cart
+type CheckoutRequest struct {
+	AddressID uint   `json:"address_id"` // Optional, 0 uses the default address
+	Note      string `json:"note" binding:"max=500"`
+
+	PaymentMethod PaymentMethod `json:"payment_method" binding:"omitempty,oneof=cod online"` // Default cod
+}
+
+// UpdateStatusRequest - Request body for moving an order to another status
+type UpdateStatusRequest struct {
+	Status OrderStatus `json:"status" binding:"required"`
+	Note   string      `json:"note" binding:"max=255"`
+}
+
+// CancelRequest - Request body for cancelling an order
+type CancelRequest struct {
+	Reason string `json:"reason" binding:"max=255"`
+}
+
+// OrderFilter - Filters for order listing
+type OrderFilter struct {
+	UserID *uuid.UUID
+	Status OrderStatus // Empty means any status
+}
+
+// UnpaidCOD - delivered COD order still waiting for the carrier's remittance
+type UnpaidCOD struct {
+	ID          uint        `json:"order_id"`
+	Number      string      `json:"number"`
+	CODAmount   money.Money `json:"cod_amount"`
+	DeliveredAt time.Time   `json:"delivered_at"`
+}
+
+// AddressInfo - address book entry used for shipping
+type AddressInfo struct {
+	ID             uint
+	RecipientName  string
+	RecipientPhone string
+	Street         string
+	Ward           string
+	District       string
+	City           string
+}
+
+// CartLine - priced cart line to turn into an order item
+type CartLine struct {
+	ProductID uint
+	VariantID uint
+	Name      string
+	Size      string
+	SKU       string
+	ImageURL  string
+	UnitPrice money.Money
+	Quantity  int
+
+	Availability      string // in_stock, backorder or preorder, as shown in the cart
+	BackorderQuantity int    // Units beyond stock the customer agreed to wait for
+	AvailableAt       *time.Time
+}
+
+// ShippingAddress - Address snapshot in responses
+type ShippingAddress struct {
+	Name     string `json:"name"`
+	Phone    string `json:"phone"`
+	Street   string `json:"street"`
+	Ward     string `json:"ward"`
+	District string `json:"district"`
+	City     string `json:"city"`
+}
+
+// OrderItemResponse - Response DTO
+type OrderItemResponse struct {
+	ID          uint        `json:"id"`
+	ProductID   uint        `json:"product_id"`
+	VariantID   uint        `json:"variant_id"`
+	WarehouseID uint        `json:"warehouse_id"`
+	ProductName string      `json:"product_name"`
+	SKU         string      `json:"sku"`
+	Size        string      `json:"size"`
+	ImageURL    string      `json:"image_url"`
+	UnitPrice   money.Money `json:"unit_price"`
+	Quantity    int         `json:"quantity"`
+	LineTotal   money.Money `json:"line_total"`
+
+	Availability      string     `json:"availability"`
+	BackorderQuantity int        `json:"backorder_quantity,omitempty"`
+	AvailableAt       *time.Time `json:"available_at,omitempty"`
+}
+
+// StatusHistoryResponse - Response DTO
+type StatusHistoryResponse struct {
+	FromStatus OrderStatus `json:"from_status,omitempty"`
+	ToStatus   OrderStatus `json:"to_status"`
+	Actor      string      `json:"actor"`
+	Note       string      `json:"note,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+// OrderResponse - Response DTO
+type OrderResponse struct {
+	ID           uint                `json:"id"`
+	Number       string              `json:"number"`
+	UserID       uuid.UUID           `json:"user_id"`
+	Status       OrderStatus         `json:"status"`
+	NextStatuses []OrderStatus       `json:"next_statuses"`
+	Shipping     ShippingAddress     `json:"shipping"`
+	Items        []OrderItemResponse `json:"items"`
+	ItemCount    int                 `json:"item_count"`
+	Subtotal     money.Money         `json:"subtotal"`
+	Total        money.Money         `json:"total"`
+	Note         string              `json:"note,omitempty"`
+
+	PaymentMethod PaymentMethod `json:"payment_method"`
+	CODFee        money.Money   `json:"cod_fee"`
+	CODAmount     money.Money   `json:"cod_amount"`
+	PaidAt        *time.Time    `json:"paid_at,omitempty"`
+
+	PayBefore *time.Time              `json:"pay_before,omitempty"` // Pending orders only, cancelled after that
+	History   []StatusHistoryResponse `json:"history,omitempty"`
+	CreatedAt time.Time               `json:"created_at"`
+	UpdatedAt time.Time               `json:"updated_at"`
+}
+
+// ToOrderResponse converts an Order to OrderResponse
+func ToOrderResponse(o *Order) *OrderResponse {
+	res := &OrderResponse{
+		ID:           o.ID,
+		Number:       o.Number,
+		UserID:       o.UserID,
+		Status:       o.Status,
+		NextStatuses: append([]OrderStatus{}, transitions[o.Status]...),
+		Shipping: ShippingAddress{
+			Name:     o.ShippingName,
+			Phone:    o.ShippingPhone,
+			Street:   o.ShippingStreet,
+			Ward:     o.ShippingWard,
+			District: o.ShippingDistrict,
+			City:     o.ShippingCity,
+		},
+		Items:     make([]OrderItemResponse, 0, len(o.Items)),
+		Subtotal:  o.Subtotal,
+		Total:     o.Total,
+		Note:      o.Note,
+		CreatedAt: o.CreatedAt,
+
+		PaymentMethod: o.PaymentMethod,
+		CODFee:        o.CODFee,
+		CODAmount:     o.CODAmount,
+		PaidAt:        o.PaidAt,
+		UpdatedAt:     o.UpdatedAt,
+	}
+	if o.Status == StatusPending {
+		res.PayBefore = o.ReservedUntil
+	}
+	for _, item := range o.Items {
+		res.Items = append(res.Items, OrderItemResponse{
+			ID:          item.ID,
+			ProductID:   item.ProductID,
+			VariantID:   item.VariantID,
+			WarehouseID: item.WarehouseID,
+			ProductName: item.ProductName,
+			SKU:         item.SKU,
+			Size:        item.Size,
+			ImageURL:    item.ImageURL,
+			UnitPrice:   item.UnitPrice,
+			Quantity:    item.Quantity,
+			LineTotal:   item.LineTotal,
+
+			Availability:      item.Availability,
+			BackorderQuantity: item.BackorderQuantity,
+			AvailableAt:       item.AvailableAt,
+		})
+		res.ItemCount += item.Quantity
+	}
+	for _, h := range o.History {
+		res.History = append(res.History, StatusHistoryResponse{
+			FromStatus: h.FromStatus,
+			ToStatus:   h.ToStatus,
+			Actor:      h.Actor,
+			Note:       h.Note,
+			CreatedAt:  h.CreatedAt,
+		})
+	}
+	return res
+}
@@ -0,0 +1,154 @@
+package order
+
+import (
+	"time"
+
+	"go-ecommerce/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// OrderStatus enum
+type OrderStatus string
+
+const (
+	StatusPending   OrderStatus = "pending"
+	StatusConfirmed OrderStatus = "confirmed"
+	StatusPacked    OrderStatus = "packed"
+	StatusShipped   OrderStatus = "shipped"
+	StatusDelivered OrderStatus = "delivered"
+	StatusCancelled OrderStatus = "cancelled"
+	StatusReturned  OrderStatus = "returned"
+)
+
+// transitions lists the statuses an order may move to from each status
+// Cancelling is possible until the parcel leaves; shipped orders can come back
+// (refused or undeliverable) and delivered ones can be returned.
+var transitions = map[OrderStatus][]OrderStatus{
+	StatusPending:   {StatusConfirmed, StatusCancelled},
+	StatusConfirmed: {StatusPacked, StatusCancelled},
+	StatusPacked:    {StatusShipped, StatusCancelled},
+	StatusShipped:   {StatusDelivered, StatusReturned},
+	StatusDelivered: {StatusReturned},
+}
+
+// CanTransitionTo reports whether the state machine allows s -> next
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range transitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// PaymentMethod enum
+type PaymentMethod string
+
+const (
+	PaymentCOD    PaymentMethod = "cod"    // Cash on delivery, collected by the carrier
+	PaymentOnline PaymentMethod = "online" // Paid through a payment gateway before confirmation
+)
+
+// ActorSystem is recorded for transitions made by background jobs
+const ActorSystem = "system"
+
+// Order entity
+// Shipping* fields are a copy of the address book entry at checkout, so editing
+// or deleting the address later does not change the order.
+type Order struct {
+	ID     uint        `gorm:"primaryKey" json:"id"`
+	Number string      `gorm:"type:varchar(20);uniqueIndex;not null" json:"number"` // e.g. DH261018-00042
+	UserID uuid.UUID   `gorm:"type:uuid;not null;index" json:"user_id"`
+	Status OrderStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+
+	AddressID        *uint  `json:"address_id"` // Address book entry it was copied from
+	ShippingName     string `gorm:"type:varchar(100);not null" json:"shipping_name"`
+	ShippingPhone    string `gorm:"type:varchar(20);not null" json:"shipping_phone"`
+	ShippingStreet   string `gorm:"type:varchar(255);not null" json:"shipping_street"`
+	ShippingWard     string `gorm:"type:varchar(100)" json:"shipping_ward"`
+	ShippingDistrict string `gorm:"type:varchar(100)" json:"shipping_district"`
+	ShippingCity     string `gorm:"type:varchar(100);not null" json:"shipping_city"`
+
+	Subtotal money.Money `gorm:"not null" json:"subtotal"`
+	Total    money.Money `gorm:"not null" json:"total"`
+	Note     string      `gorm:"type:varchar(500)" json:"note"` // Customer note for the shop
+
+	PaymentMethod PaymentMethod `gorm:"type:varchar(20);not null;default:'cod'" json:"payment_method"`
+	CODFee        money.Money   `gorm:"not null;default:0" json:"cod_fee"`    // Included in Total
+	CODAmount     money.Money   `gorm:"not null;default:0" json:"cod_amount"` // What the carrier collects, 0 unless COD
+	PaidAt        *time.Time    `gorm:"index" json:"paid_at"`                 // Gateway payment or carrier remittance received
+
+	// Stock is held until then; pending orders past it are cancelled automatically
+	ReservedUntil *time.Time `gorm:"index" json:"reserved_until"`
+
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Items   []OrderItem          `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+	History []OrderStatusHistory `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE" json:"history,omitempty"`
+}
+
+func (Order) TableName() string {
+	return "orders"
+}
+
+// OrderItem entity - immutable snapshot of a cart line at checkout
+// Product and variant IDs are kept for reference only; renaming or repricing
+// the product does not change past orders.
+type OrderItem struct {
+	ID          uint        `gorm:"primaryKey" json:"id"`
+	OrderID     uint        `gorm:"not null;index" json:"order_id"`
+	ProductID   uint        `gorm:"not null;index" json:"product_id"`
+	VariantID   uint        `gorm:"not null" json:"variant_id"`
+	WarehouseID uint        `gorm:"index" json:"warehouse_id"` // Ships the item, chosen at checkout
+	ProductName string      `gorm:"type:varchar(255);not null" json:"product_name"`
+	SKU         string      `gorm:"type:varchar(100)" json:"sku"`
+	Size        string      `gorm:"type:varchar(100)" json:"size"`
+	ImageURL    string      `gorm:"type:text" json:"image_url"`
+	UnitPrice   money.Money `gorm:"not null" json:"unit_price"`
+	Quantity    int         `gorm:"not null;check:quantity > 0" json:"quantity"`
+	LineTotal   money.Money `gorm:"not null" json:"line_total"`
+	CreatedAt   time.Time   `json:"created_at"`
+
+	// Labelling at checkout: units beyond stock ship when the variant is restocked
+	Availability      string     `gorm:"type:varchar(20);not null;default:'in_stock'" json:"availability"` // in_stock, backorder or preorder
+	BackorderQuantity int        `gorm:"not null;default:0" json:"backorder_quantity"`
+	AvailableAt       *time.Time `json:"available_at"`
+}
+
+// Availability values of an order item
+const (
+	AvailabilityInStock   = "in_stock"
+	AvailabilityBackorder = "backorder"
+	AvailabilityPreorder  = "preorder"
+)
+
+func (OrderItem) TableName() string {
+	return "order_items"
+}
+
+// OrderStatusHistory entity - one row per status change, including creation
+type OrderStatusHistory struct {
+	ID         uint        `gorm:"primaryKey" json:"id"`
+	OrderID    uint        `gorm:"not null;index" json:"order_id"`
+	FromStatus OrderStatus `gorm:"type:varchar(20)" json:"from_status"` // Empty for the creation entry
+	ToStatus   OrderStatus `gorm:"type:varchar(20);not null" json:"to_status"`
+	Actor      string      `gorm:"type:varchar(36);not null" json:"actor"` // User ID or "system"
+	Note       string      `gorm:"type:varchar(255)" json:"note"`
+	CreatedAt  time.Time   `json:"created_at"`
+}
+
+func (OrderStatusHistory) TableName() string {
+	return "order_status_histories"
+}
+
+// OrderSequence entity - per-day counter behind order numbers
+type OrderSequence struct {
+	Day  string `gorm:"type:varchar(6);primaryKey"` // yymmdd
+	Last int    `gorm:"not null"`
+}
+
+func (OrderSequence) TableName() string {
+	return "order_sequences"
+}
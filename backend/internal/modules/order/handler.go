@@ -0,0 +1,276 @@
+package order
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler handles order HTTP requests
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new order handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Checkout handles POST /orders/checkout
+// @Summary Đặt hàng
+// @Description Tạo đơn hàng từ giỏ hàng và một địa chỉ trong sổ địa chỉ (mặc định nếu không truyền address_id).
+// @Description Thanh toán khi nhận hàng (cod, mặc định) cộng phí thu hộ theo tỉnh/thành và được xác nhận ngay; online chờ thanh toán qua cổng.
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CheckoutRequest true "Thông tin đặt hàng"
+// @Success 201 {object} OrderResponse
+// @Failure 409 {object} map[string]string
+// @Router /orders/checkout [post]
+func (h *Handler) Checkout(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Checkout(c.Request.Context(), userID, req)
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy địa chỉ giao hàng"})
+		case errors.ErrEmptyCart:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Giỏ hàng trống"})
+		case errors.ErrCartChanged:
+			c.JSON(http.StatusConflict, gin.H{"error": "Giỏ hàng đã thay đổi về giá hoặc tồn kho, vui lòng kiểm tra lại"})
+		case errors.ErrInsufficientStock:
+			c.JSON(http.StatusConflict, gin.H{"error": "Một số sản phẩm vừa hết hàng, vui lòng kiểm tra lại giỏ hàng"})
+		case errors.ErrCODUnavailable:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tỉnh/thành này không hỗ trợ thanh toán khi nhận hàng, vui lòng thanh toán trực tuyến"})
+		case errors.ErrCODLimit:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Giá trị đơn hàng vượt hạn mức thanh toán khi nhận hàng, vui lòng thanh toán trực tuyến"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi đặt hàng"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Đặt hàng thành công",
+		"data":    res,
+	})
+}
+
+// GetMine handles GET /orders
+// @Summary Đơn hàng của tôi
+// @Tags Order
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} OrderResponse
+// @Router /orders [get]
+func (h *Handler) GetMine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GetMine(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// GetMineByID handles GET /orders/:id
+func (h *Handler) GetMineByID(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GetMineByID(c.Request.Context(), userID, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy đơn hàng"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// Cancel handles POST /orders/:id/cancel
+// @Summary Hủy đơn hàng
+// @Description Chỉ hủy được khi đơn chưa được đóng gói
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param request body CancelRequest false "Lý do hủy"
+// @Success 200 {object} OrderResponse
+// @Router /orders/{id}/cancel [post]
+func (h *Handler) Cancel(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req CancelRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	res, err := h.service.Cancel(c.Request.Context(), userID, id, req)
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy đơn hàng"})
+		case errors.ErrInvalidTransition:
+			c.JSON(http.StatusConflict, gin.H{"error": "Đơn hàng đã được đóng gói hoặc giao đi, không thể hủy"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hủy đơn hàng"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Hủy đơn hàng thành công",
+		"data":    res,
+	})
+}
+
+// GetAll handles GET /admin/orders
+// @Summary Danh sách đơn hàng (admin)
+// @Tags Order
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "pending, confirmed, packed, shipped, delivered, cancelled, returned"
+// @Success 200 {array} OrderResponse
+// @Router /admin/orders [get]
+func (h *Handler) GetAll(c *gin.Context) {
+	status := OrderStatus(c.Query("status"))
+	if status != "" && !validStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Trạng thái không hợp lệ"})
+		return
+	}
+
+	res, err := h.service.GetAll(c.Request.Context(), OrderFilter{Status: status})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// GetByID handles GET /admin/orders/:id
+func (h *Handler) GetByID(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy đơn hàng"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// UpdateStatus handles PUT /admin/orders/:id/status
+// @Summary Chuyển trạng thái đơn hàng
+// @Description pending → confirmed → packed → shipped → delivered; hủy được trước khi giao đi, hoàn hàng sau khi giao đi
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param request body UpdateStatusRequest true "Trạng thái mới"
+// @Success 200 {object} OrderResponse
+// @Failure 409 {object} map[string]string
+// @Router /admin/orders/{id}/status [put]
+func (h *Handler) UpdateStatus(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !validStatus(req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Trạng thái không hợp lệ"})
+		return
+	}
+
+	res, err := h.service.UpdateStatus(c.Request.Context(), id, req, c.GetString("userID"))
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy đơn hàng"})
+		case errors.ErrInvalidTransition:
+			c.JSON(http.StatusConflict, gin.H{"error": "Không thể chuyển đơn hàng sang trạng thái này"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi cập nhật đơn hàng"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cập nhật trạng thái thành công",
+		"data":    res,
+	})
+}
+
+func validStatus(status OrderStatus) bool {
+	switch status {
+	case StatusPending, StatusConfirmed, StatusPacked, StatusShipped, StatusDelivered, StatusCancelled, StatusReturned:
+		return true
+	}
+	return false
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
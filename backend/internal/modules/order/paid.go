@@ -0,0 +1,51 @@
+package order
+
+import (
+	"time"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"gorm.io/gorm"
+)
+
+// PaidMarker records that the money for an order has been received
+// It works inside the caller's transaction, e.g. with the payment or remittance that paid it.
+type PaidMarker struct{}
+
+func NewPaidMarker() *PaidMarker {
+	return &PaidMarker{}
+}
+
+// MarkPaid sets the order's paid time; false if it was already paid
+// Cancelled and returned orders are never marked: it returns ErrOrderClosed and
+// the caller keeps the money as to be refunded.
+func (m *PaidMarker) MarkPaid(tx *gorm.DB, orderID uint, paidAt time.Time) (bool, error) {
+	return markPaid(tx, orderID, paidAt, "status NOT IN ?", []OrderStatus{StatusCancelled, StatusReturned}, errors.ErrOrderClosed)
+}
+
+// MarkCollected is MarkPaid for cash on delivery
+// The carrier only holds the money once the order is delivered: for any other
+// status it returns ErrOrderNotDelivered and the order is not marked.
+func (m *PaidMarker) MarkCollected(tx *gorm.DB, orderID uint, paidAt time.Time) (bool, error) {
+	return markPaid(tx, orderID, paidAt, "status = ?", StatusDelivered, errors.ErrOrderNotDelivered)
+}
+
+// markPaid sets paid_at when the status matches; refused when it does not and the order is unpaid
+func markPaid(tx *gorm.DB, orderID uint, paidAt time.Time, status string, statusArg interface{}, refused error) (bool, error) {
+	result := tx.Model(&Order{}).
+		Where("id = ? AND paid_at IS NULL", orderID).
+		Where(status, statusArg).
+		UpdateColumn("paid_at", paidAt)
+	if result.Error != nil || result.RowsAffected > 0 {
+		return result.RowsAffected > 0, result.Error
+	}
+
+	var order Order
+	if err := tx.Select("id", "status", "paid_at").First(&order, orderID).Error; err != nil {
+		return false, err
+	}
+	if order.PaidAt == nil {
+		return false, refused
+	}
+	return false, nil
+}
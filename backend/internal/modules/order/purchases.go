@@ -0,0 +1,32 @@
+package order
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Purchases answers "did this customer buy this product" for reviews and Q&A
+// Only delivered orders count.
+type Purchases struct {
+	repo Repository
+}
+
+func NewPurchases(repo Repository) *Purchases {
+	return &Purchases{repo: repo}
+}
+
+// PurchasedItemIDs returns the delivered order items of the product, so a
+// customer can review once per delivered line
+func (p *Purchases) PurchasedItemIDs(ctx context.Context, userID uuid.UUID, productID uint) ([]uint, error) {
+	return p.repo.GetDeliveredItemIDs(ctx, userID, productID)
+}
+
+// HasPurchased reports whether the user received the product
+func (p *Purchases) HasPurchased(ctx context.Context, userID uuid.UUID, productID uint) (bool, error) {
+	ids, err := p.repo.GetDeliveredItemIDs(ctx, userID, productID)
+	if err != nil {
+		return false, err
+	}
+	return len(ids) > 0, nil
+}
@@ -0,0 +1,134 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository interface
+type Repository interface {
+	GetByID(ctx context.Context, id uint) (*Order, error)
+	GetAll(ctx context.Context, filter OrderFilter) ([]Order, error)
+	GetExpiredPending(ctx context.Context, now time.Time) ([]Order, error)
+
+	// Purchases, for reviews and Q&A
+	GetDeliveredItemIDs(ctx context.Context, userID uuid.UUID, productID uint) ([]uint, error)
+
+	// Cash on delivery
+	GetByNumbers(ctx context.Context, numbers []string) ([]Order, error)
+	GetUnpaidCOD(ctx context.Context, deliveredBefore time.Time) ([]UnpaidCOD, error)
+
+	// Transaction support
+	WithTransaction(fn func(*gorm.DB) error) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new order repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// WithTransaction executes a function within a transaction
+func (r *repository) WithTransaction(fn func(*gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
+func (r *repository) GetByID(ctx context.Context, id uint) (*Order, error) {
+	var order Order
+	err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("History", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC, id ASC") }).
+		First(&order, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// GetAll returns orders newest first, without status history
+func (r *repository) GetAll(ctx context.Context, filter OrderFilter) ([]Order, error) {
+	var orders []Order
+	query := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") })
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	err := query.Order("created_at DESC, id DESC").Find(&orders).Error
+	return orders, err
+}
+
+// GetExpiredPending returns pending orders whose stock reservation has run out
+func (r *repository) GetExpiredPending(ctx context.Context, now time.Time) ([]Order, error) {
+	var orders []Order
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND reserved_until < ?", StatusPending, now).
+		Order("id ASC").
+		Find(&orders).Error
+	return orders, err
+}
+
+// GetDeliveredItemIDs returns the user's order items of the product in delivered orders
+func (r *repository) GetDeliveredItemIDs(ctx context.Context, userID uuid.UUID, productID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&OrderItem{}).
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("orders.user_id = ? AND orders.status = ? AND order_items.product_id = ?", userID, StatusDelivered, productID).
+		Order("order_items.id ASC").
+		Pluck("order_items.id", &ids).Error
+	return ids, err
+}
+
+// GetByNumbers returns the orders with these numbers, without items
+func (r *repository) GetByNumbers(ctx context.Context, numbers []string) ([]Order, error) {
+	var orders []Order
+	if len(numbers) == 0 {
+		return orders, nil
+	}
+	err := r.db.WithContext(ctx).Where("number IN ?", numbers).Find(&orders).Error
+	return orders, err
+}
+
+// GetUnpaidCOD returns COD orders delivered before the cutoff whose money has not been remitted
+func (r *repository) GetUnpaidCOD(ctx context.Context, deliveredBefore time.Time) ([]UnpaidCOD, error) {
+	var orders []UnpaidCOD
+	delivered := r.db.Model(&OrderStatusHistory{}).
+		Select("order_id, MAX(created_at) AS delivered_at").
+		Where("to_status = ?", StatusDelivered).
+		Group("order_id")
+	err := r.db.WithContext(ctx).Model(&Order{}).
+		Select("orders.id, orders.number, orders.cod_amount, d.delivered_at").
+		Joins("JOIN (?) AS d ON d.order_id = orders.id", delivered).
+		Where("orders.status = ? AND orders.payment_method = ? AND orders.paid_at IS NULL", StatusDelivered, PaymentCOD).
+		Where("d.delivered_at < ?", deliveredBefore).
+		Order("d.delivered_at ASC, orders.id ASC").
+		Scan(&orders).Error
+	return orders, err
+}
+
+// nextNumber returns the next order number of the day, e.g. DH261018-00042
+// The counter row is upserted inside tx, so concurrent checkouts get distinct numbers.
+func nextNumber(tx *gorm.DB, now time.Time) (string, error) {
+	seq := OrderSequence{Day: now.Format("060102"), Last: 1}
+	err := tx.Clauses(
+		clause.OnConflict{
+			Columns:   []clause.Column{{Name: "day"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"last": gorm.Expr("order_sequences.last + 1")}),
+		},
+		clause.Returning{Columns: []clause.Column{{Name: "last"}}},
+	).Create(&seq).Error
+	if err != nil {
+		return "", fmt.Errorf("failed to allocate order number: %w", err)
+	}
+	return fmt.Sprintf("DH%s-%05d", seq.Day, seq.Last), nil
+}
@@ -0,0 +1,344 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AddressGetter interface for the user's address book
+type AddressGetter interface {
+	// GetAddress returns an address of the user; id 0 means the default address
+	GetAddress(ctx context.Context, userID uuid.UUID, id uint) (*AddressInfo, error)
+}
+
+// CartSource interface for the cart being checked out
+type CartSource interface {
+	// GetLines returns the available lines of the user's cart at current prices
+	// Returns ErrCartChanged while the cart shows warnings the customer has not dealt with.
+	GetLines(ctx context.Context, userID uuid.UUID) ([]CartLine, error)
+	// Clear empties the user's cart inside the checkout transaction
+	Clear(tx *gorm.DB, userID uuid.UUID) error
+}
+
+// StockUpdater interface for reserving variant stock inside a transaction
+type StockUpdater interface {
+	// Reserve returns the warehouse the stock was taken from, preferring the one serving province,
+	// and how many units are backordered
+	Reserve(tx *gorm.DB, orderID uint, province string, productID, variantID uint, quantity int, expiresAt time.Time) (uint, int, error)
+	Commit(tx *gorm.DB, orderID uint) error
+	Release(tx *gorm.DB, orderID uint) error
+}
+
+// CODPolicy interface for cash-on-delivery rules by province
+type CODPolicy interface {
+	// Quote returns the COD fee for an order of amount shipped to province
+	// Returns ErrCODUnavailable or ErrCODLimit when COD cannot be used.
+	Quote(ctx context.Context, province string, amount money.Money) (money.Money, error)
+}
+
+// Service interface
+type Service interface {
+	Checkout(ctx context.Context, userID uuid.UUID, req CheckoutRequest) (*OrderResponse, error)
+	GetMine(ctx context.Context, userID uuid.UUID) ([]OrderResponse, error)
+	GetMineByID(ctx context.Context, userID uuid.UUID, id uint) (*OrderResponse, error)
+	Cancel(ctx context.Context, userID uuid.UUID, id uint, req CancelRequest) (*OrderResponse, error)
+
+	// Admin
+	GetAll(ctx context.Context, filter OrderFilter) ([]OrderResponse, error)
+	GetByID(ctx context.Context, id uint) (*OrderResponse, error)
+	UpdateStatus(ctx context.Context, id uint, req UpdateStatusRequest, actor string) (*OrderResponse, error)
+
+	// ExpireUnconfirmed cancels pending orders whose reservation ran out
+	ExpireUnconfirmed(ctx context.Context) error
+}
+
+type service struct {
+	repo      Repository
+	addresses AddressGetter
+	carts     CartSource
+	stock     StockUpdater
+	cod       CODPolicy
+
+	reservationTTL time.Duration
+}
+
+// NewService creates a new order service
+// Stock of a pending order is held for reservationTTL, then the order is cancelled.
+func NewService(repo Repository, addresses AddressGetter, carts CartSource, stock StockUpdater, cod CODPolicy, reservationTTL time.Duration) Service {
+	return &service{repo: repo, addresses: addresses, carts: carts, stock: stock, cod: cod, reservationTTL: reservationTTL}
+}
+
+// Checkout turns the user's cart into an order and empties the cart
+// Lines are priced at the current effective price in VND; stock is reserved in
+// the same transaction, so a line that sold out meanwhile fails the whole checkout.
+// Online orders stay pending until paid; COD orders carry the province's COD fee
+// and are confirmed right away, as there is nothing to wait for.
+func (s *service) Checkout(ctx context.Context, userID uuid.UUID, req CheckoutRequest) (*OrderResponse, error) {
+	address, err := s.addresses.GetAddress(ctx, userID, req.AddressID)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	lines, err := s.carts.GetLines(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, errors.ErrEmptyCart
+	}
+	// Reserve in a fixed order so concurrent checkouts cannot deadlock
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].ProductID != lines[j].ProductID {
+			return lines[i].ProductID < lines[j].ProductID
+		}
+		return lines[i].VariantID < lines[j].VariantID
+	})
+	reservedUntil := time.Now().Add(s.reservationTTL)
+	method := req.PaymentMethod
+	if method == "" {
+		method = PaymentCOD
+	}
+
+	order := &Order{
+		UserID:           userID,
+		Status:           StatusPending,
+		AddressID:        &address.ID,
+		ShippingName:     address.RecipientName,
+		ShippingPhone:    address.RecipientPhone,
+		ShippingStreet:   address.Street,
+		ShippingWard:     address.Ward,
+		ShippingDistrict: address.District,
+		ShippingCity:     address.City,
+		Subtotal:         money.New(0, money.Default),
+		Note:             req.Note,
+		ReservedUntil:    &reservedUntil,
+		PaymentMethod:    method,
+		CODFee:           money.New(0, money.Default),
+		CODAmount:        money.New(0, money.Default),
+	}
+	for _, line := range lines {
+		item := OrderItem{
+			ProductID:   line.ProductID,
+			VariantID:   line.VariantID,
+			ProductName: line.Name,
+			SKU:         line.SKU,
+			Size:        line.Size,
+			ImageURL:    line.ImageURL,
+			UnitPrice:   line.UnitPrice,
+			Quantity:    line.Quantity,
+			LineTotal:   line.UnitPrice.Mul(int64(line.Quantity)),
+
+			Availability:      line.Availability,
+			BackorderQuantity: line.BackorderQuantity,
+			AvailableAt:       line.AvailableAt,
+		}
+		if item.Availability == "" {
+			item.Availability = AvailabilityInStock
+		}
+		order.Items = append(order.Items, item)
+		order.Subtotal = order.Subtotal.Add(item.LineTotal)
+	}
+	order.Total = order.Subtotal // No shipping fee or discounts yet
+	if method == PaymentCOD {
+		fee, err := s.cod.Quote(ctx, address.City, order.Subtotal)
+		if err != nil {
+			return nil, err
+		}
+		order.CODFee = fee
+		order.Total = order.Total.Add(fee)
+		order.CODAmount = order.Total
+		order.Status = StatusConfirmed
+		order.ReservedUntil = nil
+	}
+
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+
+		number, err := nextNumber(tx, time.Now())
+		if err != nil {
+			return err
+		}
+		order.Number = number
+
+		if err := tx.Create(order).Error; err != nil {
+			return fmt.Errorf("failed to create order: %w", err)
+		}
+		for i := range order.Items {
+			item := &order.Items[i]
+			warehouseID, backordered, err := s.stock.Reserve(tx, order.ID, order.ShippingCity, item.ProductID, item.VariantID, item.Quantity, reservedUntil)
+			if err != nil {
+				return err
+			}
+			// Never make the customer wait for more units than the cart showed
+			if backordered > item.BackorderQuantity {
+				return errors.ErrCartChanged
+			}
+			item.WarehouseID = warehouseID
+			item.BackorderQuantity = backordered
+			if backordered == 0 {
+				item.Availability = AvailabilityInStock
+				item.AvailableAt = nil
+			}
+			err = tx.Model(item).Updates(map[string]interface{}{
+				"warehouse_id":       warehouseID,
+				"availability":       item.Availability,
+				"backorder_quantity": backordered,
+				"available_at":       item.AvailableAt,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		if err := recordTransition(tx, order.ID, "", StatusPending, userID.String(), ""); err != nil {
+			return err
+		}
+		if order.Status == StatusConfirmed {
+			if err := s.stock.Commit(tx, order.ID); err != nil {
+				return err
+			}
+			if err := recordTransition(tx, order.ID, StatusPending, StatusConfirmed, ActorSystem, "Thanh toán khi nhận hàng"); err != nil {
+				return err
+			}
+		}
+		return s.carts.Clear(tx, userID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, order.ID)
+}
+
+func (s *service) GetMine(ctx context.Context, userID uuid.UUID) ([]OrderResponse, error) {
+	return s.GetAll(ctx, OrderFilter{UserID: &userID})
+}
+
+// GetMineByID returns an order of the user; other users' orders are not found
+func (s *service) GetMineByID(ctx context.Context, userID uuid.UUID, id uint) (*OrderResponse, error) {
+	order, err := s.repo.GetByID(ctx, id)
+	if err != nil || order.UserID != userID {
+		return nil, errors.ErrRecordNotFound
+	}
+	return ToOrderResponse(order), nil
+}
+
+// Cancel lets customers cancel their own order until it is packed
+func (s *service) Cancel(ctx context.Context, userID uuid.UUID, id uint, req CancelRequest) (*OrderResponse, error) {
+	order, err := s.repo.GetByID(ctx, id)
+	if err != nil || order.UserID != userID {
+		return nil, errors.ErrRecordNotFound
+	}
+	if order.Status != StatusPending && order.Status != StatusConfirmed {
+		return nil, errors.ErrInvalidTransition
+	}
+	return s.changeStatus(ctx, order, StatusCancelled, userID.String(), req.Reason)
+}
+
+func (s *service) GetAll(ctx context.Context, filter OrderFilter) ([]OrderResponse, error) {
+	orders, err := s.repo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]OrderResponse, 0, len(orders))
+	for i := range orders {
+		res = append(res, *ToOrderResponse(&orders[i]))
+	}
+	return res, nil
+}
+
+func (s *service) GetByID(ctx context.Context, id uint) (*OrderResponse, error) {
+	order, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	return ToOrderResponse(order), nil
+}
+
+// UpdateStatus moves an order along the state machine
+func (s *service) UpdateStatus(ctx context.Context, id uint, req UpdateStatusRequest, actor string) (*OrderResponse, error) {
+	order, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	return s.changeStatus(ctx, order, req.Status, actor, req.Note)
+}
+
+// ExpireUnconfirmed cancels pending orders past their reservation and releases the stock
+// Orders confirmed concurrently are skipped by changeStatus.
+func (s *service) ExpireUnconfirmed(ctx context.Context) error {
+	orders, err := s.repo.GetExpiredPending(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for i := range orders {
+		_, err := s.changeStatus(ctx, &orders[i], StatusCancelled, ActorSystem, "Hết thời gian giữ hàng")
+		if err != nil && err != errors.ErrInvalidTransition {
+			return err
+		}
+	}
+	return nil
+}
+
+// changeStatus applies one transition and records it
+// Confirming keeps the reserved stock for good; cancelling releases it.
+// Returned goods are not restocked automatically, they are checked first.
+func (s *service) changeStatus(ctx context.Context, order *Order, to OrderStatus, actor, note string) (*OrderResponse, error) {
+	if !order.Status.CanTransitionTo(to) {
+		return nil, errors.ErrInvalidTransition
+	}
+
+	err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+
+		// Only move from the status we read, so concurrent updates cannot both apply
+		result := tx.Model(&Order{}).
+			Where("id = ? AND status = ?", order.ID, order.Status).
+			Updates(map[string]interface{}{"status": to, "updated_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrInvalidTransition
+		}
+
+		switch to {
+		case StatusConfirmed:
+			if err := s.stock.Commit(tx, order.ID); err != nil {
+				return err
+			}
+		case StatusCancelled:
+			if err := s.stock.Release(tx, order.ID); err != nil {
+				return err
+			}
+		}
+		return recordTransition(tx, order.ID, order.Status, to, actor, note)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, order.ID)
+}
+
+func recordTransition(tx *gorm.DB, orderID uint, from, to OrderStatus, actor, note string) error {
+	entry := OrderStatusHistory{
+		OrderID:    orderID,
+		FromStatus: from,
+		ToStatus:   to,
+		Actor:      actor,
+		Note:       note,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("failed to record order status: %w", err)
+	}
+	return nil
+}
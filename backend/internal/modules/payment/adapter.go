@@ -0,0 +1,50 @@
+package payment
+
+import (
+	"context"
+
+	"go-ecommerce/internal/modules/order"
+
+	"github.com/google/uuid"
+)
+
+// OrderServiceAdapter adapts order.Service to OrderSource
+type OrderServiceAdapter struct {
+	service order.Service
+}
+
+func NewOrderServiceAdapter(service order.Service) *OrderServiceAdapter {
+	return &OrderServiceAdapter{service: service}
+}
+
+func (a *OrderServiceAdapter) GetOrder(ctx context.Context, id uint) (*OrderInfo, error) {
+	o, err := a.service.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return toOrderInfo(o), nil
+}
+
+func (a *OrderServiceAdapter) GetUserOrder(ctx context.Context, userID uuid.UUID, id uint) (*OrderInfo, error) {
+	o, err := a.service.GetMineByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return toOrderInfo(o), nil
+}
+
+func (a *OrderServiceAdapter) Confirm(ctx context.Context, id uint, note string) error {
+	_, err := a.service.UpdateStatus(ctx, id, order.UpdateStatusRequest{Status: order.StatusConfirmed, Note: note}, order.ActorSystem)
+	return err
+}
+
+func toOrderInfo(o *order.OrderResponse) *OrderInfo {
+	return &OrderInfo{
+		ID:            o.ID,
+		Number:        o.Number,
+		Pending:       o.Status == order.StatusPending,
+		Cancelled:     o.Status == order.StatusCancelled || o.Status == order.StatusReturned,
+		Total:         o.Total,
+		ReservedUntil: o.PayBefore,
+	}
+}
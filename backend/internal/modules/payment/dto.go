@@ -0,0 +1,28 @@
+package payment
+
+import (
+	"time"
+
+	"go-ecommerce/pkg/money"
+)
+
+// CreatePaymentRequest - Request body for paying an order online
+type CreatePaymentRequest struct {
+	Gateway string `json:"gateway" binding:"required"` // vnpay or momo
+}
+
+// CreatePaymentResponse - the payment and where to send the customer
+type CreatePaymentResponse struct {
+	Payment *Payment `json:"payment"`
+	PayURL  string   `json:"pay_url"`
+}
+
+// OrderInfo - the order being paid, as seen by payments
+type OrderInfo struct {
+	ID            uint
+	Number        string
+	Pending       bool // Waiting for payment
+	Cancelled     bool // Cancelled or returned
+	Total         money.Money
+	ReservedUntil *time.Time
+}
@@ -0,0 +1,39 @@
+package payment
+
+import (
+	"time"
+
+	"go-ecommerce/pkg/money"
+)
+
+// PaymentStatus enum
+type PaymentStatus string
+
+const (
+	StatusPending PaymentStatus = "pending" // Customer sent to the gateway, no result yet
+	StatusPaid    PaymentStatus = "paid"
+	StatusFailed  PaymentStatus = "failed" // Declined, cancelled by the customer or never started
+)
+
+// Payment entity - one attempt to pay an order through a gateway
+// An order may have several attempts; a settled attempt never changes again,
+// so repeated callbacks for it are harmless.
+type Payment struct {
+	ID           uint          `gorm:"primaryKey" json:"id"`
+	OrderID      uint          `gorm:"not null;index" json:"order_id"`
+	Gateway      string        `gorm:"type:varchar(20);not null" json:"gateway"`
+	TxnRef       string        `gorm:"type:varchar(20);index" json:"txn_ref"` // TT00000042, derived from the ID and sent to the gateway
+	Amount       money.Money   `gorm:"not null" json:"amount"`
+	Status       PaymentStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	GatewayTxnID string        `gorm:"type:varchar(50)" json:"gateway_txn_id"` // Transaction number at the gateway
+	ResponseCode string        `gorm:"type:varchar(10)" json:"response_code"`
+	Message      string        `gorm:"type:varchar(255)" json:"message"`
+	Note         string        `gorm:"type:varchar(255)" json:"note"` // e.g. paid after the order was cancelled, to refund
+	PaidAt       *time.Time    `json:"paid_at"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+func (Payment) TableName() string {
+	return "payments"
+}
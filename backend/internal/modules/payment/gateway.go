@@ -0,0 +1,79 @@
+package payment
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go-ecommerce/internal/config"
+)
+
+// Gateway is an online payment provider
+// The customer is sent to the gateway's payment page, comes back to the return URL
+// and the gateway also notifies us server-to-server (IPN). Both callbacks are
+// signed; a result is only trusted once its signature has been verified.
+type Gateway interface {
+	Name() string
+
+	// CreatePaymentURL returns the page the customer pays on
+	CreatePaymentURL(ctx context.Context, req PaymentRequest) (string, error)
+
+	// VerifyReturn checks the result the customer is redirected back with
+	VerifyReturn(r *http.Request) (*Result, error)
+
+	// VerifyIPN checks a server-to-server notification
+	VerifyIPN(r *http.Request) (*Result, error)
+
+	// AckIPN writes the reply the gateway expects for a notification handled with err
+	AckIPN(w http.ResponseWriter, err error)
+}
+
+// PaymentRequest - what a gateway needs to start a payment
+type PaymentRequest struct {
+	TxnRef    string // Our reference, echoed back in callbacks
+	Amount    int64  // In đồng
+	OrderInfo string
+	ReturnURL string
+	IPNURL    string // Only sent by gateways that take it per payment (MoMo)
+	ClientIP  string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Result - a verified payment outcome reported by a gateway
+type Result struct {
+	TxnRef       string
+	Amount       int64 // In đồng
+	Success      bool
+	GatewayTxnID string // The gateway's transaction number
+	ResponseCode string
+	Message      string
+}
+
+// Gateway names
+const (
+	GatewayVNPay = "vnpay"
+	GatewayMoMo  = "momo"
+)
+
+// NewGateways returns the gateways configured with credentials, by name
+func NewGateways(cfg *config.PaymentConfig) map[string]Gateway {
+	gateways := make(map[string]Gateway)
+	if cfg.VNPay.TmnCode != "" && cfg.VNPay.HashSecret != "" {
+		gateways[GatewayVNPay] = NewVNPay(&cfg.VNPay)
+	}
+	if cfg.MoMo.PartnerCode != "" && cfg.MoMo.SecretKey != "" {
+		gateways[GatewayMoMo] = NewMoMo(&cfg.MoMo)
+	}
+	return gateways
+}
+
+// vietnamTime is the time zone gateways expect timestamps in
+var vietnamTime = time.FixedZone("ICT", 7*60*60)
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
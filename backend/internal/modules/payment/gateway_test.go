@@ -0,0 +1,199 @@
+package payment_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"go-ecommerce/internal/config"
+	"go-ecommerce/internal/modules/payment"
+	"go-ecommerce/internal/modules/payment/sim"
+	"go-ecommerce/internal/shared/errors"
+)
+
+const (
+	testTxnRef = "TT00000001"
+	testAmount = int64(150000)
+)
+
+func newVNPay(tmnCode, secret string) *payment.VNPay {
+	return payment.NewVNPay(&config.VNPayConfig{TmnCode: tmnCode, HashSecret: secret, URL: "https://sandbox.vnpayment.vn/paymentv2/vpcpay.html"})
+}
+
+func newMoMo(partnerCode, secret string) *payment.MoMo {
+	return payment.NewMoMo(&config.MoMoConfig{PartnerCode: partnerCode, AccessKey: "F8BBA842ECF85", SecretKey: secret, Endpoint: "https://test-payment.momo.vn"})
+}
+
+// vnpayResult starts a payment with g and returns what VNPay sends back for it
+func vnpayResult(t *testing.T, g *payment.VNPay, txnRef string, amount int64, outcome string) url.Values {
+	t.Helper()
+	now := time.Now()
+	payURL, err := g.CreatePaymentURL(context.Background(), payment.PaymentRequest{
+		TxnRef:    txnRef,
+		Amount:    amount,
+		OrderInfo: "Thanh toan don hang DH00000042",
+		ReturnURL: "http://localhost/api/v1/payments/vnpay/return",
+		ClientIP:  "127.0.0.1",
+		CreatedAt: now,
+	})
+	if err != nil {
+		t.Fatalf("create pay url: %v", err)
+	}
+	u, err := url.Parse(payURL)
+	if err != nil {
+		t.Fatalf("parse pay url: %v", err)
+	}
+	return sim.VNPayResult(g, u.Query(), outcome, 14000001, now)
+}
+
+// momoResult returns what MoMo sends back for a payment created by g
+func momoResult(g *payment.MoMo, partnerCode, txnRef string, amount int64, outcome string) *payment.MoMoResult {
+	req := &payment.MoMoCreateRequest{
+		PartnerCode: partnerCode,
+		RequestID:   txnRef,
+		Amount:      amount,
+		OrderID:     txnRef,
+		OrderInfo:   "Thanh toan don hang DH00000042",
+		RequestType: "captureWallet",
+	}
+	return sim.MoMoResult(g, req, outcome, 3100001, time.Now())
+}
+
+func vnpayRequest(q url.Values, ipn bool) *http.Request {
+	path := "/api/v1/payments/vnpay/return?"
+	if ipn {
+		path = "/api/v1/payments/vnpay/ipn?"
+	}
+	return httptest.NewRequest(http.MethodGet, path+q.Encode(), nil)
+}
+
+func momoRequest(t *testing.T, res *payment.MoMoResult, ipn bool) *http.Request {
+	t.Helper()
+	if !ipn {
+		return httptest.NewRequest(http.MethodGet, "/api/v1/payments/momo/return?"+sim.MoMoReturnQuery(res).Encode(), nil)
+	}
+	body, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("marshal result: %v", err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/api/v1/payments/momo/ipn", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	return r
+}
+
+func TestVNPayVerify(t *testing.T) {
+	g := newVNPay("TESTTMN1", "vnpay-secret")
+	tests := []struct {
+		name        string
+		gateway     *payment.VNPay // Gateway that signed the result, g when nil
+		outcome     string
+		tamper      func(q url.Values)
+		wantErr     error
+		wantSuccess bool
+	}{
+		{name: "valid success", outcome: sim.Success, wantSuccess: true},
+		{name: "valid decline", outcome: sim.Fail},
+		{name: "valid cancel", outcome: sim.Cancel},
+		{name: "tampered amount", outcome: sim.Success, tamper: func(q url.Values) { q.Set("vnp_Amount", "100") }, wantErr: errors.ErrInvalidSignature},
+		{name: "tampered txn ref", outcome: sim.Success, tamper: func(q url.Values) { q.Set("vnp_TxnRef", "TT00000002") }, wantErr: errors.ErrInvalidSignature},
+		{name: "declined turned into success", outcome: sim.Fail, tamper: func(q url.Values) {
+			q.Set("vnp_ResponseCode", "00")
+			q.Set("vnp_TransactionStatus", "00")
+		}, wantErr: errors.ErrInvalidSignature},
+		{name: "missing hash", outcome: sim.Success, tamper: func(q url.Values) { q.Del("vnp_SecureHash") }, wantErr: errors.ErrInvalidSignature},
+		{name: "signed with another secret", gateway: newVNPay("TESTTMN1", "other-secret"), outcome: sim.Success, wantErr: errors.ErrInvalidSignature},
+		{name: "another merchant", gateway: newVNPay("TESTTMN2", "vnpay-secret"), outcome: sim.Success, wantErr: errors.ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer := g
+			if tt.gateway != nil {
+				signer = tt.gateway
+			}
+			q := vnpayResult(t, signer, testTxnRef, testAmount, tt.outcome)
+			if tt.tamper != nil {
+				tt.tamper(q)
+			}
+
+			for _, ipn := range []bool{false, true} {
+				verify := g.VerifyReturn
+				if ipn {
+					verify = g.VerifyIPN
+				}
+				res, err := verify(vnpayRequest(q, ipn))
+				if err != tt.wantErr {
+					t.Fatalf("ipn=%v: err = %v, want %v", ipn, err, tt.wantErr)
+				}
+				if err != nil {
+					continue
+				}
+				if res.TxnRef != testTxnRef || res.Amount != testAmount || res.Success != tt.wantSuccess {
+					t.Errorf("ipn=%v: result = %+v, want %s, %d đ, success %v", ipn, res, testTxnRef, testAmount, tt.wantSuccess)
+				}
+			}
+		})
+	}
+}
+
+func TestMoMoVerify(t *testing.T) {
+	const partnerCode = "MOMOTEST1"
+	g := newMoMo(partnerCode, "momo-secret")
+	tests := []struct {
+		name        string
+		gateway     *payment.MoMo // Gateway that signed the result, g when nil
+		partnerCode string        // Partner the result is for, partnerCode when empty
+		outcome     string
+		tamper      func(res *payment.MoMoResult)
+		wantErr     error
+		wantSuccess bool
+	}{
+		{name: "valid success", outcome: sim.Success, wantSuccess: true},
+		{name: "valid decline", outcome: sim.Fail},
+		{name: "valid cancel", outcome: sim.Cancel},
+		{name: "tampered amount", outcome: sim.Success, tamper: func(res *payment.MoMoResult) { res.Amount = 100 }, wantErr: errors.ErrInvalidSignature},
+		{name: "tampered order id", outcome: sim.Success, tamper: func(res *payment.MoMoResult) { res.OrderID = "TT00000002" }, wantErr: errors.ErrInvalidSignature},
+		{name: "declined turned into success", outcome: sim.Fail, tamper: func(res *payment.MoMoResult) { res.ResultCode = 0 }, wantErr: errors.ErrInvalidSignature},
+		{name: "missing signature", outcome: sim.Success, tamper: func(res *payment.MoMoResult) { res.Signature = "" }, wantErr: errors.ErrInvalidSignature},
+		{name: "signed with another secret", gateway: newMoMo(partnerCode, "other-secret"), outcome: sim.Success, wantErr: errors.ErrInvalidSignature},
+		{name: "another partner", gateway: newMoMo("MOMOTEST2", "momo-secret"), partnerCode: "MOMOTEST2", outcome: sim.Success, wantErr: errors.ErrInvalidSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, partner := g, partnerCode
+			if tt.gateway != nil {
+				signer = tt.gateway
+			}
+			if tt.partnerCode != "" {
+				partner = tt.partnerCode
+			}
+			res := momoResult(signer, partner, testTxnRef, testAmount, tt.outcome)
+			if tt.tamper != nil {
+				tt.tamper(res)
+			}
+
+			for _, ipn := range []bool{false, true} {
+				verify := g.VerifyReturn
+				if ipn {
+					verify = g.VerifyIPN
+				}
+				got, err := verify(momoRequest(t, res, ipn))
+				if err != tt.wantErr {
+					t.Fatalf("ipn=%v: err = %v, want %v", ipn, err, tt.wantErr)
+				}
+				if err != nil {
+					continue
+				}
+				if got.TxnRef != testTxnRef || got.Amount != testAmount || got.Success != tt.wantSuccess {
+					t.Errorf("ipn=%v: result = %+v, want %s, %d đ, success %v", ipn, got, testTxnRef, testAmount, tt.wantSuccess)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package payment
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler handles payment HTTP requests, including gateway callbacks
+type Handler struct {
+	service   Service
+	resultURL string
+}
+
+// NewHandler creates a new payment handler
+// Customers returning from a gateway are redirected to resultURL when set.
+func NewHandler(service Service, resultURL string) *Handler {
+	return &Handler{service: service, resultURL: resultURL}
+}
+
+// Create handles POST /orders/:id/payments
+// @Summary Thanh toán trực tuyến
+// @Description Tạo giao dịch qua cổng thanh toán (vnpay, momo) và trả về link thanh toán
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Order ID"
+// @Param request body CreatePaymentRequest true "Cổng thanh toán"
+// @Success 201 {object} CreatePaymentResponse
+// @Failure 409 {object} map[string]string
+// @Router /orders/{id}/payments [post]
+func (h *Handler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req CreatePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Create(c.Request.Context(), userID, id, req, c.ClientIP())
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy đơn hàng"})
+		case errors.ErrUnknownGateway:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cổng thanh toán không được hỗ trợ"})
+		case errors.ErrOrderNotPayable:
+			c.JSON(http.StatusConflict, gin.H{"error": "Đơn hàng không ở trạng thái chờ thanh toán hoặc đã hết thời gian giữ hàng"})
+		case errors.ErrGatewayUnavailable:
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Không kết nối được cổng thanh toán, vui lòng thử lại"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi tạo giao dịch thanh toán"})
+		}
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Tạo giao dịch thanh toán thành công",
+		"data":    res,
+	})
+}
+
+// GetMine handles GET /orders/:id/payments
+func (h *Handler) GetMine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GetMine(c.Request.Context(), userID, id)
+	if err == errors.ErrRecordNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy đơn hàng"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách giao dịch"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// GetByOrder handles GET /admin/orders/:id/payments
+func (h *Handler) GetByOrder(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GetByOrder(c.Request.Context(), id)
+	if err == errors.ErrRecordNotFound {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy đơn hàng"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách giao dịch"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// Return handles GET /payments/:gateway/return, where the gateway sends the customer back
+// The result is applied too, so payments work even when the IPN cannot reach us.
+func (h *Handler) Return(c *gin.Context) {
+	gateway, err := h.service.Gateway(c.Param("gateway"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cổng thanh toán không được hỗ trợ"})
+		return
+	}
+
+	res, err := gateway.VerifyReturn(c.Request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Chữ ký không hợp lệ"})
+		return
+	}
+	payment, err := h.service.Apply(c.Request.Context(), gateway.Name(), res)
+	if err != nil && err != errors.ErrPaymentSettled {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy giao dịch"})
+		case errors.ErrPaymentAmount:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Số tiền thanh toán không khớp"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi xử lý kết quả thanh toán"})
+		}
+		return
+	}
+
+	if h.resultURL != "" {
+		query := url.Values{}
+		query.Set("order_id", strconv.FormatUint(uint64(payment.OrderID), 10))
+		query.Set("txn_ref", payment.TxnRef)
+		query.Set("status", string(payment.Status))
+		c.Redirect(http.StatusFound, h.resultURL+"?"+query.Encode())
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": payment})
+}
+
+// IPN handles GET/POST /payments/:gateway/ipn, the gateway's server-to-server notification
+// The reply format is the gateway's own.
+func (h *Handler) IPN(c *gin.Context) {
+	gateway, err := h.service.Gateway(c.Param("gateway"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cổng thanh toán không được hỗ trợ"})
+		return
+	}
+
+	res, err := gateway.VerifyIPN(c.Request)
+	if err == nil {
+		_, err = h.service.Apply(c.Request.Context(), gateway.Name(), res)
+	}
+	gateway.AckIPN(c.Writer, err)
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
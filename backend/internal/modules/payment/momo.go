@@ -0,0 +1,216 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-ecommerce/internal/config"
+	"go-ecommerce/internal/shared/errors"
+)
+
+// MoMo implements the MoMo v2 "captureWallet" flow
+// Creating a payment is an API call that returns the payment page; requests and
+// callbacks are signed with HMAC-SHA256 over "key=value&..." in a fixed key order.
+// The customer comes back with the result in the query, the IPN posts it as JSON.
+type MoMo struct {
+	partnerCode string
+	accessKey   string
+	secretKey   string
+	endpoint    string
+	client      *http.Client
+}
+
+func NewMoMo(cfg *config.MoMoConfig) *MoMo {
+	return &MoMo{
+		partnerCode: cfg.PartnerCode,
+		accessKey:   cfg.AccessKey,
+		secretKey:   cfg.SecretKey,
+		endpoint:    strings.TrimSuffix(cfg.Endpoint, "/"),
+		client:      &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+// MoMoCreateRequest - body of POST /v2/gateway/api/create
+type MoMoCreateRequest struct {
+	PartnerCode string `json:"partnerCode"`
+	AccessKey   string `json:"accessKey"`
+	RequestID   string `json:"requestId"`
+	Amount      int64  `json:"amount"`
+	OrderID     string `json:"orderId"`
+	OrderInfo   string `json:"orderInfo"`
+	RedirectURL string `json:"redirectUrl"`
+	IPNURL      string `json:"ipnUrl"`
+	RequestType string `json:"requestType"`
+	ExtraData   string `json:"extraData"`
+	Lang        string `json:"lang"`
+	Signature   string `json:"signature"`
+}
+
+// MoMoCreateResponse - reply to a create request
+type MoMoCreateResponse struct {
+	PartnerCode  string `json:"partnerCode"`
+	RequestID    string `json:"requestId"`
+	OrderID      string `json:"orderId"`
+	Amount       int64  `json:"amount"`
+	ResponseTime int64  `json:"responseTime"`
+	Message      string `json:"message"`
+	ResultCode   int    `json:"resultCode"`
+	PayURL       string `json:"payUrl"`
+}
+
+// MoMoResult - payment outcome sent to the redirect URL and the IPN
+type MoMoResult struct {
+	PartnerCode  string `json:"partnerCode"`
+	OrderID      string `json:"orderId"`
+	RequestID    string `json:"requestId"`
+	Amount       int64  `json:"amount"`
+	OrderInfo    string `json:"orderInfo"`
+	OrderType    string `json:"orderType"`
+	TransID      int64  `json:"transId"`
+	ResultCode   int    `json:"resultCode"`
+	Message      string `json:"message"`
+	PayType      string `json:"payType"`
+	ResponseTime int64  `json:"responseTime"`
+	ExtraData    string `json:"extraData"`
+	Signature    string `json:"signature"`
+}
+
+func (g *MoMo) Name() string {
+	return GatewayMoMo
+}
+
+func (g *MoMo) CreatePaymentURL(ctx context.Context, req PaymentRequest) (string, error) {
+	body := MoMoCreateRequest{
+		PartnerCode: g.partnerCode,
+		AccessKey:   g.accessKey,
+		RequestID:   req.TxnRef,
+		Amount:      req.Amount,
+		OrderID:     req.TxnRef,
+		OrderInfo:   req.OrderInfo,
+		RedirectURL: req.ReturnURL,
+		IPNURL:      req.IPNURL,
+		RequestType: "captureWallet",
+		Lang:        "vi",
+	}
+	body.Signature = g.SignCreate(&body)
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return "", err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, g.endpoint+"/v2/gateway/api/create", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return "", fmt.Errorf("momo create payment: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var res MoMoCreateResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return "", fmt.Errorf("momo create payment: %s: %w", resp.Status, err)
+	}
+	if res.ResultCode != 0 || res.PayURL == "" {
+		return "", fmt.Errorf("momo create payment: %d %s", res.ResultCode, res.Message)
+	}
+	return res.PayURL, nil
+}
+
+func (g *MoMo) VerifyReturn(r *http.Request) (*Result, error) {
+	q := r.URL.Query()
+	res := MoMoResult{
+		PartnerCode: q.Get("partnerCode"),
+		OrderID:     q.Get("orderId"),
+		RequestID:   q.Get("requestId"),
+		OrderInfo:   q.Get("orderInfo"),
+		OrderType:   q.Get("orderType"),
+		Message:     q.Get("message"),
+		PayType:     q.Get("payType"),
+		ExtraData:   q.Get("extraData"),
+		Signature:   q.Get("signature"),
+	}
+	var err error
+	if res.Amount, err = strconv.ParseInt(q.Get("amount"), 10, 64); err != nil {
+		return nil, errors.ErrInvalidSignature
+	}
+	if res.TransID, err = strconv.ParseInt(q.Get("transId"), 10, 64); err != nil {
+		return nil, errors.ErrInvalidSignature
+	}
+	if res.ResultCode, err = strconv.Atoi(q.Get("resultCode")); err != nil {
+		return nil, errors.ErrInvalidSignature
+	}
+	if res.ResponseTime, err = strconv.ParseInt(q.Get("responseTime"), 10, 64); err != nil {
+		return nil, errors.ErrInvalidSignature
+	}
+	return g.verify(&res)
+}
+
+func (g *MoMo) VerifyIPN(r *http.Request) (*Result, error) {
+	var res MoMoResult
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		return nil, errors.ErrInvalidSignature
+	}
+	return g.verify(&res)
+}
+
+// AckIPN answers 204 once the notification is handled; MoMo retries on errors
+func (g *MoMo) AckIPN(w http.ResponseWriter, err error) {
+	switch err {
+	case nil, errors.ErrPaymentSettled:
+		w.WriteHeader(http.StatusNoContent)
+	case errors.ErrInvalidSignature, errors.ErrPaymentAmount, errors.ErrRecordNotFound:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+	default:
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"message": "internal error"})
+	}
+}
+
+// SignCreate returns the signature of a create request
+func (g *MoMo) SignCreate(req *MoMoCreateRequest) string {
+	return g.sign(fmt.Sprintf("accessKey=%s&amount=%d&extraData=%s&ipnUrl=%s&orderId=%s&orderInfo=%s&partnerCode=%s&redirectUrl=%s&requestId=%s&requestType=%s",
+		g.accessKey, req.Amount, req.ExtraData, req.IPNURL, req.OrderID, req.OrderInfo, req.PartnerCode, req.RedirectURL, req.RequestID, req.RequestType))
+}
+
+// SignResult returns the signature of a payment result
+func (g *MoMo) SignResult(res *MoMoResult) string {
+	return g.sign(fmt.Sprintf("accessKey=%s&amount=%d&extraData=%s&message=%s&orderId=%s&orderInfo=%s&orderType=%s&partnerCode=%s&payType=%s&requestId=%s&responseTime=%d&resultCode=%d&transId=%d",
+		g.accessKey, res.Amount, res.ExtraData, res.Message, res.OrderID, res.OrderInfo, res.OrderType, res.PartnerCode, res.PayType, res.RequestID, res.ResponseTime, res.ResultCode, res.TransID))
+}
+
+func (g *MoMo) sign(raw string) string {
+	mac := hmac.New(sha256.New, []byte(g.secretKey))
+	mac.Write([]byte(raw))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (g *MoMo) verify(res *MoMoResult) (*Result, error) {
+	signature, err := hex.DecodeString(res.Signature)
+	if err != nil || res.PartnerCode != g.partnerCode {
+		return nil, errors.ErrInvalidSignature
+	}
+	expected, _ := hex.DecodeString(g.SignResult(res))
+	if !hmac.Equal(signature, expected) {
+		return nil, errors.ErrInvalidSignature
+	}
+
+	return &Result{
+		TxnRef:       res.OrderID,
+		Amount:       res.Amount,
+		Success:      res.ResultCode == 0,
+		GatewayTxnID: strconv.FormatInt(res.TransID, 10),
+		ResponseCode: strconv.Itoa(res.ResultCode),
+		Message:      res.Message,
+	}, nil
+}
@@ -0,0 +1,36 @@
+package payment
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repository interface
+type Repository interface {
+	GetByOrder(ctx context.Context, orderID uint) ([]Payment, error)
+
+	// Transaction support
+	WithTransaction(fn func(*gorm.DB) error) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new payment repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// WithTransaction executes a function within a transaction
+func (r *repository) WithTransaction(fn func(*gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
+// GetByOrder returns the payment attempts of an order, newest first
+func (r *repository) GetByOrder(ctx context.Context, orderID uint) ([]Payment, error) {
+	var payments []Payment
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).Order("id DESC").Find(&payments).Error
+	return payments, err
+}
@@ -0,0 +1,239 @@
+package payment
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrderSource interface for the orders being paid
+type OrderSource interface {
+	GetOrder(ctx context.Context, id uint) (*OrderInfo, error)
+	// GetUserOrder returns an order of the user; other users' orders are not found
+	GetUserOrder(ctx context.Context, userID uuid.UUID, id uint) (*OrderInfo, error)
+	// Confirm moves a pending order to confirmed; ErrInvalidTransition if it is not pending
+	Confirm(ctx context.Context, id uint, note string) error
+}
+
+// PaidMarker interface for recording the order as paid with the payment
+// ErrOrderClosed when the order was cancelled or returned; it is then not marked.
+type PaidMarker interface {
+	MarkPaid(tx *gorm.DB, orderID uint, paidAt time.Time) (bool, error)
+}
+
+// Service interface
+type Service interface {
+	Gateway(name string) (Gateway, error)
+	Create(ctx context.Context, userID uuid.UUID, orderID uint, req CreatePaymentRequest, clientIP string) (*CreatePaymentResponse, error)
+	// Apply records a verified gateway result and confirms the order once paid
+	// Returns ErrPaymentSettled (with the payment) when the result was already applied.
+	Apply(ctx context.Context, gateway string, res *Result) (*Payment, error)
+
+	GetMine(ctx context.Context, userID uuid.UUID, orderID uint) ([]Payment, error)
+	GetByOrder(ctx context.Context, orderID uint) ([]Payment, error)
+}
+
+type service struct {
+	repo      Repository
+	orders    OrderSource
+	paid      PaidMarker
+	gateways  map[string]Gateway
+	publicURL string
+}
+
+// NewService creates a new payment service
+// publicURL is where gateways reach this API, for return and IPN callbacks.
+func NewService(repo Repository, orders OrderSource, paid PaidMarker, gateways map[string]Gateway, publicURL string) Service {
+	return &service{repo: repo, orders: orders, paid: paid, gateways: gateways, publicURL: strings.TrimSuffix(publicURL, "/")}
+}
+
+func (s *service) Gateway(name string) (Gateway, error) {
+	gateway, ok := s.gateways[name]
+	if !ok {
+		return nil, errors.ErrUnknownGateway
+	}
+	return gateway, nil
+}
+
+// Create starts a payment of the whole order total
+// The gateway page expires with the order's stock reservation.
+func (s *service) Create(ctx context.Context, userID uuid.UUID, orderID uint, req CreatePaymentRequest, clientIP string) (*CreatePaymentResponse, error) {
+	gateway, err := s.Gateway(req.Gateway)
+	if err != nil {
+		return nil, err
+	}
+	order, err := s.orders.GetUserOrder(ctx, userID, orderID)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	now := time.Now()
+	if !order.Pending || order.ReservedUntil == nil || !order.ReservedUntil.After(now) || order.Total.Amount <= 0 {
+		return nil, errors.ErrOrderNotPayable
+	}
+
+	payment := &Payment{
+		OrderID: order.ID,
+		Gateway: gateway.Name(),
+		Amount:  order.Total,
+		Status:  StatusPending,
+	}
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		if err := tx.Create(payment).Error; err != nil {
+			return fmt.Errorf("failed to create payment: %w", err)
+		}
+		payment.TxnRef = fmt.Sprintf("TT%08d", payment.ID)
+		return tx.Model(payment).Update("txn_ref", payment.TxnRef).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	callbackURL := s.publicURL + "/api/v1/payments/" + gateway.Name()
+	payURL, err := gateway.CreatePaymentURL(ctx, PaymentRequest{
+		TxnRef:    payment.TxnRef,
+		Amount:    payment.Amount.Amount,
+		OrderInfo: "Thanh toan don hang " + order.Number, // Gateways reject diacritics
+		ReturnURL: callbackURL + "/return",
+		IPNURL:    callbackURL + "/ipn",
+		ClientIP:  clientIP,
+		CreatedAt: now,
+		ExpiresAt: *order.ReservedUntil,
+	})
+	if err != nil {
+		s.repo.WithTransaction(func(tx *gorm.DB) error {
+			return tx.WithContext(ctx).Model(payment).Updates(map[string]interface{}{"status": StatusFailed, "message": truncate(err.Error(), 255)}).Error
+		})
+		return nil, errors.ErrGatewayUnavailable
+	}
+	return &CreatePaymentResponse{Payment: payment, PayURL: payURL}, nil
+}
+
+// Apply settles a pending payment with a verified result
+// The payment row is locked so a return and an IPN arriving together apply once.
+// Money for an order that was cancelled meanwhile, or already paid by another
+// attempt, is recorded with a refund note and the order is left as it is.
+// Confirming the order happens after the payment is saved; a later callback for the
+// settled payment retries it while the order is still pending, so an order is
+// never left pending once paid and never confirmed twice.
+func (s *service) Apply(ctx context.Context, gateway string, res *Result) (*Payment, error) {
+	var payment Payment
+	settled := false
+	err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("txn_ref = ? AND gateway = ?", res.TxnRef, gateway).
+			First(&payment).Error
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrRecordNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if payment.Status != StatusPending {
+			settled = true
+			return nil
+		}
+		if res.Amount != payment.Amount.Amount {
+			return errors.ErrPaymentAmount
+		}
+
+		now := time.Now()
+		payment.Status = StatusFailed
+		if res.Success {
+			payment.Status = StatusPaid
+			payment.PaidAt = &now
+			marked, err := s.paid.MarkPaid(tx, payment.OrderID, now)
+			switch {
+			case err == errors.ErrOrderClosed:
+				// Cancelled (e.g. reservation expired) or returned meanwhile
+				payment.Note = "Đơn hàng đã huỷ, cần hoàn tiền"
+			case err != nil:
+				return err
+			case !marked:
+				payment.Note = "Đơn hàng đã được thanh toán bởi giao dịch khác, cần hoàn tiền"
+			}
+		}
+		payment.GatewayTxnID = res.GatewayTxnID
+		payment.ResponseCode = res.ResponseCode
+		payment.Message = truncate(res.Message, 255)
+		return tx.Model(&payment).Updates(map[string]interface{}{
+			"status":         payment.Status,
+			"gateway_txn_id": payment.GatewayTxnID,
+			"response_code":  payment.ResponseCode,
+			"message":        payment.Message,
+			"paid_at":        payment.PaidAt,
+			"note":           payment.Note,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Payments to refund did not pay for the order, so it is not confirmed
+	if payment.Status == StatusPaid && payment.Note == "" {
+		if settled {
+			order, err := s.orders.GetOrder(ctx, payment.OrderID)
+			if err != nil {
+				return nil, err
+			}
+			if !order.Pending {
+				return &payment, errors.ErrPaymentSettled
+			}
+		}
+		err := s.orders.Confirm(ctx, payment.OrderID, fmt.Sprintf("Đã thanh toán qua %s (%s)", payment.Gateway, payment.TxnRef))
+		switch {
+		case err == errors.ErrInvalidTransition && !settled:
+			// Either a duplicate callback confirmed it first, or it was
+			// cancelled between marking it paid and confirming it
+			order, err := s.orders.GetOrder(ctx, payment.OrderID)
+			if err != nil {
+				return nil, err
+			}
+			if !order.Cancelled {
+				break
+			}
+			payment.Note = "Đơn hàng không còn chờ thanh toán, cần hoàn tiền"
+			err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+				return tx.WithContext(ctx).Model(&payment).Update("note", payment.Note).Error
+			})
+			if err != nil {
+				return nil, err
+			}
+		case err != nil && err != errors.ErrInvalidTransition:
+			return nil, err
+		}
+	}
+	if settled {
+		return &payment, errors.ErrPaymentSettled
+	}
+	return &payment, nil
+}
+
+func (s *service) GetMine(ctx context.Context, userID uuid.UUID, orderID uint) ([]Payment, error) {
+	if _, err := s.orders.GetUserOrder(ctx, userID, orderID); err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	return s.repo.GetByOrder(ctx, orderID)
+}
+
+func (s *service) GetByOrder(ctx context.Context, orderID uint) ([]Payment, error) {
+	if _, err := s.orders.GetOrder(ctx, orderID); err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	return s.repo.GetByOrder(ctx, orderID)
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
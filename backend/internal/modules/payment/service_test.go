@@ -0,0 +1,234 @@
+package payment_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go-ecommerce/internal/database/dbtest"
+	"go-ecommerce/internal/modules/payment"
+	"go-ecommerce/internal/modules/payment/sim"
+	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const testOrderID = uint(42)
+
+// fakeOrders is an order that moves from pending to confirmed at most once
+type fakeOrders struct {
+	mu        sync.Mutex
+	pending   bool
+	cancelled bool
+	confirms  int // Confirm calls
+	confirmed int // Confirm calls that moved the order
+}
+
+func (o *fakeOrders) GetOrder(ctx context.Context, id uint) (*payment.OrderInfo, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return &payment.OrderInfo{ID: id, Pending: o.pending, Cancelled: o.cancelled, Total: money.VNDOf(testAmount)}, nil
+}
+
+func (o *fakeOrders) GetUserOrder(ctx context.Context, userID uuid.UUID, id uint) (*payment.OrderInfo, error) {
+	return o.GetOrder(ctx, id)
+}
+
+func (o *fakeOrders) Confirm(ctx context.Context, id uint, note string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.confirms++
+	if !o.pending {
+		return errors.ErrInvalidTransition
+	}
+	o.pending = false
+	o.confirmed++
+	return nil
+}
+
+// fakePaid marks the order paid once, like orders.paid_at
+type fakePaid struct {
+	mu     sync.Mutex
+	closed bool
+	marks  int
+}
+
+func (p *fakePaid) MarkPaid(tx *gorm.DB, orderID uint, paidAt time.Time) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return false, errors.ErrOrderClosed
+	}
+	if p.marks > 0 {
+		return false, nil
+	}
+	p.marks++
+	return true, nil
+}
+
+type callback struct {
+	outcome string
+	amount  int64 // Amount the gateway reports
+	ipn     bool  // Server-to-server notification, otherwise the customer's return
+}
+
+func TestApply(t *testing.T) {
+	paid := callback{outcome: sim.Success, amount: testAmount, ipn: true}
+	paidReturn := callback{outcome: sim.Success, amount: testAmount}
+
+	tests := []struct {
+		name       string
+		cancelled  bool // Order cancelled before the payment arrives
+		callbacks  []callback
+		concurrent bool
+		wantErrs   []error // Per callback, when not concurrent
+		wantStatus payment.PaymentStatus
+		wantNote   bool
+		wantMarks  int
+		wantConfs  int // Confirm calls, when not concurrent
+		wantConfed int
+	}{
+		{
+			name:       "valid payment",
+			callbacks:  []callback{paid},
+			wantErrs:   []error{nil},
+			wantStatus: payment.StatusPaid, wantMarks: 1, wantConfs: 1, wantConfed: 1,
+		},
+		{
+			name:       "declined",
+			callbacks:  []callback{{outcome: sim.Fail, amount: testAmount, ipn: true}},
+			wantErrs:   []error{nil},
+			wantStatus: payment.StatusFailed,
+		},
+		{
+			name:       "wrong amount",
+			callbacks:  []callback{{outcome: sim.Success, amount: testAmount - 1000, ipn: true}},
+			wantErrs:   []error{errors.ErrPaymentAmount},
+			wantStatus: payment.StatusPending,
+		},
+		{
+			name:       "duplicate callback",
+			callbacks:  []callback{paid, paidReturn, paid},
+			wantErrs:   []error{nil, errors.ErrPaymentSettled, errors.ErrPaymentSettled},
+			wantStatus: payment.StatusPaid, wantMarks: 1, wantConfs: 1, wantConfed: 1,
+		},
+		{
+			name:       "duplicate callbacks at the same time",
+			callbacks:  []callback{paid, paidReturn, paid, paidReturn},
+			concurrent: true,
+			wantStatus: payment.StatusPaid, wantMarks: 1, wantConfed: 1,
+		},
+		{
+			name:       "order cancelled before payment",
+			cancelled:  true,
+			callbacks:  []callback{paid, paidReturn},
+			wantErrs:   []error{nil, errors.ErrPaymentSettled},
+			wantStatus: payment.StatusPaid, wantNote: true,
+		},
+	}
+
+	gateways := []struct {
+		name   string
+		result func(t *testing.T, cb callback) *payment.Result
+	}{
+		{name: payment.GatewayVNPay, result: func(t *testing.T, cb callback) *payment.Result {
+			g := newVNPay("TESTTMN1", "vnpay-secret")
+			verify := g.VerifyReturn
+			if cb.ipn {
+				verify = g.VerifyIPN
+			}
+			res, err := verify(vnpayRequest(vnpayResult(t, g, testTxnRef, cb.amount, cb.outcome), cb.ipn))
+			if err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+			return res
+		}},
+		{name: payment.GatewayMoMo, result: func(t *testing.T, cb callback) *payment.Result {
+			g := newMoMo("MOMOTEST1", "momo-secret")
+			verify := g.VerifyReturn
+			if cb.ipn {
+				verify = g.VerifyIPN
+			}
+			res, err := verify(momoRequest(t, momoResult(g, "MOMOTEST1", testTxnRef, cb.amount, cb.outcome), cb.ipn))
+			if err != nil {
+				t.Fatalf("verify: %v", err)
+			}
+			return res
+		}},
+	}
+
+	for _, gw := range gateways {
+		for _, tt := range tests {
+			t.Run(gw.name+"/"+tt.name, func(t *testing.T) {
+				db := dbtest.Open(t, &payment.Payment{})
+				p := payment.Payment{OrderID: testOrderID, Gateway: gw.name, TxnRef: testTxnRef, Amount: money.VNDOf(testAmount), Status: payment.StatusPending}
+				if err := db.Create(&p).Error; err != nil {
+					t.Fatalf("create payment: %v", err)
+				}
+				orders := &fakeOrders{pending: !tt.cancelled, cancelled: tt.cancelled}
+				marker := &fakePaid{closed: tt.cancelled}
+				svc := payment.NewService(payment.NewRepository(db), orders, marker, nil, "http://localhost")
+
+				results := make([]*payment.Result, len(tt.callbacks))
+				for i, cb := range tt.callbacks {
+					results[i] = gw.result(t, cb)
+				}
+				errs := make([]error, len(results))
+				if tt.concurrent {
+					var wg sync.WaitGroup
+					for i, res := range results {
+						wg.Add(1)
+						go func() {
+							defer wg.Done()
+							_, errs[i] = svc.Apply(context.Background(), gw.name, res)
+						}()
+					}
+					wg.Wait()
+					applied := 0
+					for i, err := range errs {
+						switch err {
+						case nil:
+							applied++
+						case errors.ErrPaymentSettled:
+						default:
+							t.Errorf("callback %d: unexpected error: %v", i+1, err)
+						}
+					}
+					if applied != 1 {
+						t.Errorf("%d callbacks applied the payment, want 1", applied)
+					}
+				} else {
+					for i, res := range results {
+						_, errs[i] = svc.Apply(context.Background(), gw.name, res)
+						if errs[i] != tt.wantErrs[i] {
+							t.Errorf("callback %d: err = %v, want %v", i+1, errs[i], tt.wantErrs[i])
+						}
+					}
+					if orders.confirms != tt.wantConfs {
+						t.Errorf("Confirm called %d times, want %d", orders.confirms, tt.wantConfs)
+					}
+				}
+
+				var got payment.Payment
+				if err := db.First(&got, p.ID).Error; err != nil {
+					t.Fatalf("load payment: %v", err)
+				}
+				if got.Status != tt.wantStatus {
+					t.Errorf("status = %s, want %s", got.Status, tt.wantStatus)
+				}
+				if (got.Note != "") != tt.wantNote {
+					t.Errorf("note = %q, want a note: %v", got.Note, tt.wantNote)
+				}
+				if marker.marks != tt.wantMarks {
+					t.Errorf("order marked paid %d times, want %d", marker.marks, tt.wantMarks)
+				}
+				if orders.confirmed != tt.wantConfed {
+					t.Errorf("order confirmed %d times, want %d", orders.confirmed, tt.wantConfed)
+				}
+			})
+		}
+	}
+}
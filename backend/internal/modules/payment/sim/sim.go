@@ -0,0 +1,95 @@
+// Package sim builds the signed results VNPay and MoMo send back for a payment.
+//
+// It is the core of cmd/payment-sim and lets tests produce real callbacks
+// without a sandbox account.
+package sim
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"go-ecommerce/internal/modules/payment"
+)
+
+// Outcomes of a simulated payment
+const (
+	Success = "success"
+	Fail    = "fail"   // Declined for insufficient balance
+	Cancel  = "cancel" // Cancelled by the customer
+)
+
+var vietnamTime = time.FixedZone("ICT", 7*60*60)
+
+// VNPayResult returns the signed query VNPay sends to the return URL and the IPN
+// for a payment started with the pay URL query q
+func VNPayResult(g *payment.VNPay, q url.Values, outcome string, txnNo int64, now time.Time) url.Values {
+	code, status := "00", "00"
+	switch outcome {
+	case Fail:
+		code, status = "51", "02"
+	case Cancel:
+		code, status = "24", "02"
+	}
+
+	res := url.Values{}
+	res.Set("vnp_TmnCode", q.Get("vnp_TmnCode"))
+	res.Set("vnp_Amount", q.Get("vnp_Amount"))
+	res.Set("vnp_BankCode", "NCB")
+	res.Set("vnp_CardType", "ATM")
+	res.Set("vnp_OrderInfo", q.Get("vnp_OrderInfo"))
+	res.Set("vnp_PayDate", now.In(vietnamTime).Format("20060102150405"))
+	res.Set("vnp_ResponseCode", code)
+	res.Set("vnp_TransactionNo", strconv.FormatInt(txnNo, 10))
+	res.Set("vnp_TransactionStatus", status)
+	res.Set("vnp_TxnRef", q.Get("vnp_TxnRef"))
+	res.Set("vnp_SecureHash", g.Sign(res))
+	return res
+}
+
+// MoMoResult returns the signed result MoMo posts to the IPN for a create request
+func MoMoResult(g *payment.MoMo, req *payment.MoMoCreateRequest, outcome string, transID int64, now time.Time) *payment.MoMoResult {
+	code, message := 0, "Thành công."
+	switch outcome {
+	case Fail:
+		code, message = 1001, "Giao dịch thất bại do tài khoản người dùng không đủ tiền."
+	case Cancel:
+		code, message = 1006, "Giao dịch thất bại do người dùng đã từ chối xác nhận thanh toán."
+	}
+
+	res := &payment.MoMoResult{
+		PartnerCode:  req.PartnerCode,
+		OrderID:      req.OrderID,
+		RequestID:    req.RequestID,
+		Amount:       req.Amount,
+		OrderInfo:    req.OrderInfo,
+		OrderType:    "momo_wallet",
+		TransID:      transID,
+		ResultCode:   code,
+		Message:      message,
+		PayType:      "qr",
+		ResponseTime: now.UnixMilli(),
+		ExtraData:    req.ExtraData,
+	}
+	res.Signature = g.SignResult(res)
+	return res
+}
+
+// MoMoReturnQuery is the same result as MoMo appends it to the redirect URL
+func MoMoReturnQuery(res *payment.MoMoResult) url.Values {
+	q := url.Values{}
+	q.Set("partnerCode", res.PartnerCode)
+	q.Set("orderId", res.OrderID)
+	q.Set("requestId", res.RequestID)
+	q.Set("amount", strconv.FormatInt(res.Amount, 10))
+	q.Set("orderInfo", res.OrderInfo)
+	q.Set("orderType", res.OrderType)
+	q.Set("transId", strconv.FormatInt(res.TransID, 10))
+	q.Set("resultCode", strconv.Itoa(res.ResultCode))
+	q.Set("message", res.Message)
+	q.Set("payType", res.PayType)
+	q.Set("responseTime", strconv.FormatInt(res.ResponseTime, 10))
+	q.Set("extraData", res.ExtraData)
+	q.Set("signature", res.Signature)
+	return q
+}
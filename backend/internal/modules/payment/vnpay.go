@@ -0,0 +1,118 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"go-ecommerce/internal/config"
+	"go-ecommerce/internal/shared/errors"
+)
+
+// VNPay implements the VNPay 2.1.0 redirect flow
+// Parameters are signed with HMAC-SHA512 over the URL-encoded query sorted by key.
+// Return and IPN callbacks carry the same signed query; the IPN URL is set up
+// in the VNPay merchant portal, not per payment.
+type VNPay struct {
+	tmnCode    string
+	hashSecret string
+	payURL     string
+}
+
+func NewVNPay(cfg *config.VNPayConfig) *VNPay {
+	return &VNPay{tmnCode: cfg.TmnCode, hashSecret: cfg.HashSecret, payURL: cfg.URL}
+}
+
+func (g *VNPay) Name() string {
+	return GatewayVNPay
+}
+
+func (g *VNPay) CreatePaymentURL(ctx context.Context, req PaymentRequest) (string, error) {
+	params := url.Values{}
+	params.Set("vnp_Version", "2.1.0")
+	params.Set("vnp_Command", "pay")
+	params.Set("vnp_TmnCode", g.tmnCode)
+	params.Set("vnp_Amount", strconv.FormatInt(req.Amount*100, 10)) // VNPay counts in 1/100 đồng
+	params.Set("vnp_CurrCode", "VND")
+	params.Set("vnp_TxnRef", req.TxnRef)
+	params.Set("vnp_OrderInfo", req.OrderInfo)
+	params.Set("vnp_OrderType", "other")
+	params.Set("vnp_Locale", "vn")
+	params.Set("vnp_ReturnUrl", req.ReturnURL)
+	params.Set("vnp_IpAddr", req.ClientIP)
+	params.Set("vnp_CreateDate", req.CreatedAt.In(vietnamTime).Format("20060102150405"))
+	if !req.ExpiresAt.IsZero() {
+		params.Set("vnp_ExpireDate", req.ExpiresAt.In(vietnamTime).Format("20060102150405"))
+	}
+	params.Set("vnp_SecureHash", g.Sign(params))
+	return g.payURL + "?" + params.Encode(), nil
+}
+
+func (g *VNPay) VerifyReturn(r *http.Request) (*Result, error) {
+	return g.verify(r.URL.Query())
+}
+
+func (g *VNPay) VerifyIPN(r *http.Request) (*Result, error) {
+	return g.verify(r.URL.Query())
+}
+
+// AckIPN replies with the RspCode VNPay expects; VNPay retries on anything but 00 and 02
+func (g *VNPay) AckIPN(w http.ResponseWriter, err error) {
+	code, message := "00", "Confirm Success"
+	switch err {
+	case nil:
+	case errors.ErrPaymentSettled:
+		code, message = "02", "Order already confirmed"
+	case errors.ErrRecordNotFound:
+		code, message = "01", "Order not found"
+	case errors.ErrPaymentAmount:
+		code, message = "04", "Invalid amount"
+	case errors.ErrInvalidSignature:
+		code, message = "97", "Invalid signature"
+	default:
+		code, message = "99", "Unknown error"
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"RspCode": code, "Message": message})
+}
+
+// Sign returns vnp_SecureHash for the vnp_ parameters, ignoring any hash already set
+func (g *VNPay) Sign(params url.Values) string {
+	signed := url.Values{}
+	for key, values := range params {
+		if key == "vnp_SecureHash" || key == "vnp_SecureHashType" || len(values) == 0 {
+			continue
+		}
+		signed.Set(key, values[0])
+	}
+	mac := hmac.New(sha512.New, []byte(g.hashSecret))
+	mac.Write([]byte(signed.Encode())) // Encode sorts by key
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (g *VNPay) verify(params url.Values) (*Result, error) {
+	hash, err := hex.DecodeString(params.Get("vnp_SecureHash"))
+	if err != nil || params.Get("vnp_TmnCode") != g.tmnCode {
+		return nil, errors.ErrInvalidSignature
+	}
+	expected, _ := hex.DecodeString(g.Sign(params))
+	if !hmac.Equal(hash, expected) {
+		return nil, errors.ErrInvalidSignature
+	}
+
+	amount, err := strconv.ParseInt(params.Get("vnp_Amount"), 10, 64)
+	if err != nil {
+		return nil, errors.ErrInvalidSignature
+	}
+	code := params.Get("vnp_ResponseCode")
+	return &Result{
+		TxnRef:       params.Get("vnp_TxnRef"),
+		Amount:       amount / 100,
+		Success:      code == "00" && params.Get("vnp_TransactionStatus") == "00",
+		GatewayTxnID: params.Get("vnp_TransactionNo"),
+		ResponseCode: code,
+	}, nil
+}
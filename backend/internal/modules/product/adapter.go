@@ -2,6 +2,7 @@ package product
 
 import (
 	"context"
+	"fmt"
 
 	"go-ecommerce/internal/modules/brand"
 	"go-ecommerce/internal/modules/category"
@@ -24,6 +25,30 @@ func (a *CategoryRepoAdapter) GetByID(ctx context.Context, id uint) (string, err
 	return cat.Name, nil
 }
 
+// FindByNameOrSlug matches a slug first, then a unique name
+func (a *CategoryRepoAdapter) FindByNameOrSlug(ctx context.Context, key string) (uint, string, error) {
+	if cat, err := a.repo.GetBySlug(ctx, key); err == nil {
+		return cat.ID, cat.Name, nil
+	}
+
+	cats, err := a.repo.GetByName(ctx, key)
+	if err != nil {
+		return 0, "", err
+	}
+	switch len(cats) {
+	case 0:
+		return 0, "", fmt.Errorf("category %q not found", key)
+	case 1:
+		return cats[0].ID, cats[0].Name, nil
+	default:
+		return 0, "", fmt.Errorf("category name %q is ambiguous, use its slug", key)
+	}
+}
+
+func (a *CategoryRepoAdapter) GetDescendantIDs(ctx context.Context, id uint) ([]uint, error) {
+	return a.repo.GetDescendantIDs(ctx, id)
+}
+
 // BrandRepoAdapter adapts brand.Repository to BrandGetter
 type BrandRepoAdapter struct {
 	repo brand.Repository
@@ -40,3 +65,23 @@ func (a *BrandRepoAdapter) GetByID(ctx context.Context, id uint) (string, error)
 	}
 	return b.Name, nil
 }
+
+// FindByNameOrSlug matches a slug first, then a unique name
+func (a *BrandRepoAdapter) FindByNameOrSlug(ctx context.Context, key string) (uint, string, error) {
+	if b, err := a.repo.GetBySlug(ctx, key); err == nil {
+		return b.ID, b.Name, nil
+	}
+
+	brands, err := a.repo.GetByName(ctx, key)
+	if err != nil {
+		return 0, "", err
+	}
+	switch len(brands) {
+	case 0:
+		return 0, "", fmt.Errorf("brand %q not found", key)
+	case 1:
+		return brands[0].ID, brands[0].Name, nil
+	default:
+		return 0, "", fmt.Errorf("brand name %q is ambiguous, use its slug", key)
+	}
+}
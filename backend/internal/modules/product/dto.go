@@ -1,12 +1,27 @@
 package product
 
-import "time"
+import (
+	"time"
+
+	"go-ecommerce/internal/modules/question"
+	"go-ecommerce/pkg/money"
+)
 
 // VariantInput represents variant data from form
+// When the product has options, Options maps option name -> value
+// (e.g. {"Màu sắc": "Đỏ", "Kích thước": "M"}) and Size may be left empty.
 type VariantInput struct {
-	Price float64 `json:"price" binding:"required,min=0"`
-	Stock int     `json:"stock" binding:"required,min=0"`
-	Size  string  `json:"size" binding:"required,min=1,max=50"`
+	Price   money.Money       `json:"price"`
+	Stock   int               `json:"stock" binding:"min=0"`
+	Size    string            `json:"size" binding:"omitempty,max=50"`
+	Options map[string]string `json:"options"`
+}
+
+// OptionInput represents an option definition from form
+// Example: {"name": "Màu sắc", "values": ["Đỏ", "Xanh"]}
+type OptionInput struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
 }
 
 // CreateProductRequest - Request body (multipart/form-data)
@@ -16,7 +31,18 @@ type CreateProductRequest struct {
 	CategoryID  uint   `form:"category_id" binding:"required"`
 	BrandID     uint   `form:"brand_id" binding:"required"`
 	// Variants will be parsed from JSON string in form-data
+	// Options will be parsed from JSON string in form-data (optional)
 	// Images will be uploaded files
+
+	// Used for every generated variant when options are given without variants
+	DefaultPrice money.Money `form:"default_price"`
+	DefaultStock int         `form:"default_stock" binding:"min=0"`
+}
+
+// GenerateVariantsRequest - Request body for generating the variant matrix
+type GenerateVariantsRequest struct {
+	Price money.Money `json:"price"`
+	Stock int         `json:"stock" binding:"min=0"`
 }
 
 // UpdateProductRequest - Request body for update
@@ -25,33 +51,162 @@ type UpdateProductRequest struct {
 	Description string `form:"description"`
 	CategoryID  uint   `form:"category_id"`
 	BrandID     uint   `form:"brand_id"`
-	// Allow updating variants and images
+	// Variants and images are managed through their own endpoints
+}
+
+// UpdateVariantRequest - Request body for updating a variant
+// Option values are fixed; remove the variant and add a new one to change them.
+// A compare_at_price of 0 removes it.
+type UpdateVariantRequest struct {
+	Price          *money.Money `json:"price"`
+	Stock          *int         `json:"stock" binding:"omitempty,min=0"` // Counted in WarehouseID
+	WarehouseID    *uint        `json:"warehouse_id"`                    // Optional, the default warehouse if omitted
+	Size           *string      `json:"size" binding:"omitempty,min=1,max=50"`
+	CompareAtPrice *money.Money `json:"compare_at_price"`
+	Reason         string       `json:"reason" binding:"max=255"` // Kept in the price history and stock ledger
+
+	ReorderThreshold *int `json:"reorder_threshold" binding:"omitempty,min=0"` // 0 turns low-stock alerts off
+}
+
+// StockMovementRequest - Request body for a manual stock movement
+// Quantity is positive for receipt, return and damage (damage removes stock)
+// and signed for adjustment. Sales only come from orders.
+type StockMovementRequest struct {
+	Type        MovementType `json:"type" binding:"required,oneof=receipt return adjustment damage"`
+	Quantity    int          `json:"quantity" binding:"required"`
+	Reason      string       `json:"reason" binding:"required,max=255"`
+	WarehouseID *uint        `json:"warehouse_id"` // Optional, the default warehouse if omitted
+}
+
+// TransferStockRequest - Request body for moving stock between warehouses
+type TransferStockRequest struct {
+	FromWarehouseID uint   `json:"from_warehouse_id" binding:"required"`
+	ToWarehouseID   uint   `json:"to_warehouse_id" binding:"required"`
+	Quantity        int    `json:"quantity" binding:"required,min=1"`
+	Reason          string `json:"reason" binding:"max=255"`
+}
+
+// SetSaleRequest - Request body for putting a variant on sale
+// Both times are optional: no start means now, no end means until the sale is removed.
+type SetSaleRequest struct {
+	SalePrice *money.Money `json:"sale_price" binding:"required"`
+	StartsAt  *time.Time   `json:"starts_at"`
+	EndsAt    *time.Time   `json:"ends_at"`
+}
+
+// SetBackorderRequest - Request body for selling a variant beyond its stock
+// Mode none stops taking backorders; units already owed are still allocated.
+type SetBackorderRequest struct {
+	Mode        BackorderMode `json:"mode" binding:"required,oneof=none backorder preorder"`
+	Limit       int           `json:"limit" binding:"min=0"` // Units that may be owed at once
+	AvailableAt *time.Time    `json:"available_at"`          // Required for pre-orders
+}
+
+// ReorderImagesRequest - Request body for reordering images
+// ImageIDs must list every image of the product in the new display order.
+type ReorderImagesRequest struct {
+	ImageIDs []uint `json:"image_ids" binding:"required,min=1,max=5"`
+}
+
+// ScheduleProductRequest - Request body for scheduled publish/unpublish
+// Either time may be null to clear it.
+type ScheduleProductRequest struct {
+	PublishAt   *time.Time `json:"publish_at"`
+	UnpublishAt *time.Time `json:"unpublish_at"`
+}
+
+// ReadinessResponse - Result of the publish-readiness check
+type ReadinessResponse struct {
+	Ready  bool     `json:"ready"`
+	Issues []string `json:"issues"`
+}
+
+// ProductFilter - Filters for product listing
+type ProductFilter struct {
+	CategoryIDs []uint          // Category and its descendants
+	Statuses    []ProductStatus // Empty means any status
 }
 
 // ProductResponse - Full response with nested data
 type ProductResponse struct {
-	ID          uint              `json:"id"`
-	Name        string            `json:"name"`
-	Slug        string            `json:"slug"`
-	Description string            `json:"description"`
-	CategoryID  uint              `json:"category_id"`
-	BrandID     uint              `json:"brand_id"`
-	TotalStock  int               `json:"total_stock"`
-	RatingAvg   float64           `json:"rating_avg"`
-	ReviewCount int               `json:"review_count"`
-	Variants    []VariantResponse `json:"variants"`
-	Images      []ImageResponse   `json:"images"`
-	CreatedAt   time.Time         `json:"created_at"`
-	UpdatedAt   time.Time         `json:"updated_at"`
+	ID           uint              `json:"id"`
+	Name         string            `json:"name"`
+	Slug         string            `json:"slug"`
+	Description  string            `json:"description"`
+	CategoryID   uint              `json:"category_id"`
+	BrandID      uint              `json:"brand_id"`
+	TotalStock   int               `json:"total_stock"`
+	RatingAvg    float64           `json:"rating_avg"`
+	ReviewCount  int               `json:"review_count"`
+	Status       ProductStatus     `json:"status"`
+	PublishAt    *time.Time        `json:"publish_at"`
+	UnpublishAt  *time.Time        `json:"unpublish_at"`
+	PublishedAt  *time.Time        `json:"published_at"`
+	PublishError string            `json:"publish_error,omitempty"`
+	Options      []OptionResponse  `json:"options"`
+	Variants     []VariantResponse `json:"variants"`
+	Images       []ImageResponse   `json:"images"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	DeletedAt    *time.Time        `json:"deleted_at,omitempty"`
+}
+
+// ProductDetailResponse - Storefront product detail with answered questions
+type ProductDetailResponse struct {
+	*ProductResponse
+	Questions []question.QuestionResponse `json:"questions"`
+}
+
+// OptionResponse - Option definition DTO
+type OptionResponse struct {
+	ID     uint                  `json:"id"`
+	Name   string                `json:"name"`
+	Values []OptionValueResponse `json:"values"`
+}
+
+// OptionValueResponse - Option value DTO
+type OptionValueResponse struct {
+	ID    uint   `json:"id"`
+	Value string `json:"value"`
+	Code  string `json:"code"`
 }
 
 // VariantResponse - Variant DTO
+// Price is always the effective price; CompareAtPrice is the "was" price, nil when not discounted.
 type VariantResponse struct {
-	ID    uint    `json:"id"`
-	Price float64 `json:"price"`
-	Stock int     `json:"stock"`
-	Size  string  `json:"size"`
-	SKU   string  `json:"sku"`
+	ID             uint                    `json:"id"`
+	Price          money.Money             `json:"price"`
+	RegularPrice   money.Money             `json:"regular_price"`
+	CompareAtPrice *money.Money            `json:"compare_at_price"`
+	OnSale         bool                    `json:"on_sale"`
+	LowestPrice30d *money.Money            `json:"lowest_price_30d,omitempty"` // Only for variants on sale
+	Sale           *SaleResponse           `json:"sale,omitempty"`
+	Backorder      *BackorderResponse      `json:"backorder,omitempty"` // Only for variants taking backorders or pre-orders
+	Stock          int                     `json:"stock"`
+	Size           string                  `json:"size"`
+	SKU            string                  `json:"sku"`
+	Options        []VariantOptionResponse `json:"options,omitempty"`
+}
+
+// SaleResponse - A scheduled or running sale of a variant
+type SaleResponse struct {
+	SalePrice money.Money `json:"sale_price"`
+	StartsAt  *time.Time  `json:"starts_at"`
+	EndsAt    *time.Time  `json:"ends_at"`
+	Active    bool        `json:"active"`
+}
+
+// BackorderResponse - How a variant sells beyond its stock
+type BackorderResponse struct {
+	Mode        BackorderMode `json:"mode"`
+	Remaining   int           `json:"remaining"` // Units that can still be ordered beyond stock
+	AvailableAt *time.Time    `json:"available_at,omitempty"`
+}
+
+// VariantOptionResponse - One option value of a variant
+type VariantOptionResponse struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 // ImageResponse - Image DTO
@@ -61,17 +216,85 @@ type ImageResponse struct {
 	DisplayOrder int    `json:"display_order"`
 }
 
+// ConvertPrices rewrites every variant price for display in another currency
+func (p *ProductResponse) ConvertPrices(convert func(money.Money) money.Money) {
+	convertPtr := func(m *money.Money) *money.Money {
+		if m == nil {
+			return nil
+		}
+		converted := convert(*m)
+		return &converted
+	}
+
+	for i := range p.Variants {
+		v := &p.Variants[i]
+		v.Price = convert(v.Price)
+		v.RegularPrice = convert(v.RegularPrice)
+		v.CompareAtPrice = convertPtr(v.CompareAtPrice)
+		v.LowestPrice30d = convertPtr(v.LowestPrice30d)
+		if v.Sale != nil {
+			sale := *v.Sale
+			sale.SalePrice = convert(sale.SalePrice)
+			v.Sale = &sale
+		}
+	}
+}
+
+// ToVariantResponse converts a variant to its DTO with prices resolved
+func ToVariantResponse(v *ProductVariant, options []VariantOptionResponse) VariantResponse {
+	res := VariantResponse{
+		ID:             v.ID,
+		Price:          v.EffectivePrice(),
+		RegularPrice:   v.Price,
+		CompareAtPrice: v.WasPrice(),
+		OnSale:         v.SaleActive && v.SalePrice != nil,
+		Stock:          v.Stock,
+		Size:           v.Size,
+		SKU:            v.SKU,
+		Options:        options,
+	}
+	if v.SalePrice != nil {
+		res.Sale = &SaleResponse{
+			SalePrice: *v.SalePrice,
+			StartsAt:  v.SaleStartsAt,
+			EndsAt:    v.SaleEndsAt,
+			Active:    v.SaleActive,
+		}
+	}
+	if v.BackorderMode == BackorderAllowed || v.BackorderMode == BackorderPreorder {
+		res.Backorder = &BackorderResponse{
+			Mode:        v.BackorderMode,
+			Remaining:   v.BackorderRoom(),
+			AvailableAt: v.AvailableAt,
+		}
+	}
+	return res
+}
+
 // ToProductResponse converts entity to response DTO
 func ToProductResponse(p *Product) *ProductResponse {
+	var options []OptionResponse
+	optionNames := make(map[uint]string)
+	for _, opt := range p.Options {
+		var values []OptionValueResponse
+		for _, val := range opt.Values {
+			values = append(values, OptionValueResponse{ID: val.ID, Value: val.Value, Code: val.Code})
+			optionNames[val.ID] = opt.Name
+		}
+		options = append(options, OptionResponse{ID: opt.ID, Name: opt.Name, Values: values})
+	}
+
 	var variants []VariantResponse
 	for _, v := range p.Variants {
-		variants = append(variants, VariantResponse{
-			ID:    v.ID,
-			Price: v.Price,
-			Stock: v.Stock,
-			Size:  v.Size,
-			SKU:   v.SKU,
-		})
+		var variantOptions []VariantOptionResponse
+		for _, val := range v.OptionValues {
+			variantOptions = append(variantOptions, VariantOptionResponse{
+				Name:  optionNames[val.ID],
+				Value: val.Value,
+			})
+		}
+
+		variants = append(variants, ToVariantResponse(&v, variantOptions))
 	}
 
 	var images []ImageResponse
@@ -83,19 +306,122 @@ func ToProductResponse(p *Product) *ProductResponse {
 		})
 	}
 
+	var deletedAt *time.Time
+	if p.DeletedAt.Valid {
+		deletedAt = &p.DeletedAt.Time
+	}
+
 	return &ProductResponse{
-		ID:          p.ID,
-		Name:        p.Name,
-		Slug:        p.Slug,
-		Description: p.Description,
-		CategoryID:  p.CategoryID,
-		BrandID:     p.BrandID,
-		TotalStock:  p.TotalStock,
-		RatingAvg:   p.RatingAvg,
-		ReviewCount: p.ReviewCount,
-		Variants:    variants,
-		Images:      images,
-		CreatedAt:   p.CreatedAt,
-		UpdatedAt:   p.UpdatedAt,
+		ID:           p.ID,
+		Name:         p.Name,
+		Slug:         p.Slug,
+		Description:  p.Description,
+		CategoryID:   p.CategoryID,
+		BrandID:      p.BrandID,
+		TotalStock:   p.TotalStock,
+		RatingAvg:    p.RatingAvg,
+		ReviewCount:  p.ReviewCount,
+		Status:       p.Status,
+		PublishAt:    p.PublishAt,
+		UnpublishAt:  p.UnpublishAt,
+		PublishedAt:  p.PublishedAt,
+		PublishError: p.PublishError,
+		Options:      options,
+		Variants:     variants,
+		Images:       images,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+		DeletedAt:    deletedAt,
 	}
 }
+
+// ImportRowError - one problem found in an import file
+// Row is the spreadsheet row number (the header is row 1).
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportProduct - a product assembled from one or more import rows
+type ImportProduct struct {
+	Row          int // First row of the product
+	Request      CreateProductRequest
+	Options      []OptionInput
+	Variants     []VariantInput
+	ImageURLs    []string
+	CategoryName string
+}
+
+// ImportPreview - summary of a product that will be created
+type ImportPreview struct {
+	Row      int      `json:"row"`
+	Name     string   `json:"name"`
+	Category string   `json:"category"`
+	Variants int      `json:"variants"`
+	Images   int      `json:"images"`
+	Options  []string `json:"options,omitempty"`
+}
+
+// ImportReport - validation result of an import file
+type ImportReport struct {
+	DryRun        bool             `json:"dry_run"`
+	TotalRows     int              `json:"total_rows"`
+	TotalProducts int              `json:"total_products"`
+	Valid         bool             `json:"valid"`
+	Errors        []ImportRowError `json:"errors"`
+	Products      []ImportPreview  `json:"products,omitempty"`
+}
+
+// ExportProduct - one line of a JSON Lines export
+type ExportProduct struct {
+	ID          uint            `json:"id"`
+	Name        string          `json:"name"`
+	Slug        string          `json:"slug"`
+	Status      ProductStatus   `json:"status"`
+	Description string          `json:"description"`
+	Category    string          `json:"category"`
+	Brand       string          `json:"brand"`
+	TotalStock  int             `json:"total_stock"`
+	Images      []string        `json:"images"`
+	Variants    []ExportVariant `json:"variants"`
+}
+
+// ExportVariant - variant inside ExportProduct
+type ExportVariant struct {
+	SKU     string            `json:"sku"`
+	Price   money.Money       `json:"price"`
+	Stock   int               `json:"stock"`
+	Size    string            `json:"size"`
+	Options map[string]string `json:"options,omitempty"`
+}
+
+// StockLevelResponse - Stock of a variant in one warehouse
+type StockLevelResponse struct {
+	WarehouseID   uint   `json:"warehouse_id"`
+	WarehouseCode string `json:"warehouse_code"`
+	WarehouseName string `json:"warehouse_name"`
+	Stock         int    `json:"stock"`
+}
+
+// LowStockItem - Variant at or below its reorder threshold
+type LowStockItem struct {
+	ProductID        uint   `json:"product_id"`
+	ProductName      string `json:"product_name"`
+	VariantID        uint   `json:"variant_id"`
+	SKU              string `json:"sku"`
+	Size             string `json:"size"`
+	Stock            int    `json:"stock"`
+	ReorderThreshold int    `json:"reorder_threshold"`
+}
+
+// StockDiscrepancy - stock that does not match the ledger
+// VariantID is 0 for a product whose total_stock is off; WarehouseID is 0
+// for a variant total and set for the stock of one warehouse.
+type StockDiscrepancy struct {
+	ProductID   uint
+	VariantID   uint
+	WarehouseID uint
+	Stock       int
+	Ledger      int
+}
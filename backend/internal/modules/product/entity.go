@@ -1,22 +1,51 @@
 package product
 
-import "time"
+import (
+	"time"
+
+	"go-ecommerce/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+// ProductStatus Enum
+type ProductStatus string
+
+const (
+	StatusDraft     ProductStatus = "draft"
+	StatusScheduled ProductStatus = "scheduled" // Waiting for PublishAt
+	StatusPublished ProductStatus = "published"
+	StatusArchived  ProductStatus = "archived"
+)
 
 // Product entity
+// Status defaults to published at the database level so rows created before
+// lifecycle states existed stay visible; new products start as draft.
 type Product struct {
-	ID          uint      `gorm:"primaryKey" json:"id"`
-	Name        string    `gorm:"type:text;not null" json:"name"`
-	Description string    `gorm:"type:text" json:"description"`
-	Slug        string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"slug"`
-	CategoryID  uint      `gorm:"not null;index" json:"category_id"`
-	BrandID     uint      `gorm:"not null;index" json:"brand_id"`
-	TotalStock  int       `gorm:"default:0" json:"total_stock"`
-	RatingAvg   float64   `gorm:"default:0" json:"rating_avg"`
-	ReviewCount int       `gorm:"default:0" json:"review_count"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          uint    `gorm:"primaryKey" json:"id"`
+	Name        string  `gorm:"type:text;not null" json:"name"`
+	Description string  `gorm:"type:text" json:"description"`
+	Slug        string  `gorm:"type:varchar(255);uniqueIndex;not null" json:"slug"`
+	CategoryID  uint    `gorm:"not null;index" json:"category_id"`
+	BrandID     uint    `gorm:"not null;index" json:"brand_id"`
+	TotalStock  int     `gorm:"default:0" json:"total_stock"`
+	RatingAvg   float64 `gorm:"default:0" json:"rating_avg"`
+	ReviewCount int     `gorm:"default:0" json:"review_count"`
+
+	// Lifecycle
+	Status      ProductStatus `gorm:"type:varchar(20);not null;default:'published';index" json:"status"`
+	PublishAt   *time.Time    `gorm:"index" json:"publish_at"`
+	UnpublishAt *time.Time    `gorm:"index" json:"unpublish_at"`
+	PublishedAt *time.Time    `json:"published_at"`
+	// Why the scheduler could not publish the product at PublishAt; cleared when it is rescheduled or published
+	PublishError string `gorm:"type:varchar(500);not null;default:''" json:"publish_error"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // Soft delete (trash)
 
 	// Relationships
+	Options  []ProductOption  `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"options,omitempty"`
 	Variants []ProductVariant `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"variants,omitempty"`
 	Images   []ProductImage   `gorm:"foreignKey:ProductID;constraint:OnDelete:CASCADE" json:"images,omitempty"`
 }
@@ -25,22 +54,240 @@ func (Product) TableName() string {
 	return "products"
 }
 
-// ProductVariant entity
-type ProductVariant struct {
+// ProductOption entity - an option definition of a product, e.g. "Màu sắc" or "Kích thước"
+type ProductOption struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ProductID uint      `gorm:"not null;uniqueIndex:idx_product_option_name,priority:1" json:"product_id"`
+	Name      string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_product_option_name,priority:2" json:"name"`
+	Position  int       `gorm:"not null;default:0" json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Values []ProductOptionValue `gorm:"foreignKey:OptionID;constraint:OnDelete:CASCADE" json:"values,omitempty"`
+}
+
+func (ProductOption) TableName() string {
+	return "product_options"
+}
+
+// ProductOptionValue entity - one value of an option, e.g. "Đỏ" or "XL"
+type ProductOptionValue struct {
 	ID        uint      `gorm:"primaryKey" json:"id"`
-	ProductID uint      `gorm:"not null;index" json:"product_id"`
-	Price     float64   `gorm:"not null;check:price >= 0" json:"price"`
-	Stock     int       `gorm:"not null;check:stock >= 0" json:"stock"`
-	Size      string    `gorm:"type:varchar(50);not null" json:"size"`
-	SKU       string    `gorm:"type:varchar(100);uniqueIndex" json:"sku"`
+	OptionID  uint      `gorm:"not null;uniqueIndex:idx_option_value,priority:1" json:"option_id"`
+	Value     string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_option_value,priority:2" json:"value"`
+	Code      string    `gorm:"type:varchar(20);not null" json:"code"` // Short code used in SKUs
+	Position  int       `gorm:"not null;default:0" json:"position"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+func (ProductOptionValue) TableName() string {
+	return "product_option_values"
+}
+
+// BackorderMode enum
+type BackorderMode string
+
+const (
+	BackorderNone     BackorderMode = "none"      // Sold out at 0 stock
+	BackorderAllowed  BackorderMode = "backorder" // Sold beyond stock, shipped when restocked
+	BackorderPreorder BackorderMode = "preorder"  // Not released yet, shipped from AvailableAt
+)
+
+// ProductVariant entity
+// OptionKey identifies the combination of option values (see optionKey) and is
+// unique per product; it is empty for legacy single-size variants.
+// Price is the regular price; while SaleActive the customer pays SalePrice
+// (see EffectivePrice). SaleActive is switched by the scheduler at the window edges.
+type ProductVariant struct {
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	ProductID uint        `gorm:"not null;index;uniqueIndex:idx_variant_option_key,priority:1" json:"product_id"`
+	Price     money.Money `gorm:"not null;check:price >= 0" json:"price"`
+	Stock     int         `gorm:"not null;check:stock >= 0" json:"stock"`
+	Size      string      `gorm:"type:varchar(50);not null" json:"size"`
+	SKU       string      `gorm:"type:varchar(100);uniqueIndex" json:"sku"`
+	OptionKey string      `gorm:"type:varchar(255);not null;default:'';uniqueIndex:idx_variant_option_key,priority:2,where:option_key <> ''" json:"-"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+
+	// Pricing
+	CompareAtPrice *money.Money `gorm:"check:compare_at_price >= 0" json:"compare_at_price"` // "Was" price shown struck through
+	SalePrice      *money.Money `gorm:"check:sale_price >= 0" json:"sale_price"`
+	SaleStartsAt   *time.Time   `json:"sale_starts_at"` // nil: starts immediately
+	SaleEndsAt     *time.Time   `json:"sale_ends_at"`   // nil: runs until cleared
+	SaleActive     bool         `gorm:"not null;default:false;index" json:"sale_active"`
+
+	// Inventory
+	ReorderThreshold int `gorm:"not null;default:0;check:reorder_threshold >= 0" json:"reorder_threshold"` // Reported as low stock at or below it; 0 disables
+
+	// Backorders: beyond Stock, up to BackorderLimit units may be owed to orders at once
+	BackorderMode  BackorderMode `gorm:"type:varchar(20);not null;default:'none'" json:"backorder_mode"`
+	BackorderLimit int           `gorm:"not null;default:0;check:backorder_limit >= 0" json:"backorder_limit"`
+	Backordered    int           `gorm:"not null;default:0;check:backordered >= 0" json:"backordered"` // Units owed right now
+	AvailableAt    *time.Time    `json:"available_at"`                                                 // Expected availability shown to customers
+
+	OptionValues []ProductOptionValue `gorm:"many2many:variant_option_values;constraint:OnDelete:CASCADE" json:"option_values,omitempty"`
+}
+
 func (ProductVariant) TableName() string {
 	return "product_variants"
 }
 
+// BackorderRoom is how many more units can be sold beyond stock
+func (v *ProductVariant) BackorderRoom() int {
+	if v.BackorderMode == BackorderNone || v.BackorderMode == "" {
+		return 0
+	}
+	return max(v.BackorderLimit-v.Backordered, 0)
+}
+
+// EffectivePrice is the price the customer pays right now
+func (v *ProductVariant) EffectivePrice() money.Money {
+	if v.SaleActive && v.SalePrice != nil {
+		return *v.SalePrice
+	}
+	return v.Price
+}
+
+// WasPrice is the price shown struck through next to EffectivePrice
+// It is the compare-at price when set, otherwise the regular price during a sale;
+// nil when the variant is not discounted.
+func (v *ProductVariant) WasPrice() *money.Money {
+	effective := v.EffectivePrice()
+	if v.CompareAtPrice != nil && v.CompareAtPrice.GreaterThan(effective) {
+		return v.CompareAtPrice
+	}
+	if effective.LessThan(v.Price) {
+		price := v.Price
+		return &price
+	}
+	return nil
+}
+
+// saleRunningAt reports whether a sale is set and its window contains t
+func (v *ProductVariant) saleRunningAt(t time.Time) bool {
+	if v.SalePrice == nil {
+		return false
+	}
+	if v.SaleStartsAt != nil && v.SaleStartsAt.After(t) {
+		return false
+	}
+	return v.SaleEndsAt == nil || v.SaleEndsAt.After(t)
+}
+
+// PriceChangeReason Enum
+type PriceChangeReason string
+
+const (
+	PriceCreated     PriceChangeReason = "created"
+	PriceManual      PriceChangeReason = "manual"
+	PriceSaleStarted PriceChangeReason = "sale_started"
+	PriceSaleEnded   PriceChangeReason = "sale_ended" // Expired or removed
+
+	// ActorSystem marks changes made by the scheduler
+	ActorSystem = "system"
+)
+
+// PriceHistory entity - append-only log of variant prices
+// Price is the effective price from CreatedAt on. There is no foreign key: the
+// log outlives deleted variants and is only removed when the product is purged.
+type PriceHistory struct {
+	ID           uint              `gorm:"primaryKey" json:"id"`
+	ProductID    uint              `gorm:"not null;index" json:"product_id"`
+	VariantID    uint              `gorm:"not null;index:idx_price_history_variant,priority:1" json:"variant_id"`
+	Price        money.Money       `gorm:"not null" json:"price"`
+	RegularPrice money.Money       `gorm:"not null" json:"regular_price"`
+	OldPrice     *money.Money      `json:"old_price"` // nil for the first entry
+	Reason       PriceChangeReason `gorm:"type:varchar(20);not null" json:"reason"`
+	Note         string            `gorm:"type:varchar(255)" json:"note"`
+	Actor        string            `gorm:"type:varchar(36)" json:"actor"` // Admin user ID or "system"; empty at creation
+	CreatedAt    time.Time         `gorm:"index:idx_price_history_variant,priority:2" json:"created_at"`
+}
+
+func (PriceHistory) TableName() string {
+	return "product_price_histories"
+}
+
+// MovementType Enum
+type MovementType string
+
+const (
+	MovementReceipt    MovementType = "receipt"    // Goods received (+)
+	MovementSale       MovementType = "sale"       // Sold through an order (-), put back when it is cancelled (+)
+	MovementReturn     MovementType = "return"     // Customer return back on sale (+)
+	MovementAdjustment MovementType = "adjustment" // Stock count correction (+/-)
+	MovementDamage     MovementType = "damage"     // Damaged or lost (-)
+	MovementTransfer   MovementType = "transfer"   // Moved between warehouses, one entry per side
+)
+
+// StockMovement entity - append-only ledger of variant stock
+// The sum of Quantity per variant and warehouse equals VariantStock.Stock, and per
+// variant ProductVariant.Stock; see cmd/reconcile-stock.
+// There is no foreign key: the ledger outlives deleted variants and is only
+// removed when the product is purged.
+type StockMovement struct {
+	ID          uint         `gorm:"primaryKey" json:"id"`
+	ProductID   uint         `gorm:"not null;index" json:"product_id"`
+	VariantID   uint         `gorm:"not null;index:idx_stock_movement_variant,priority:1" json:"variant_id"`
+	WarehouseID uint         `gorm:"index" json:"warehouse_id"`
+	Type        MovementType `gorm:"type:varchar(20);not null" json:"type"`
+	Quantity    int          `gorm:"not null;check:quantity <> 0" json:"quantity"` // Signed change
+	StockAfter  int          `gorm:"not null" json:"stock_after"`                  // In the warehouse
+	Reason      string       `gorm:"type:varchar(255)" json:"reason"`
+	Actor       string       `gorm:"type:varchar(36)" json:"actor"` // Admin user ID or "system"
+	OrderID     *uint        `gorm:"index" json:"order_id,omitempty"`
+	CreatedAt   time.Time    `gorm:"index:idx_stock_movement_variant,priority:2" json:"created_at"`
+}
+
+func (StockMovement) TableName() string {
+	return "product_stock_movements"
+}
+
+// VariantStock entity - stock of a variant in one warehouse
+// ProductVariant.Stock is the sum over warehouses and is kept in step by moveStock.
+type VariantStock struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	VariantID   uint      `gorm:"not null;uniqueIndex:idx_variant_warehouse,priority:1" json:"variant_id"`
+	WarehouseID uint      `gorm:"not null;uniqueIndex:idx_variant_warehouse,priority:2;index" json:"warehouse_id"`
+	ProductID   uint      `gorm:"not null;index" json:"product_id"`
+	Stock       int       `gorm:"not null;check:stock >= 0" json:"stock"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (VariantStock) TableName() string {
+	return "variant_stocks"
+}
+
+// ReservationStatus enum
+type ReservationStatus string
+
+const (
+	ReservationHeld      ReservationStatus = "held"      // Stock taken, order not confirmed yet
+	ReservationCommitted ReservationStatus = "committed" // Order confirmed, the stock is sold
+	ReservationReleased  ReservationStatus = "released"  // Order cancelled or expired, stock put back
+)
+
+// StockReservation entity - stock taken from a variant for an order
+// Variant stock already excludes held and committed reservations. Backordered
+// ones are owed to the order and take stock when it arrives (see allocateBackorders).
+type StockReservation struct {
+	ID          uint              `gorm:"primaryKey" json:"id"`
+	OrderID     uint              `gorm:"not null;index" json:"order_id"`
+	WarehouseID uint              `gorm:"index" json:"warehouse_id"` // Warehouse the line ships from, 0 while backordered
+	ProductID   uint              `gorm:"not null" json:"product_id"`
+	VariantID   uint              `gorm:"not null;index" json:"variant_id"`
+	Quantity    int               `gorm:"not null;check:quantity > 0" json:"quantity"`
+	Status      ReservationStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	Backordered bool              `gorm:"not null;default:false" json:"backordered"` // Waiting for stock
+	ExpiresAt   time.Time         `gorm:"not null" json:"expires_at"`                // Held reservations only
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+func (StockReservation) TableName() string {
+	return "stock_reservations"
+}
+
 // ProductImage entity
 type ProductImage struct {
 	ID            uint      `gorm:"primaryKey" json:"id"`
@@ -55,3 +302,36 @@ type ProductImage struct {
 func (ProductImage) TableName() string {
 	return "product_images"
 }
+
+// ImportJobStatus Enum
+type ImportJobStatus string
+
+const (
+	ImportPending   ImportJobStatus = "pending"
+	ImportRunning   ImportJobStatus = "running"
+	ImportCompleted ImportJobStatus = "completed"
+	ImportFailed    ImportJobStatus = "failed" // Interrupted before every product was processed
+)
+
+// ImportJob entity - a bulk product import running in the background
+// Processed counts products (not rows); Errors lists products that failed to be created.
+type ImportJob struct {
+	ID            uint             `gorm:"primaryKey" json:"id"`
+	FileName      string           `gorm:"type:varchar(255)" json:"file_name"`
+	Status        ImportJobStatus  `gorm:"type:varchar(20);not null;index" json:"status"`
+	TotalRows     int              `json:"total_rows"`
+	TotalProducts int              `json:"total_products"`
+	Processed     int              `json:"processed"`
+	Created       int              `json:"created"`
+	Failed        int              `json:"failed"`
+	Errors        []ImportRowError `gorm:"type:jsonb;serializer:json" json:"errors"`
+	ProductIDs    []uint           `gorm:"type:jsonb;serializer:json" json:"product_ids"`
+	CreatedBy     string           `gorm:"type:varchar(36)" json:"created_by"` // Admin user ID
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+	FinishedAt    *time.Time       `json:"finished_at"`
+}
+
+func (ImportJob) TableName() string {
+	return "product_import_jobs"
+}
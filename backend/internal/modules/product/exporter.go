@@ -0,0 +1,230 @@
+package product
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"go-ecommerce/pkg/xlsx"
+)
+
+// ExportFormat Enum
+type ExportFormat string
+
+const (
+	ExportCSV   ExportFormat = "csv"
+	ExportXLSX  ExportFormat = "xlsx"
+	ExportJSONL ExportFormat = "jsonl"
+
+	exportBatchSize = 200
+)
+
+// ContentType returns the MIME type of the export format
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case ExportJSONL:
+		return "application/x-ndjson"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// Valid reports whether the format is supported
+func (f ExportFormat) Valid() bool {
+	return f == ExportCSV || f == ExportXLSX || f == ExportJSONL
+}
+
+// Spreadsheet exports have one row per variant; shared columns use the import names.
+var exportColumns = []interface{}{"product_id", colName, "slug", "status", colDescription, colCategory, colBrand, "sku", colPrice, colStock, colSize, colOptions, colImages}
+
+// rowWriter is implemented by the CSV and XLSX writers
+type rowWriter interface {
+	WriteRow(values []interface{}) error
+	Flush() error
+	Close() error
+}
+
+// Exporter streams the catalog to CSV, XLSX or JSON Lines
+type Exporter struct {
+	repo       Repository
+	categories CategoryGetter
+	brands     BrandGetter
+}
+
+// NewExporter creates a new catalog exporter
+func NewExporter(repo Repository, categories CategoryGetter, brands BrandGetter) *Exporter {
+	return &Exporter{repo: repo, categories: categories, brands: brands}
+}
+
+// Export writes every product matching filter to w
+// flush is called after each batch so HTTP responses stream to the client.
+func (e *Exporter) Export(ctx context.Context, w io.Writer, format ExportFormat, filter ProductFilter, flush func()) error {
+	names := newNameCache(e.categories, e.brands)
+
+	if format == ExportJSONL {
+		enc := json.NewEncoder(w)
+		return e.repo.ExportBatches(ctx, filter, exportBatchSize, func(products []Product) error {
+			for i := range products {
+				if err := enc.Encode(toExportProduct(ctx, &products[i], names)); err != nil {
+					return err
+				}
+			}
+			flush()
+			return nil
+		})
+	}
+
+	var rw rowWriter
+	if format == ExportXLSX {
+		xw, err := xlsx.NewWriter(w, "Products")
+		if err != nil {
+			return err
+		}
+		rw = xw
+	} else {
+		rw = &csvRowWriter{w: csv.NewWriter(w)}
+	}
+
+	if err := rw.WriteRow(exportColumns); err != nil {
+		return err
+	}
+
+	err := e.repo.ExportBatches(ctx, filter, exportBatchSize, func(products []Product) error {
+		for i := range products {
+			p := toExportProduct(ctx, &products[i], names)
+			images := strings.Join(p.Images, " | ")
+			for _, v := range p.Variants {
+				row := []interface{}{
+					p.ID, p.Name, p.Slug, string(p.Status), p.Description, p.Category, p.Brand,
+					v.SKU, v.Price.Float64(), v.Stock, v.Size, formatExportOptions(products[i].Options, v.Options), images,
+				}
+				if err := rw.WriteRow(row); err != nil {
+					return err
+				}
+			}
+		}
+		if err := rw.Flush(); err != nil {
+			return err
+		}
+		flush()
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return rw.Close()
+}
+
+func toExportProduct(ctx context.Context, p *Product, names *nameCache) ExportProduct {
+	res := ToProductResponse(p)
+
+	out := ExportProduct{
+		ID:          p.ID,
+		Name:        p.Name,
+		Slug:        p.Slug,
+		Status:      p.Status,
+		Description: p.Description,
+		Category:    names.category(ctx, p.CategoryID),
+		Brand:       names.brand(ctx, p.BrandID),
+		TotalStock:  p.TotalStock,
+		Images:      []string{},
+		Variants:    []ExportVariant{},
+	}
+	for _, img := range res.Images {
+		out.Images = append(out.Images, img.ImageURL)
+	}
+	for _, v := range res.Variants {
+		// Regular price, so an export does not bake running sales into the catalog
+		variant := ExportVariant{SKU: v.SKU, Price: v.RegularPrice, Stock: v.Stock, Size: v.Size}
+		if len(v.Options) > 0 {
+			variant.Options = make(map[string]string, len(v.Options))
+			for _, opt := range v.Options {
+				variant.Options[opt.Name] = opt.Value
+			}
+		}
+		out.Variants = append(out.Variants, variant)
+	}
+	return out
+}
+
+// formatExportOptions writes "Màu sắc=Đỏ; Kích thước=M" in the product's option order
+func formatExportOptions(options []ProductOption, values map[string]string) string {
+	var parts []string
+	for _, opt := range options {
+		if v, ok := values[opt.Name]; ok {
+			parts = append(parts, opt.Name+"="+v)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// nameCache looks up each category and brand name once per export
+type nameCache struct {
+	categories    CategoryGetter
+	brands        BrandGetter
+	categoryNames map[uint]string
+	brandNames    map[uint]string
+}
+
+func newNameCache(categories CategoryGetter, brands BrandGetter) *nameCache {
+	return &nameCache{
+		categories:    categories,
+		brands:        brands,
+		categoryNames: make(map[uint]string),
+		brandNames:    make(map[uint]string),
+	}
+}
+
+func (c *nameCache) category(ctx context.Context, id uint) string {
+	if name, ok := c.categoryNames[id]; ok {
+		return name
+	}
+	// Trashed categories are not found; the export keeps going with an empty name
+	name, _ := c.categories.GetByID(ctx, id)
+	c.categoryNames[id] = name
+	return name
+}
+
+func (c *nameCache) brand(ctx context.Context, id uint) string {
+	if name, ok := c.brandNames[id]; ok {
+		return name
+	}
+	name, _ := c.brands.GetByID(ctx, id)
+	c.brandNames[id] = name
+	return name
+}
+
+// csvRowWriter adapts encoding/csv to rowWriter
+type csvRowWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvRowWriter) WriteRow(values []interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch n := v.(type) {
+		case float64:
+			record[i] = strconv.FormatFloat(n, 'f', -1, 64)
+		case nil:
+			record[i] = ""
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return c.w.Write(record)
+}
+
+func (c *csvRowWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func (c *csvRowWriter) Close() error {
+	return c.Flush()
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
+	"time"
 
+	"go-ecommerce/internal/modules/question"
 	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/pkg/money"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,24 +21,45 @@ type Handler struct {
 	service      Service
 	categoryRepo CategoryGetter
 	brandRepo    BrandGetter
+	importer     *Importer
+	exporter     *Exporter
+	questions    QuestionGetter
+	currencies   CurrencyConverter
 }
 
-// CategoryGetter interface for getting category names
+// CategoryGetter interface for getting category names and subtrees
 type CategoryGetter interface {
 	GetByID(ctx context.Context, id uint) (name string, err error)
+	GetDescendantIDs(ctx context.Context, id uint) ([]uint, error)
+	FindByNameOrSlug(ctx context.Context, key string) (id uint, name string, err error)
 }
 
 // BrandGetter interface for getting brand names
 type BrandGetter interface {
 	GetByID(ctx context.Context, id uint) (name string, err error)
+	FindByNameOrSlug(ctx context.Context, key string) (id uint, name string, err error)
+}
+
+// CurrencyConverter interface for showing storefront prices in another currency
+type CurrencyConverter interface {
+	Converter(ctx context.Context, code string) (func(money.Money) money.Money, error)
+}
+
+// QuestionGetter interface for answered questions shown on the product detail
+type QuestionGetter interface {
+	GetAnsweredByProduct(ctx context.Context, productID uint) ([]question.QuestionResponse, error)
 }
 
 // NewHandler creates a new product handler
-func NewHandler(service Service, categoryRepo CategoryGetter, brandRepo BrandGetter) *Handler {
+func NewHandler(service Service, categoryRepo CategoryGetter, brandRepo BrandGetter, importer *Importer, exporter *Exporter, questions QuestionGetter, currencies CurrencyConverter) *Handler {
 	return &Handler{
 		service:      service,
 		categoryRepo: categoryRepo,
 		brandRepo:    brandRepo,
+		importer:     importer,
+		exporter:     exporter,
+		questions:    questions,
+		currencies:   currencies,
 	}
 }
 
@@ -56,18 +81,31 @@ func (h *Handler) Create(c *gin.Context) {
 		return
 	}
 
+	// Get options JSON string from form (optional)
+	optionsJSON := c.PostForm("options")
+	if optionsJSON != "" {
+		var testOptions []OptionInput
+		if err := json.Unmarshal([]byte(optionsJSON), &testOptions); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid options JSON: %v", err)})
+			return
+		}
+	}
+
 	// Get variants JSON string from form
+	// Without options it is required; with options the variant matrix is generated when omitted
 	variantsJSON := c.PostForm("variants")
-	if variantsJSON == "" {
+	if variantsJSON == "" && optionsJSON == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "variants field is required"})
 		return
 	}
 
 	// Validate JSON format early
-	var testVariants []VariantInput
-	if err := json.Unmarshal([]byte(variantsJSON), &testVariants); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid variants JSON: %v", err)})
-		return
+	if variantsJSON != "" {
+		var testVariants []VariantInput
+		if err := json.Unmarshal([]byte(variantsJSON), &testVariants); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid variants JSON: %v", err)})
+			return
+		}
 	}
 
 	// Get image files
@@ -100,7 +138,11 @@ func (h *Handler) Create(c *gin.Context) {
 		return
 	}
 
-	res, err := h.service.Create(c.Request.Context(), req, variantsJSON, imageFiles, categoryName, brandName)
+	res, err := h.service.Create(c.Request.Context(), req, variantsJSON, optionsJSON, imageFiles, categoryName, brandName)
+	if err == errors.ErrTooManyVariants {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A product can have at most %d variants", maxVariants)})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create product: %v", err)})
 		return
@@ -113,8 +155,15 @@ func (h *Handler) Create(c *gin.Context) {
 }
 
 // GetAll handles GET /admin/products
+// Optional ?category_id= also includes products of all descendant categories,
+// optional ?status= filters by lifecycle status.
 func (h *Handler) GetAll(c *gin.Context) {
-	res, err := h.service.GetAll(c.Request.Context())
+	filter, ok := h.parseAdminFilter(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GetAll(c.Request.Context(), filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get products"})
 		return
@@ -191,5 +240,909 @@ func (h *Handler) Delete(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Xóa sản phẩm thành công"})
+	c.JSON(http.StatusOK, gin.H{"message": "Đã chuyển sản phẩm vào thùng rác"})
+}
+
+// GetTrash handles GET /admin/products/trash
+func (h *Handler) GetTrash(c *gin.Context) {
+	res, err := h.service.GetTrash(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get trash"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// Restore handles POST /admin/products/:id/restore
+// The product's category and brand must not be in trash.
+func (h *Handler) Restore(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	trashed, err := h.service.GetTrashedByID(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found in trash"})
+		return
+	}
+	if _, err := h.categoryRepo.GetByID(c.Request.Context(), trashed.CategoryID); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Category of this product is in trash, restore it first"})
+		return
+	}
+	if _, err := h.brandRepo.GetByID(c.Request.Context(), trashed.BrandID); err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Brand of this product is in trash, restore it first"})
+		return
+	}
+
+	res, err := h.service.Restore(c.Request.Context(), uint(id))
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found in trash"})
+		case errors.ErrRestoreExpired:
+			c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore product"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Khôi phục sản phẩm thành công",
+		"data":    res,
+	})
+}
+
+// Purge handles DELETE /admin/products/:id/purge
+// Permanently deletes a trashed product and its images on Cloudinary.
+func (h *Handler) Purge(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	err = h.service.Purge(c.Request.Context(), uint(id))
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found in trash"})
+		case errors.ErrVariantReserved:
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge product"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Xóa vĩnh viễn sản phẩm thành công"})
+}
+
+// GenerateVariants handles POST /admin/products/:id/variants/generate
+// Creates a variant for every option combination that does not exist yet.
+func (h *Handler) GenerateVariants(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req GenerateVariantsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	categoryName, ok := h.productCategoryName(c, uint(id))
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GenerateVariants(c.Request.Context(), uint(id), req, categoryName)
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		case errors.ErrProductHasNoOptions:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Product has no options"})
+		case errors.ErrTooManyVariants:
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A product can have at most %d variants", maxVariants)})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to generate variants: %v", err)})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tạo biến thể thành công",
+		"data":    res,
+	})
+}
+
+// AddVariant handles POST /admin/products/:id/variants
+func (h *Handler) AddVariant(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req VariantInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	categoryName, ok := h.productCategoryName(c, uint(id))
+	if !ok {
+		return
+	}
+
+	res, err := h.service.AddVariant(c.Request.Context(), uint(id), req, categoryName)
+	if err != nil {
+		h.respondManageError(c, err, "Failed to add variant")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Thêm biến thể thành công",
+		"data":    res,
+	})
+}
+
+// UpdateVariant handles PUT /admin/products/:id/variants/:variantId
+func (h *Handler) UpdateVariant(c *gin.Context) {
+	id, variantID, ok := parseChildIDs(c, "variantId")
+	if !ok {
+		return
+	}
+
+	var req UpdateVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.UpdateVariant(c.Request.Context(), id, variantID, req, c.GetString("userID"))
+	if err != nil {
+		h.respondManageError(c, err, "Failed to update variant")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cập nhật biến thể thành công",
+		"data":    res,
+	})
+}
+
+// SetSale handles PUT /admin/products/:id/variants/:variantId/sale
+func (h *Handler) SetSale(c *gin.Context) {
+	id, variantID, ok := parseChildIDs(c, "variantId")
+	if !ok {
+		return
+	}
+
+	var req SetSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.SetSale(c.Request.Context(), id, variantID, req, c.GetString("userID"))
+	if err != nil {
+		h.respondManageError(c, err, "Failed to set sale")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cập nhật giá khuyến mãi thành công",
+		"data":    res,
+	})
+}
+
+// SetBackorder handles PUT /admin/products/:id/variants/:variantId/backorder
+// @Summary Configure backorders or pre-orders
+// @Description Lets customers order up to limit units beyond stock; incoming stock goes to waiting orders first
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param variantId path int true "Variant ID"
+// @Param request body SetBackorderRequest true "Backorder settings"
+// @Success 200 {object} ProductResponse
+// @Router /admin/products/{id}/variants/{variantId}/backorder [put]
+func (h *Handler) SetBackorder(c *gin.Context) {
+	id, variantID, ok := parseChildIDs(c, "variantId")
+	if !ok {
+		return
+	}
+
+	var req SetBackorderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.SetBackorder(c.Request.Context(), id, variantID, req)
+	if err != nil {
+		h.respondManageError(c, err, "Failed to set backorder")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cập nhật đặt hàng trước thành công",
+		"data":    res,
+	})
+}
+
+// ClearSale handles DELETE /admin/products/:id/variants/:variantId/sale
+func (h *Handler) ClearSale(c *gin.Context) {
+	id, variantID, ok := parseChildIDs(c, "variantId")
+	if !ok {
+		return
+	}
+
+	res, err := h.service.ClearSale(c.Request.Context(), id, variantID, c.GetString("userID"))
+	if err != nil {
+		h.respondManageError(c, err, "Failed to clear sale")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Đã hủy giá khuyến mãi",
+		"data":    res,
+	})
+}
+
+// GetPriceHistory handles GET /admin/products/:id/variants/:variantId/price-history
+func (h *Handler) GetPriceHistory(c *gin.Context) {
+	id, variantID, ok := parseChildIDs(c, "variantId")
+	if !ok {
+		return
+	}
+
+	history, err := h.service.GetPriceHistory(c.Request.Context(), id, variantID)
+	if err != nil {
+		h.respondManageError(c, err, "Failed to get price history")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": history})
+}
+
+// MoveStock handles POST /admin/products/:id/variants/:variantId/stock-movements
+// @Summary Record a stock movement
+// @Description Receipt, return, damage or adjustment; the variant stock changes through the ledger
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param variantId path int true "Variant ID"
+// @Param request body StockMovementRequest true "Movement"
+// @Success 200 {object} ProductResponse
+// @Failure 409 {object} map[string]string
+// @Router /admin/products/{id}/variants/{variantId}/stock-movements [post]
+func (h *Handler) MoveStock(c *gin.Context) {
+	id, variantID, ok := parseChildIDs(c, "variantId")
+	if !ok {
+		return
+	}
+
+	var req StockMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.MoveStock(c.Request.Context(), id, variantID, req, c.GetString("userID"))
+	if err != nil {
+		h.respondManageError(c, err, "Failed to record stock movement")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Đã cập nhật tồn kho",
+		"data":    res,
+	})
+}
+
+// GetStockMovements handles GET /admin/products/:id/variants/:variantId/stock-movements
+func (h *Handler) GetStockMovements(c *gin.Context) {
+	id, variantID, ok := parseChildIDs(c, "variantId")
+	if !ok {
+		return
+	}
+
+	movements, err := h.service.GetStockMovements(c.Request.Context(), id, variantID)
+	if err != nil {
+		h.respondManageError(c, err, "Failed to get stock movements")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": movements})
+}
+
+// GetLowStock handles GET /admin/products/low-stock
+// @Summary Low-stock report
+// @Description Variants at or below their reorder threshold, emptiest first
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} LowStockItem
+// @Router /admin/products/low-stock [get]
+func (h *Handler) GetLowStock(c *gin.Context) {
+	items, err := h.service.GetLowStock(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get low-stock report"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": items})
+}
+
+// TransferStock handles POST /admin/products/:id/variants/:variantId/transfers
+// @Summary Transfer stock between warehouses
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Product ID"
+// @Param variantId path int true "Variant ID"
+// @Param request body TransferStockRequest true "Transfer"
+// @Success 200 {object} ProductResponse
+// @Failure 409 {object} map[string]string
+// @Router /admin/products/{id}/variants/{variantId}/transfers [post]
+func (h *Handler) TransferStock(c *gin.Context) {
+	id, variantID, ok := parseChildIDs(c, "variantId")
+	if !ok {
+		return
+	}
+
+	var req TransferStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.TransferStock(c.Request.Context(), id, variantID, req, c.GetString("userID"))
+	if err != nil {
+		h.respondManageError(c, err, "Failed to transfer stock")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Đã chuyển kho",
+		"data":    res,
+	})
+}
+
+// GetStockLevels handles GET /admin/products/:id/variants/:variantId/stock
+func (h *Handler) GetStockLevels(c *gin.Context) {
+	id, variantID, ok := parseChildIDs(c, "variantId")
+	if !ok {
+		return
+	}
+
+	levels, err := h.service.GetStockLevels(c.Request.Context(), id, variantID)
+	if err != nil {
+		h.respondManageError(c, err, "Failed to get stock levels")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": levels})
+}
+
+// DeleteVariant handles DELETE /admin/products/:id/variants/:variantId
+func (h *Handler) DeleteVariant(c *gin.Context) {
+	id, variantID, ok := parseChildIDs(c, "variantId")
+	if !ok {
+		return
+	}
+
+	res, err := h.service.DeleteVariant(c.Request.Context(), id, variantID, c.GetString("userID"))
+	if err != nil {
+		h.respondManageError(c, err, "Failed to delete variant")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Xóa biến thể thành công",
+		"data":    res,
+	})
+}
+
+// AddImages handles POST /admin/products/:id/images (multipart/form-data, field "images")
+func (h *Handler) AddImages(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to parse multipart form"})
+		return
+	}
+
+	imageFiles := form.File["images"]
+	if len(imageFiles) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "At least 1 image is required"})
+		return
+	}
+
+	res, err := h.service.AddImages(c.Request.Context(), uint(id), imageFiles)
+	if err != nil {
+		h.respondManageError(c, err, "Failed to add images")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Thêm ảnh thành công",
+		"data":    res,
+	})
+}
+
+// ReplaceImage handles PUT /admin/products/:id/images/:imageId (multipart/form-data, field "image")
+func (h *Handler) ReplaceImage(c *gin.Context) {
+	id, imageID, ok := parseChildIDs(c, "imageId")
+	if !ok {
+		return
+	}
+
+	imageFile, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "image file is required"})
+		return
+	}
+
+	res, err := h.service.ReplaceImage(c.Request.Context(), id, imageID, imageFile)
+	if err != nil {
+		h.respondManageError(c, err, "Failed to replace image")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Thay ảnh thành công",
+		"data":    res,
+	})
+}
+
+// DeleteImage handles DELETE /admin/products/:id/images/:imageId
+func (h *Handler) DeleteImage(c *gin.Context) {
+	id, imageID, ok := parseChildIDs(c, "imageId")
+	if !ok {
+		return
+	}
+
+	res, err := h.service.DeleteImage(c.Request.Context(), id, imageID)
+	if err != nil {
+		h.respondManageError(c, err, "Failed to delete image")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Xóa ảnh thành công",
+		"data":    res,
+	})
+}
+
+// ReorderImages handles PUT /admin/products/:id/images/order
+func (h *Handler) ReorderImages(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req ReorderImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.ReorderImages(c.Request.Context(), uint(id), req)
+	if err != nil {
+		h.respondManageError(c, err, "Failed to reorder images")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Sắp xếp ảnh thành công",
+		"data":    res,
+	})
+}
+
+// productCategoryName looks up the category name of a product (needed for SKUs)
+func (h *Handler) productCategoryName(c *gin.Context, id uint) (string, bool) {
+	product, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return "", false
+	}
+
+	categoryName, err := h.categoryRepo.GetByID(c.Request.Context(), product.CategoryID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category_id"})
+		return "", false
+	}
+	return categoryName, true
+}
+
+// respondManageError maps variant/image management errors to HTTP responses
+func (h *Handler) respondManageError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case errors.ErrRecordNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product, variant or image not found"})
+	case errors.ErrInvalidVariant:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Variant must specify size or a value for each option"})
+	case errors.ErrTooManyVariants:
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A product can have at most %d variants", maxVariants)})
+	case errors.ErrDuplicateVariant, errors.ErrLastVariant, errors.ErrLastImage, errors.ErrTooManyImages, errors.ErrVariantReserved:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.ErrInvalidImageOrder, errors.ErrInvalidPrice, errors.ErrInvalidSalePeriod, errors.ErrInvalidStockMovement,
+		errors.ErrInvalidTransfer, errors.ErrUnknownWarehouse, errors.ErrInvalidBackorder:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.ErrInsufficientStock:
+		c.JSON(http.StatusConflict, gin.H{"error": "Stock cannot go below 0"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("%s: %v", fallback, err)})
+	}
+}
+
+// parseChildIDs parses :id and a nested resource ID such as :variantId
+func parseChildIDs(c *gin.Context, childParam string) (uint, uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, 0, false
+	}
+	childID, err := strconv.ParseUint(c.Param(childParam), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return 0, 0, false
+	}
+	return uint(id), uint(childID), true
+}
+
+// CheckReadiness handles GET /admin/products/:id/readiness
+func (h *Handler) CheckReadiness(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	res, err := h.service.CheckReadiness(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// Publish handles POST /admin/products/:id/publish
+func (h *Handler) Publish(c *gin.Context) {
+	h.changeStatus(c, h.service.Publish, "Đăng bán sản phẩm thành công")
+}
+
+// Unpublish handles POST /admin/products/:id/unpublish
+func (h *Handler) Unpublish(c *gin.Context) {
+	h.changeStatus(c, h.service.Unpublish, "Ngừng đăng bán sản phẩm thành công")
+}
+
+// Archive handles POST /admin/products/:id/archive
+func (h *Handler) Archive(c *gin.Context) {
+	h.changeStatus(c, h.service.Archive, "Lưu trữ sản phẩm thành công")
+}
+
+// Schedule handles PUT /admin/products/:id/schedule
+func (h *Handler) Schedule(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req ScheduleProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Schedule(c.Request.Context(), uint(id), req)
+	if err != nil {
+		h.respondLifecycleError(c, uint(id), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Lên lịch đăng bán thành công",
+		"data":    res,
+	})
+}
+
+// ListPublished handles GET /products (storefront)
+func (h *Handler) ListPublished(c *gin.Context) {
+	filter, ok := h.parseFilter(c)
+	if !ok {
+		return
+	}
+
+	convert, ok := h.priceConverter(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GetPublished(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get products"})
+		return
+	}
+	for i := range res {
+		res[i].ConvertPrices(convert)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// GetBySlug handles GET /products/:slug (storefront)
+// Slugs of renamed products answer with 301 and the current slug.
+func (h *Handler) GetBySlug(c *gin.Context) {
+	convert, ok := h.priceConverter(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GetPublishedBySlug(c.Request.Context(), c.Param("slug"))
+	if err != nil {
+		newSlug, resolveErr := h.service.ResolveOldSlug(c.Request.Context(), c.Param("slug"))
+		if resolveErr != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+			return
+		}
+
+		c.Header("Location", "/api/v1/products/"+newSlug)
+		c.JSON(http.StatusMovedPermanently, gin.H{"redirect_to": newSlug})
+		return
+	}
+
+	res.ConvertPrices(convert)
+	detail := ProductDetailResponse{ProductResponse: res, Questions: []question.QuestionResponse{}}
+	if h.questions != nil {
+		questions, err := h.questions.GetAnsweredByProduct(c.Request.Context(), res.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get product"})
+			return
+		}
+		detail.Questions = questions
+	}
+	c.JSON(http.StatusOK, gin.H{"data": detail})
+}
+
+// priceConverter picks the display currency from ?currency= or the Accept-Currency header
+// Prices stay in the base currency when neither is given.
+func (h *Handler) priceConverter(c *gin.Context) (func(money.Money) money.Money, bool) {
+	c.Header("Vary", "Accept-Currency")
+
+	code := c.Query("currency")
+	if code == "" {
+		code = c.GetHeader("Accept-Currency")
+	}
+	if code == "" || h.currencies == nil {
+		return func(m money.Money) money.Money { return m }, true
+	}
+
+	convert, err := h.currencies.Converter(c.Request.Context(), code)
+	if err != nil {
+		if err == errors.ErrUnsupportedCurrency {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported currency"})
+			return nil, false
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load exchange rate"})
+		return nil, false
+	}
+	return convert, true
+}
+
+// Import handles POST /admin/products/import
+// @Summary Nhập sản phẩm hàng loạt
+// @Description Nhập sản phẩm từ file CSV/XLSX (mỗi dòng một biến thể). Toàn bộ file được kiểm tra trước;
+// @Description dry_run=true chỉ trả về báo cáo, ngược lại tạo job chạy nền.
+// @Tags Products
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "File .csv hoặc .xlsx"
+// @Param dry_run query bool false "Chỉ kiểm tra, không tạo sản phẩm"
+// @Success 202 {object} ImportJob
+// @Success 200 {object} ImportReport
+// @Failure 422 {object} ImportReport
+// @Router /admin/products/import [post]
+func (h *Handler) Import(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+
+	dryRun := false
+	if v := c.DefaultQuery("dry_run", c.PostForm("dry_run")); v != "" {
+		if dryRun, err = strconv.ParseBool(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dry_run value"})
+			return
+		}
+	}
+
+	rows, err := ReadImportFile(fileHeader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	products, report := h.importer.Validate(c.Request.Context(), rows)
+	report.DryRun = dryRun
+	if !report.Valid {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Import file has errors",
+			"data":  report,
+		})
+		return
+	}
+	if dryRun {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "File hợp lệ",
+			"data":    report,
+		})
+		return
+	}
+
+	userID, _ := c.Get("userID")
+	userIDStr, _ := userID.(string)
+	job, err := h.importer.Start(c.Request.Context(), fileHeader.Filename, userIDStr, products, report.TotalRows)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start import"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "Đã bắt đầu nhập sản phẩm",
+		"data":    job,
+	})
+}
+
+// GetImportJob handles GET /admin/products/import/:jobId
+// @Summary Tiến độ nhập sản phẩm
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Param jobId path int true "Import job ID"
+// @Success 200 {object} ImportJob
+// @Router /admin/products/import/{jobId} [get]
+func (h *Handler) GetImportJob(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("jobId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.importer.GetJob(c.Request.Context(), uint(jobID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Import job not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": job})
+}
+
+// Export handles GET /admin/products/export
+// @Summary Xuất danh mục sản phẩm
+// @Description Xuất sản phẩm (biến thể, SKU, giá, tồn kho, danh mục, thương hiệu, ảnh) dạng CSV, XLSX hoặc JSON Lines.
+// @Description Dùng chung bộ lọc với danh sách sản phẩm.
+// @Tags Products
+// @Produce text/csv
+// @Security BearerAuth
+// @Param format query string false "csv (mặc định), xlsx hoặc jsonl"
+// @Param category_id query int false "Lọc theo danh mục (gồm danh mục con)"
+// @Param status query string false "Lọc theo trạng thái"
+// @Router /admin/products/export [get]
+func (h *Handler) Export(c *gin.Context) {
+	format := ExportFormat(c.DefaultQuery("format", string(ExportCSV)))
+	if !format.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, use csv, xlsx or jsonl"})
+		return
+	}
+
+	filter, ok := h.parseAdminFilter(c)
+	if !ok {
+		return
+	}
+
+	fileName := fmt.Sprintf("products-%s.%s", time.Now().Format("20060102-150405"), format)
+	c.Header("Content-Type", format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	c.Status(http.StatusOK)
+
+	// Headers are already sent, so a failure can only cut the stream short
+	err := h.exporter.Export(c.Request.Context(), c.Writer, format, filter, c.Writer.Flush)
+	if err != nil {
+		log.Printf("Export products failed: %v", err)
+	}
+}
+
+// changeStatus runs a status transition for :id and writes the response
+func (h *Handler) changeStatus(c *gin.Context, transition func(context.Context, uint) (*ProductResponse, error), message string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	res, err := transition(c.Request.Context(), uint(id))
+	if err != nil {
+		h.respondLifecycleError(c, uint(id), err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    res,
+	})
+}
+
+// respondLifecycleError maps lifecycle errors, listing readiness issues when relevant
+func (h *Handler) respondLifecycleError(c *gin.Context, id uint, err error) {
+	switch err {
+	case errors.ErrRecordNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Product not found"})
+	case errors.ErrProductNotReady:
+		readiness, _ := h.service.CheckReadiness(c.Request.Context(), id)
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "data": readiness})
+	case errors.ErrInvalidSchedule:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "publish_at and unpublish_at must be in the future, unpublish_at after publish_at"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update product status"})
+	}
+}
+
+// parseFilter reads listing filters shared by admin and storefront listings
+func (h *Handler) parseFilter(c *gin.Context) (ProductFilter, bool) {
+	var filter ProductFilter
+
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		id, err := strconv.ParseUint(categoryID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category_id"})
+			return filter, false
+		}
+		ids, err := h.categoryRepo.GetDescendantIDs(c.Request.Context(), uint(id))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid category_id"})
+			return filter, false
+		}
+		filter.CategoryIDs = ids
+	}
+
+	return filter, true
+}
+
+// parseAdminFilter adds ?status to parseFilter; admins may see every status
+func (h *Handler) parseAdminFilter(c *gin.Context) (ProductFilter, bool) {
+	filter, ok := h.parseFilter(c)
+	if !ok {
+		return filter, false
+	}
+
+	if status := c.Query("status"); status != "" {
+		switch ProductStatus(status) {
+		case StatusDraft, StatusScheduled, StatusPublished, StatusArchived:
+			filter.Statuses = []ProductStatus{ProductStatus(status)}
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+			return filter, false
+		}
+	}
+	return filter, true
 }
@@ -2,31 +2,19 @@ package product
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode"
 
-	"golang.org/x/text/transform"
-	"golang.org/x/text/unicode/norm"
+	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/internal/shared/slug"
 )
 
-// removeAccents removes Vietnamese diacritics
-// "Áo thun" -> "Ao thun"
-func removeAccents(s string) string {
-	t := transform.Chain(norm.NFD, transform.RemoveFunc(func(r rune) bool {
-		return unicode.Is(unicode.Mn, r) // Mn: Mark, nonspacing
-	}), norm.NFC)
-	result, _, _ := transform.String(t, s)
-
-	// Handle Đ/đ specifically
-	result = strings.ReplaceAll(result, "đ", "d")
-	result = strings.ReplaceAll(result, "Đ", "D")
-	return result
-}
-
 // generatePrefix generates prefix from text
 // "Áo thun trắng" -> "Ao thun trang" -> "ATT"
 func generatePrefix(text string) string {
-	text = removeAccents(text)
+	text = slug.RemoveAccents(text)
 	words := strings.Fields(text)
 	var prefix string
 	for _, word := range words {
@@ -38,27 +26,149 @@ func generatePrefix(text string) string {
 	return prefix
 }
 
-// generateSKU creates SKU from category, product names and variant ID
+// generateSKU creates SKU from category, product names, variant ID and option codes
 // Example: "Áo thun" + "Áo thun trắng" + 1 -> "ATATT1"
-func generateSKU(categoryName, productName string, variantID uint) string {
+// Example: "Áo thun" + "Áo thun trắng" + 1 + "DO", "M" -> "ATATT1-DO-M"
+func generateSKU(categoryName, productName string, variantID uint, optionCodes ...string) string {
 	catPrefix := generatePrefix(categoryName)
 	prodPrefix := generatePrefix(productName)
-	return fmt.Sprintf("%s%s%d", catPrefix, prodPrefix, variantID)
+	sku := fmt.Sprintf("%s%s%d", catPrefix, prodPrefix, variantID)
+	for _, code := range optionCodes {
+		sku += "-" + code
+	}
+	return sku
+}
+
+// generateOptionCode creates a short code for an option value
+// "Đỏ" -> "DO", "XL" -> "XL", "Xanh dương" -> "XD"
+func generateOptionCode(value string) string {
+	if len(strings.Fields(value)) > 1 {
+		return generatePrefix(value)
+	}
+
+	var code strings.Builder
+	for _, r := range strings.ToUpper(slug.RemoveAccents(value)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			code.WriteRune(r)
+		}
+	}
+	runes := []rune(code.String())
+	if len(runes) > 3 {
+		runes = runes[:3]
+	}
+	return string(runes)
+}
+
+// uniqueOptionCodes assigns codes to option values, suffixing duplicates
+// "Xanh lá", "Xanh lơ" -> "XL", "XL2"
+func uniqueOptionCodes(values []string) []string {
+	codes := make([]string, len(values))
+	seen := make(map[string]int)
+	for i, v := range values {
+		code := generateOptionCode(v)
+		seen[code]++
+		if seen[code] > 1 {
+			code += strconv.Itoa(seen[code])
+		}
+		codes[i] = code
+	}
+	return codes
+}
+
+// maxVariants caps the variants of one product, including a generated option matrix
+const maxVariants = 100
+
+// matrixSize counts the option combinations without generating them
+// Counting stops once it passes maxVariants, so huge inputs cannot overflow.
+func matrixSize(valueCounts ...int) int {
+	if len(valueCounts) == 0 {
+		return 0
+	}
+	size := 1
+	for _, n := range valueCounts {
+		size *= n
+		if size > maxVariants {
+			return maxVariants + 1
+		}
+	}
+	return size
+}
+
+// validateVariantCount checks the variants a new product would get:
+// the listed ones, or the full option matrix when none are listed
+func validateVariantCount(options []OptionInput, variants []VariantInput) error {
+	count := len(variants)
+	if count == 0 {
+		counts := make([]int, len(options))
+		for i, opt := range options {
+			counts[i] = len(opt.Values)
+		}
+		count = matrixSize(counts...)
+	}
+	if count > maxVariants {
+		return errors.ErrTooManyVariants
+	}
+	return nil
 }
 
-// generateSlug creates URL-friendly slug from name
-func generateSlug(name string) string {
-	slug := removeAccents(name)
-	slug = strings.ToLower(strings.TrimSpace(slug))
-	slug = strings.ReplaceAll(slug, " ", "-")
-	// Remove non-alphanumeric except hyphens
-	var result strings.Builder
-	for _, r := range slug {
-		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' {
-			result.WriteRune(r)
+// generateVariantMatrix returns every combination of option values
+// [Màu: Đỏ, Xanh] x [Size: M, L] -> [Đỏ M], [Đỏ L], [Xanh M], [Xanh L]
+func generateVariantMatrix(options []ProductOption) [][]ProductOptionValue {
+	if len(options) == 0 {
+		return nil
+	}
+
+	combinations := [][]ProductOptionValue{{}}
+	for _, opt := range options {
+		var next [][]ProductOptionValue
+		for _, combo := range combinations {
+			for _, val := range opt.Values {
+				row := make([]ProductOptionValue, len(combo), len(combo)+1)
+				copy(row, combo)
+				next = append(next, append(row, val))
+			}
 		}
+		combinations = next
+	}
+	return combinations
+}
+
+// optionKey builds an order-independent key for a combination of option values
+func optionKey(values []ProductOptionValue) string {
+	ids := make([]int, len(values))
+	for i, v := range values {
+		ids[i] = int(v.ID)
+	}
+	sort.Ints(ids)
+
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.Itoa(id)
 	}
-	return result.String()
+	return strings.Join(parts, "-")
+}
+
+// variantLabel builds a display label from option values, stored in Size
+// [Đỏ, M] -> "Đỏ / M"
+func variantLabel(values []ProductOptionValue) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = v.Value
+	}
+	label := []rune(strings.Join(parts, " / "))
+	if len(label) > 50 {
+		label = label[:50]
+	}
+	return string(label)
+}
+
+// optionCodes returns the SKU codes of option values in option order
+func optionCodes(values []ProductOptionValue) []string {
+	codes := make([]string, len(values))
+	for i, v := range values {
+		codes[i] = v.Code
+	}
+	return codes
 }
 
 // calculateTotalStock sums stock from all variants
@@ -69,3 +179,24 @@ func calculateTotalStock(variants []ProductVariant) int {
 	}
 	return total
 }
+
+// publishReadinessIssues lists what prevents a product from being published
+// A product needs at least one image and at least one variant with stock.
+func publishReadinessIssues(p *Product) []string {
+	issues := []string{}
+	if len(p.Images) == 0 {
+		issues = append(issues, "product has no images")
+	}
+
+	hasStock := false
+	for _, v := range p.Variants {
+		if v.Stock > 0 || v.BackorderRoom() > 0 {
+			hasStock = true
+			break
+		}
+	}
+	if !hasStock {
+		issues = append(issues, "product has no variant in stock or open for backorder")
+	}
+	return issues
+}
@@ -0,0 +1,538 @@
+package product
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"net/url"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-ecommerce/pkg/money"
+	"go-ecommerce/pkg/xlsx"
+)
+
+// Import file layout: one row per variant, rows with the same name form one product.
+// Product columns (description, category, brand, images) may be repeated on every row
+// or given on the first row only. Options look like "Màu sắc=Đỏ; Kích thước=M",
+// images are URLs separated by "|".
+const (
+	colName        = "name"
+	colDescription = "description"
+	colCategory    = "category"
+	colBrand       = "brand"
+	colPrice       = "price"
+	colStock       = "stock"
+	colSize        = "size"
+	colOptions     = "options"
+	colImages      = "images"
+
+	maxImportRows = 5000
+	maxImages     = 5
+)
+
+var importColumns = []string{colName, colDescription, colCategory, colBrand, colPrice, colStock, colSize, colOptions, colImages}
+var requiredImportColumns = []string{colName, colCategory, colBrand, colPrice, colStock}
+
+// Importer validates import files and creates their products in the background
+type Importer struct {
+	service    Service
+	repo       Repository
+	categories CategoryGetter
+	brands     BrandGetter
+}
+
+// NewImporter creates a new product importer
+func NewImporter(service Service, repo Repository, categories CategoryGetter, brands BrandGetter) *Importer {
+	return &Importer{service: service, repo: repo, categories: categories, brands: brands}
+}
+
+// ReadImportFile reads all rows of a .csv or .xlsx upload, header included
+func ReadImportFile(fileHeader *multipart.FileHeader) ([][]string, error) {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+	case ".csv":
+		reader := csv.NewReader(file)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		// Strip the UTF-8 BOM Excel adds when saving CSV
+		if len(rows) > 0 && len(rows[0]) > 0 {
+			rows[0][0] = strings.TrimPrefix(rows[0][0], "\ufeff")
+		}
+		return rows, nil
+	case ".xlsx":
+		return xlsx.ReadFirstSheet(file, fileHeader.Size, maxImportRows+1) // Header and data rows
+	default:
+		return nil, fmt.Errorf("unsupported file type, use .csv or .xlsx")
+	}
+}
+
+// importRow is one parsed data row
+type importRow struct {
+	num     int
+	values  map[string]string
+	price   money.Money
+	stock   int
+	options map[string]string
+	names   []string // Option names in column order
+}
+
+// importGroup collects the rows of one product
+type importGroup struct {
+	rows        []*importRow
+	description string
+	category    string
+	brand       string
+	images      []string
+	imagesRaw   string
+}
+
+// Validate checks every row up front and assembles the products to create
+// The report lists every problem found; products are only usable when it is valid.
+func (i *Importer) Validate(ctx context.Context, rows [][]string) ([]ImportProduct, *ImportReport) {
+	report := &ImportReport{Errors: []ImportRowError{}}
+	addErr := func(row int, column, format string, args ...interface{}) {
+		report.Errors = append(report.Errors, ImportRowError{Row: row, Column: column, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(rows) == 0 {
+		addErr(1, "", "file is empty")
+		return nil, report
+	}
+
+	// Header
+	columns := make(map[string]int)
+	for idx, h := range rows[0] {
+		name := strings.ToLower(strings.TrimSpace(h))
+		if name == "" {
+			continue
+		}
+		if !isImportColumn(name) {
+			addErr(1, h, "unknown column, expected one of: %s", strings.Join(importColumns, ", "))
+			continue
+		}
+		if _, dup := columns[name]; dup {
+			addErr(1, h, "duplicate column")
+			continue
+		}
+		columns[name] = idx
+	}
+	for _, name := range requiredImportColumns {
+		if _, ok := columns[name]; !ok {
+			addErr(1, name, "missing required column")
+		}
+	}
+	if len(report.Errors) > 0 {
+		return nil, report
+	}
+
+	// Data rows, grouped by product name in file order
+	groups := make(map[string]*importGroup)
+	var order []string
+	for idx, raw := range rows[1:] {
+		num := idx + 2
+		if isBlankRow(raw) {
+			continue
+		}
+		report.TotalRows++
+		if report.TotalRows > maxImportRows {
+			addErr(num, "", "too many rows, maximum is %d", maxImportRows)
+			break
+		}
+
+		row := &importRow{num: num, values: make(map[string]string)}
+		for name, col := range columns {
+			if col < len(raw) {
+				row.values[name] = strings.TrimSpace(raw[col])
+			}
+		}
+
+		name := row.values[colName]
+		if name == "" {
+			addErr(num, colName, "name is required")
+			continue
+		}
+		if n := len([]rune(name)); n < 2 || n > 255 {
+			addErr(num, colName, "name must be 2-255 characters")
+		}
+
+		if price, err := money.Parse(row.values[colPrice], money.Default); err != nil || price.IsNegative() {
+			addErr(num, colPrice, "price must be a whole number of đồng >= 0")
+		} else {
+			row.price = price
+		}
+		if stock, err := strconv.Atoi(row.values[colStock]); err != nil || stock < 0 {
+			addErr(num, colStock, "stock must be a whole number >= 0")
+		} else {
+			row.stock = stock
+		}
+
+		if raw := row.values[colOptions]; raw != "" {
+			options, names, err := parseImportOptions(raw)
+			if err != nil {
+				addErr(num, colOptions, "%v", err)
+			}
+			row.options, row.names = options, names
+		} else if row.values[colSize] == "" {
+			addErr(num, colSize, "size or options is required")
+		} else if len([]rune(row.values[colSize])) > 50 {
+			addErr(num, colSize, "size must be at most 50 characters")
+		}
+
+		key := strings.ToLower(name)
+		group, ok := groups[key]
+		if !ok {
+			group = &importGroup{}
+			groups[key] = group
+			order = append(order, key)
+		}
+		group.rows = append(group.rows, row)
+
+		// Product columns: first value wins, later rows must agree
+		mergeProductField(&group.description, row.values[colDescription], num, colDescription, addErr)
+		mergeProductField(&group.category, row.values[colCategory], num, colCategory, addErr)
+		mergeProductField(&group.brand, row.values[colBrand], num, colBrand, addErr)
+		if raw := row.values[colImages]; raw != "" {
+			if group.imagesRaw == "" {
+				images, err := parseImportImages(raw)
+				if err != nil {
+					addErr(num, colImages, "%v", err)
+				}
+				group.imagesRaw, group.images = raw, images
+			} else if raw != group.imagesRaw {
+				addErr(num, colImages, "conflicts with images given on an earlier row of this product")
+			}
+		}
+	}
+
+	// Categories and brands are looked up once per distinct value
+	categoryCache := make(map[string]resolvedRef)
+	brandCache := make(map[string]resolvedRef)
+
+	var products []ImportProduct
+	for _, key := range order {
+		group := groups[key]
+		first := group.rows[0]
+
+		product := ImportProduct{
+			Row: first.num,
+			Request: CreateProductRequest{
+				Name:        first.values[colName],
+				Description: group.description,
+			},
+			ImageURLs: group.images,
+		}
+
+		if group.category == "" {
+			addErr(first.num, colCategory, "category is required")
+		} else if ref := resolveRef(ctx, categoryCache, group.category, i.categories.FindByNameOrSlug); ref.err != nil {
+			addErr(first.num, colCategory, "%v", ref.err)
+		} else {
+			product.Request.CategoryID = ref.id
+			product.CategoryName = ref.name
+		}
+
+		if group.brand == "" {
+			addErr(first.num, colBrand, "brand is required")
+		} else if ref := resolveRef(ctx, brandCache, group.brand, i.brands.FindByNameOrSlug); ref.err != nil {
+			addErr(first.num, colBrand, "%v", ref.err)
+		} else {
+			product.Request.BrandID = ref.id
+		}
+
+		if len(group.images) == 0 && group.imagesRaw == "" {
+			addErr(first.num, colImages, "at least 1 image is required")
+		}
+
+		product.Options, product.Variants = buildImportVariants(group, addErr)
+		if len(product.Variants) > maxVariants {
+			addErr(first.num, colName, "a product can have at most %d variants", maxVariants)
+		}
+		products = append(products, product)
+	}
+
+	sort.SliceStable(report.Errors, func(a, b int) bool { return report.Errors[a].Row < report.Errors[b].Row })
+	report.TotalProducts = len(products)
+	report.Valid = len(report.Errors) == 0
+	for _, p := range products {
+		preview := ImportPreview{
+			Row:      p.Row,
+			Name:     p.Request.Name,
+			Category: p.CategoryName,
+			Variants: len(p.Variants),
+			Images:   len(p.ImageURLs),
+		}
+		for _, opt := range p.Options {
+			preview.Options = append(preview.Options, opt.Name)
+		}
+		report.Products = append(report.Products, preview)
+	}
+	return products, report
+}
+
+// Start records an import job and creates its products in the background
+func (i *Importer) Start(ctx context.Context, fileName, userID string, products []ImportProduct, totalRows int) (*ImportJob, error) {
+	job := &ImportJob{
+		FileName:      fileName,
+		Status:        ImportPending,
+		TotalRows:     totalRows,
+		TotalProducts: len(products),
+		Errors:        []ImportRowError{},
+		ProductIDs:    []uint{},
+		CreatedBy:     userID,
+	}
+	if err := i.repo.CreateImportJob(ctx, job); err != nil {
+		return nil, err
+	}
+
+	// The request context ends with the response; the job must outlive it
+	jobCopy := *job
+	go i.run(context.Background(), &jobCopy, products)
+
+	return job, nil
+}
+
+// GetJob returns an import job for progress polling
+func (i *Importer) GetJob(ctx context.Context, id uint) (*ImportJob, error) {
+	return i.repo.GetImportJob(ctx, id)
+}
+
+// FailInterrupted marks jobs left unfinished by a previous run as failed
+func (i *Importer) FailInterrupted(ctx context.Context) error {
+	return i.repo.FailUnfinishedImportJobs(ctx)
+}
+
+func (i *Importer) run(ctx context.Context, job *ImportJob, products []ImportProduct) {
+	// Nothing recovers panics outside a request; one here would take the API down
+	row := 0
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Import: job %d panicked at row %d: %v\n%s", job.ID, row, r, debug.Stack())
+			now := time.Now()
+			job.Status = ImportFailed
+			job.FinishedAt = &now
+			job.Errors = append(job.Errors, ImportRowError{Row: row, Message: "import stopped by an internal error"})
+			i.save(ctx, job)
+		}
+	}()
+
+	job.Status = ImportRunning
+	i.save(ctx, job)
+
+	// Products are independent: one failure (e.g. an unreachable image URL) does not stop the rest
+	for _, p := range products {
+		row = p.Row
+		res, err := i.service.CreateImported(ctx, p)
+		if err != nil {
+			job.Failed++
+			job.Errors = append(job.Errors, ImportRowError{
+				Row:     p.Row,
+				Message: fmt.Sprintf("%s: %v", p.Request.Name, err),
+			})
+		} else {
+			job.Created++
+			job.ProductIDs = append(job.ProductIDs, res.ID)
+		}
+		job.Processed++
+		i.save(ctx, job)
+	}
+
+	now := time.Now()
+	job.Status = ImportCompleted
+	job.FinishedAt = &now
+	i.save(ctx, job)
+}
+
+func (i *Importer) save(ctx context.Context, job *ImportJob) {
+	if err := i.repo.SaveImportJob(ctx, job); err != nil {
+		log.Printf("Import: save job %d failed: %v", job.ID, err)
+	}
+}
+
+// buildImportVariants derives option definitions and variants from a product's rows
+// Rows of one product must either all use options (with the same names) or all use size.
+func buildImportVariants(group *importGroup, addErr func(int, string, string, ...interface{})) ([]OptionInput, []VariantInput) {
+	first := group.rows[0]
+
+	var options []OptionInput
+	for _, name := range first.names {
+		options = append(options, OptionInput{Name: name})
+	}
+
+	var variants []VariantInput
+	seen := make(map[string]int)
+	for _, row := range group.rows {
+		if (len(row.options) > 0) != (len(options) > 0) {
+			addErr(row.num, colOptions, "all rows of a product must either use options or not")
+			continue
+		}
+
+		key := strings.ToLower(row.values[colSize])
+		if len(options) > 0 {
+			if len(row.options) != len(options) {
+				addErr(row.num, colOptions, "must give the same options as row %d", first.num)
+				continue
+			}
+
+			var parts []string
+			valid := true
+			for idx := range options {
+				value, ok := lookupOption(row.options, options[idx].Name)
+				if !ok {
+					addErr(row.num, colOptions, "missing option %s (given on row %d)", options[idx].Name, first.num)
+					valid = false
+					break
+				}
+				if !containsFold(options[idx].Values, value) {
+					options[idx].Values = append(options[idx].Values, value)
+				}
+				parts = append(parts, strings.ToLower(value))
+			}
+			if !valid {
+				continue
+			}
+			key = strings.Join(parts, "|")
+		}
+
+		if prev, dup := seen[key]; dup {
+			addErr(row.num, "", "duplicate variant of row %d", prev)
+			continue
+		}
+		seen[key] = row.num
+
+		variants = append(variants, VariantInput{
+			Price:   row.price,
+			Stock:   row.stock,
+			Size:    row.values[colSize],
+			Options: row.options,
+		})
+	}
+	return options, variants
+}
+
+// parseImportOptions parses "Màu sắc=Đỏ; Kích thước=M"
+func parseImportOptions(raw string) (map[string]string, []string, error) {
+	options := make(map[string]string)
+	var names []string
+	for _, part := range strings.Split(raw, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" || value == "" {
+			return nil, nil, fmt.Errorf("invalid option %q, expected Name=Value", part)
+		}
+		if _, dup := lookupOption(options, name); dup {
+			return nil, nil, fmt.Errorf("duplicate option %s", name)
+		}
+		options[name] = value
+		names = append(names, name)
+	}
+	if len(options) == 0 {
+		return nil, nil, fmt.Errorf("invalid options, expected Name=Value; Name=Value")
+	}
+	return options, names, nil
+}
+
+// parseImportImages parses "https://a.jpg | https://b.jpg"
+func parseImportImages(raw string) ([]string, error) {
+	var images []string
+	for _, part := range strings.Split(raw, "|") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		u, err := url.Parse(part)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, fmt.Errorf("invalid image URL %q", part)
+		}
+		images = append(images, part)
+	}
+	if len(images) > maxImages {
+		return nil, fmt.Errorf("maximum %d images allowed", maxImages)
+	}
+	return images, nil
+}
+
+type resolvedRef struct {
+	id   uint
+	name string
+	err  error
+}
+
+func resolveRef(ctx context.Context, cache map[string]resolvedRef, key string, find func(context.Context, string) (uint, string, error)) resolvedRef {
+	cacheKey := strings.ToLower(key)
+	if ref, ok := cache[cacheKey]; ok {
+		return ref
+	}
+	id, name, err := find(ctx, key)
+	ref := resolvedRef{id: id, name: name, err: err}
+	cache[cacheKey] = ref
+	return ref
+}
+
+func mergeProductField(current *string, value string, row int, column string, addErr func(int, string, string, ...interface{})) {
+	if value == "" {
+		return
+	}
+	if *current == "" {
+		*current = value
+		return
+	}
+	if *current != value {
+		addErr(row, column, "conflicts with %q given on an earlier row of this product", *current)
+	}
+}
+
+func lookupOption(options map[string]string, name string) (string, bool) {
+	for n, v := range options {
+		if strings.EqualFold(n, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func isImportColumn(name string) bool {
+	for _, c := range importColumns {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isBlankRow(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,82 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// importService creates products, panicking on the row named "boom"
+type importService struct {
+	Service
+	created int
+}
+
+func (s *importService) CreateImported(ctx context.Context, input ImportProduct) (*ProductResponse, error) {
+	if input.Request.Name == "boom" {
+		var p *Product
+		_ = p.Name // nil dereference
+	}
+	if input.Request.Name == "bad image" {
+		return nil, fmt.Errorf("image unreachable")
+	}
+	s.created++
+	return &ProductResponse{ID: uint(s.created)}, nil
+}
+
+// jobRepo keeps the last saved state of the job
+type jobRepo struct {
+	Repository
+	saved ImportJob
+}
+
+func (r *jobRepo) SaveImportJob(ctx context.Context, job *ImportJob) error {
+	r.saved = *job
+	return nil
+}
+
+func TestImporterRun(t *testing.T) {
+	tests := []struct {
+		name        string
+		products    []string
+		wantStatus  ImportJobStatus
+		wantCreated int
+		wantFailed  int
+		wantErrRow  int // Row of the last error, 0 when none
+	}{
+		{name: "all created", products: []string{"a", "b"}, wantStatus: ImportCompleted, wantCreated: 2},
+		{name: "failed product does not stop the rest", products: []string{"a", "bad image", "c"}, wantStatus: ImportCompleted, wantCreated: 2, wantFailed: 1, wantErrRow: 3},
+		{name: "panic fails the job", products: []string{"a", "boom", "c"}, wantStatus: ImportFailed, wantCreated: 1, wantErrRow: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &jobRepo{}
+			importer := NewImporter(&importService{}, repo, nil, nil)
+			var products []ImportProduct
+			for idx, name := range tt.products {
+				products = append(products, ImportProduct{Row: idx + 2, Request: CreateProductRequest{Name: name}})
+			}
+
+			importer.run(context.Background(), &ImportJob{ID: 1, Status: ImportPending}, products)
+
+			job := repo.saved
+			if job.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", job.Status, tt.wantStatus)
+			}
+			if job.FinishedAt == nil {
+				t.Error("finished_at is not set")
+			}
+			if job.Created != tt.wantCreated || job.Failed != tt.wantFailed {
+				t.Errorf("created %d, failed %d; want %d, %d", job.Created, job.Failed, tt.wantCreated, tt.wantFailed)
+			}
+			if tt.wantErrRow == 0 {
+				if len(job.Errors) != 0 {
+					t.Errorf("errors = %+v, want none", job.Errors)
+				}
+			} else if len(job.Errors) == 0 || job.Errors[len(job.Errors)-1].Row != tt.wantErrRow {
+				t.Errorf("errors = %+v, want the last one on row %d", job.Errors, tt.wantErrRow)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package product
+
+import (
+	"fmt"
+
+	"go-ecommerce/internal/modules/warehouse"
+	"go-ecommerce/internal/shared/errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// moveStock changes the stock of a variant in a warehouse by quantity and records the movement
+// The variant row is locked first, so all moves of one variant are serialized.
+// Stock never goes below 0: a decrement larger than the warehouse has left
+// returns ErrInsufficientStock and changes nothing. Stock coming in goes to
+// backordered orders first.
+func moveStock(tx *gorm.DB, warehouseID, productID, variantID uint, kind MovementType, quantity int, reason, actor string, orderID *uint) error {
+	var variant ProductVariant
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ? AND product_id = ?", variantID, productID).
+		First(&variant).Error
+	if err == gorm.ErrRecordNotFound {
+		return errors.ErrRecordNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if quantity == 0 {
+		return nil
+	}
+
+	level := VariantStock{VariantID: variantID, WarehouseID: warehouseID, ProductID: productID, Stock: quantity}
+	if quantity > 0 {
+		err = tx.Clauses(
+			clause.OnConflict{
+				Columns:   []clause.Column{{Name: "variant_id"}, {Name: "warehouse_id"}},
+				DoUpdates: clause.Assignments(map[string]interface{}{"stock": gorm.Expr("variant_stocks.stock + excluded.stock"), "updated_at": gorm.Expr("excluded.updated_at")}),
+			},
+			clause.Returning{Columns: []clause.Column{{Name: "stock"}}},
+		).Create(&level).Error
+		if err != nil {
+			return err
+		}
+	} else {
+		result := tx.Model(&level).
+			Clauses(clause.Returning{Columns: []clause.Column{{Name: "stock"}}}).
+			Where("variant_id = ? AND warehouse_id = ? AND stock + ? >= 0", variantID, warehouseID, quantity).
+			UpdateColumn("stock", gorm.Expr("stock + ?", quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrInsufficientStock
+		}
+	}
+
+	err = tx.Model(&ProductVariant{}).Where("id = ?", variantID).
+		UpdateColumn("stock", gorm.Expr("stock + ?", quantity)).Error
+	if err != nil {
+		return err
+	}
+	if err := recordMovement(tx, warehouseID, productID, variantID, kind, quantity, level.Stock, reason, actor, orderID); err != nil {
+		return err
+	}
+	if err := syncTotalStock(tx, productID); err != nil {
+		return err
+	}
+	if quantity > 0 {
+		return allocateBackorders(tx, warehouseID, productID, variantID, level.Stock)
+	}
+	return nil
+}
+
+// allocateBackorders gives stock that arrived in a warehouse to backordered reservations
+// Oldest reservations are served first; one that cannot be filled completely is
+// split into an allocated part and a part that keeps waiting. Must be called
+// with the variant row locked.
+func allocateBackorders(tx *gorm.DB, warehouseID, productID, variantID uint, available int) error {
+	var waiting []StockReservation
+	err := tx.Where("variant_id = ? AND backordered = ? AND status IN ?", variantID, true, []ReservationStatus{ReservationHeld, ReservationCommitted}).
+		Order("id ASC").
+		Find(&waiting).Error
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(waiting) && available > 0; i++ {
+		r := &waiting[i]
+		take := min(r.Quantity, available)
+		if err := moveStock(tx, warehouseID, productID, variantID, MovementSale, -take, "backorder allocated", ActorSystem, &r.OrderID); err != nil {
+			return err
+		}
+		available -= take
+
+		if take == r.Quantity {
+			err = tx.Model(r).Updates(map[string]interface{}{"backordered": false, "warehouse_id": warehouseID}).Error
+		} else {
+			err = tx.Model(r).UpdateColumn("quantity", r.Quantity-take).Error
+			if err == nil {
+				err = tx.Create(&StockReservation{
+					OrderID:     r.OrderID,
+					ProductID:   productID,
+					VariantID:   variantID,
+					WarehouseID: warehouseID,
+					Quantity:    take,
+					Status:      r.Status,
+					ExpiresAt:   r.ExpiresAt,
+				}).Error
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&ProductVariant{}).Where("id = ?", variantID).
+			UpdateColumn("backordered", gorm.Expr("backordered - ?", take)).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordMovement appends a ledger entry for a change already applied to the stock
+func recordMovement(tx *gorm.DB, warehouseID, productID, variantID uint, kind MovementType, quantity, stockAfter int, reason, actor string, orderID *uint) error {
+	if quantity == 0 {
+		return nil
+	}
+	entry := StockMovement{
+		ProductID:   productID,
+		VariantID:   variantID,
+		WarehouseID: warehouseID,
+		Type:        kind,
+		Quantity:    quantity,
+		StockAfter:  stockAfter,
+		Reason:      reason,
+		Actor:       actor,
+		OrderID:     orderID,
+	}
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("failed to record stock movement: %w", err)
+	}
+	return nil
+}
+
+// defaultWarehouseID returns the warehouse new stock goes to when none is given
+func defaultWarehouseID(tx *gorm.DB) (uint, error) {
+	var w warehouse.Warehouse
+	if err := tx.Select("id").Where("is_default = ?", true).First(&w).Error; err != nil {
+		return 0, fmt.Errorf("no default warehouse: %w", err)
+	}
+	return w.ID, nil
+}
+
+// servingWarehouseID returns the warehouse that ships to a province, or the default one
+func servingWarehouseID(tx *gorm.DB, province string) (uint, error) {
+	var p warehouse.Province
+	err := tx.Where("province_key = ?", warehouse.ProvinceKey(province)).First(&p).Error
+	if err == nil {
+		return p.WarehouseID, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return 0, err
+	}
+	return defaultWarehouseID(tx)
+}
+
+// resolveWarehouse returns the given warehouse, or the default one when id is nil
+func resolveWarehouse(tx *gorm.DB, id *uint) (uint, error) {
+	if id == nil {
+		return defaultWarehouseID(tx)
+	}
+	var count int64
+	if err := tx.Model(&warehouse.Warehouse{}).Where("id = ?", *id).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, errors.ErrUnknownWarehouse
+	}
+	return *id, nil
+}
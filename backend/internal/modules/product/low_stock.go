@@ -0,0 +1,49 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go-ecommerce/internal/shared/jobs"
+	"go-ecommerce/pkg/notify"
+)
+
+// LowStockAlerter sends staff the list of variants at or below their reorder threshold
+// A variant stays on the list every run until it is restocked or its threshold lowered.
+type LowStockAlerter struct {
+	repo     Repository
+	channel  notify.Channel
+	interval time.Duration
+}
+
+// NewLowStockAlerter creates a new low-stock alerter
+func NewLowStockAlerter(repo Repository, channel notify.Channel, interval time.Duration) *LowStockAlerter {
+	return &LowStockAlerter{repo: repo, channel: channel, interval: interval}
+}
+
+// Start runs the alerter in the background until ctx is cancelled
+func (a *LowStockAlerter) Start(ctx context.Context) {
+	jobs.Every(ctx, "low-stock-alerts", a.interval, a.RunOnce)
+}
+
+// RunOnce sends one alert listing every low variant; nothing is sent when none is low
+func (a *LowStockAlerter) RunOnce(ctx context.Context) error {
+	items, err := a.repo.GetLowStock(ctx)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	var body strings.Builder
+	for _, item := range items {
+		fmt.Fprintf(&body, "- %s (%s) SKU %s: còn %d, ngưỡng %d\n", item.ProductName, item.Size, item.SKU, item.Stock, item.ReorderThreshold)
+	}
+	body.WriteString("\nXem báo cáo: /admin/products/low-stock\n")
+
+	subject := fmt.Sprintf("%d mẫu sắp hết hàng", len(items))
+	return a.channel.Notify(ctx, subject, body.String())
+}
@@ -7,6 +7,7 @@ import (
 )
 
 // ProductChecker checks if products exist for a category or brand
+// Products in trash count too, since restoring them needs the category/brand.
 type ProductChecker struct {
 	db *gorm.DB
 }
@@ -18,13 +19,13 @@ func NewProductChecker(db *gorm.DB) *ProductChecker {
 // HasProductsWithCategory checks if any products exist for a category
 func (pc *ProductChecker) HasProductsWithCategory(ctx context.Context, categoryID uint) (bool, error) {
 	var count int64
-	err := pc.db.WithContext(ctx).Model(&Product{}).Where("category_id = ?", categoryID).Count(&count).Error
+	err := pc.db.WithContext(ctx).Unscoped().Model(&Product{}).Where("category_id = ?", categoryID).Count(&count).Error
 	return count > 0, err
 }
 
 // HasProductsWithBrand checks if any products exist for a brand
 func (pc *ProductChecker) HasProductsWithBrand(ctx context.Context, brandID uint) (bool, error) {
 	var count int64
-	err := pc.db.WithContext(ctx).Model(&Product{}).Where("brand_id = ?", brandID).Count(&count).Error
+	err := pc.db.WithContext(ctx).Unscoped().Model(&Product{}).Where("brand_id = ?", brandID).Count(&count).Error
 	return count > 0, err
 }
@@ -0,0 +1,32 @@
+package product
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RatingUpdater writes review aggregates onto products
+// It works inside the caller's transaction so ratings never drift from reviews.
+type RatingUpdater struct{}
+
+func NewRatingUpdater() *RatingUpdater {
+	return &RatingUpdater{}
+}
+
+// LockProduct locks the product row until the transaction ends
+// Trashed products are included, so their reviews can still be moderated.
+// Returns gorm.ErrRecordNotFound if the product does not exist.
+func (u *RatingUpdater) LockProduct(tx *gorm.DB, productID uint) error {
+	var product Product
+	return tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&product, productID).Error
+}
+
+// UpdateRating sets rating_avg and review_count without touching updated_at
+func (u *RatingUpdater) UpdateRating(tx *gorm.DB, productID uint, avg float64, count int) error {
+	return tx.Unscoped().Model(&Product{}).Where("id = ?", productID).UpdateColumns(map[string]interface{}{
+		"rating_avg":   avg,
+		"review_count": count,
+	}).Error
+}
@@ -2,6 +2,9 @@ package product
 
 import (
 	"context"
+	"time"
+
+	"go-ecommerce/pkg/money"
 
 	"gorm.io/gorm"
 )
@@ -11,20 +14,59 @@ type Repository interface {
 	// Product operations
 	Create(ctx context.Context, product *Product) error
 	GetByID(ctx context.Context, id uint) (*Product, error)
-	GetAll(ctx context.Context) ([]Product, error)
+	GetBySlug(ctx context.Context, slug string) (*Product, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]Product, error)
+	GetAll(ctx context.Context, filter ProductFilter) ([]Product, error)
 	Update(ctx context.Context, product *Product) error
 	Delete(ctx context.Context, id uint) error
+	UpdateFields(ctx context.Context, id uint, fields map[string]interface{}) error
+
+	// Trash operations (soft-deleted products)
+	GetTrashed(ctx context.Context) ([]Product, error)
+	GetTrashedByID(ctx context.Context, id uint) (*Product, error)
+	GetTrashedBefore(ctx context.Context, before time.Time) ([]Product, error)
+	Restore(ctx context.Context, id uint) error
+
+	// Lifecycle scheduling
+	GetDueForPublish(ctx context.Context, now time.Time) ([]Product, error)
+	GetDueForUnpublish(ctx context.Context, now time.Time) ([]Product, error)
+	GetVariantsWithDueSales(ctx context.Context, now time.Time) ([]ProductVariant, error)
+	GetVariantsWithExpiredSales(ctx context.Context, now time.Time) ([]ProductVariant, error)
 
 	// Variant operations
 	CreateVariant(ctx context.Context, variant *ProductVariant) error
+	GetVariantByID(ctx context.Context, productID, variantID uint) (*ProductVariant, error)
 	UpdateVariantSKU(ctx context.Context, variantID uint, sku string) error
+	UpdateVariantFields(ctx context.Context, variantID uint, fields map[string]interface{}) error
 	GetVariantsByProductID(ctx context.Context, productID uint) ([]ProductVariant, error)
+	GetVariantsBySKUs(ctx context.Context, skus []string) ([]ProductVariant, error)
 
 	// Image operations
 	CreateImage(ctx context.Context, image *ProductImage) error
 	GetImagesByProductID(ctx context.Context, productID uint) ([]ProductImage, error)
 	DeleteImage(ctx context.Context, imageID uint) error
 
+	// Export streams products matching filter in batches, ordered by ID
+	ExportBatches(ctx context.Context, filter ProductFilter, batchSize int, fn func([]Product) error) error
+
+	// Price history
+	GetPriceHistory(ctx context.Context, variantID uint) ([]PriceHistory, error)
+	GetLowestPrices(ctx context.Context, variantIDs []uint, since time.Time) (map[uint]money.Money, error)
+
+	// Stock ledger operations
+	GetStockMovements(ctx context.Context, variantID uint) ([]StockMovement, error)
+	GetStockDiscrepancies(ctx context.Context) ([]StockDiscrepancy, error)
+	RecordOpeningBalances(ctx context.Context) (int64, error)
+	GetStockLevels(ctx context.Context, variantID uint) ([]StockLevelResponse, error)
+	AssignToWarehouse(ctx context.Context, warehouseID uint) error
+	GetLowStock(ctx context.Context) ([]LowStockItem, error)
+
+	// Import jobs
+	CreateImportJob(ctx context.Context, job *ImportJob) error
+	GetImportJob(ctx context.Context, id uint) (*ImportJob, error)
+	SaveImportJob(ctx context.Context, job *ImportJob) error
+	FailUnfinishedImportJobs(ctx context.Context) error
+
 	// Transaction support
 	WithTransaction(fn func(*gorm.DB) error) error
 }
@@ -38,6 +80,16 @@ func NewRepository(db *gorm.DB) Repository {
 	return &repository{db: db}
 }
 
+// preloadDetails loads options, variants (with option values) and images in display order
+func preloadDetails(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Options", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Options.Values", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Variants.OptionValues", func(db *gorm.DB) *gorm.DB { return db.Order("option_id ASC") }).
+		Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Order("display_order ASC") })
+}
+
 // WithTransaction executes a function within a transaction
 func (r *repository) WithTransaction(fn func(*gorm.DB) error) error {
 	return r.db.Transaction(fn)
@@ -50,8 +102,7 @@ func (r *repository) Create(ctx context.Context, product *Product) error {
 func (r *repository) GetByID(ctx context.Context, id uint) (*Product, error) {
 	var product Product
 	err := r.db.WithContext(ctx).
-		Preload("Variants").
-		Preload("Images").
+		Scopes(preloadDetails).
 		First(&product, id).Error
 	if err != nil {
 		return nil, err
@@ -59,11 +110,42 @@ func (r *repository) GetByID(ctx context.Context, id uint) (*Product, error) {
 	return &product, nil
 }
 
-func (r *repository) GetAll(ctx context.Context) ([]Product, error) {
+// GetByIDs loads several products with details; missing or trashed IDs are left out
+func (r *repository) GetByIDs(ctx context.Context, ids []uint) ([]Product, error) {
 	var products []Product
+	if len(ids) == 0 {
+		return products, nil
+	}
 	err := r.db.WithContext(ctx).
-		Preload("Variants").
-		Preload("Images").
+		Scopes(preloadDetails).
+		Where("id IN ?", ids).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *repository) GetBySlug(ctx context.Context, slug string) (*Product, error) {
+	var product Product
+	err := r.db.WithContext(ctx).
+		Scopes(preloadDetails).
+		Where("slug = ?", slug).
+		First(&product).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *repository) GetAll(ctx context.Context, filter ProductFilter) ([]Product, error) {
+	var products []Product
+	query := r.db.WithContext(ctx)
+	if filter.CategoryIDs != nil {
+		query = query.Where("category_id IN ?", filter.CategoryIDs)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	err := query.
+		Scopes(preloadDetails).
 		Find(&products).Error
 	if err != nil {
 		return nil, err
@@ -71,6 +153,24 @@ func (r *repository) GetAll(ctx context.Context) ([]Product, error) {
 	return products, nil
 }
 
+// ExportBatches loads products batch by batch so exports never hold the whole catalog
+func (r *repository) ExportBatches(ctx context.Context, filter ProductFilter, batchSize int, fn func([]Product) error) error {
+	var batch []Product
+	query := r.db.WithContext(ctx)
+	if filter.CategoryIDs != nil {
+		query = query.Where("category_id IN ?", filter.CategoryIDs)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	return query.
+		Scopes(preloadDetails).
+		Order("id ASC").
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
 func (r *repository) Update(ctx context.Context, product *Product) error {
 	return r.db.WithContext(ctx).Save(product).Error
 }
@@ -79,23 +179,127 @@ func (r *repository) Delete(ctx context.Context, id uint) error {
 	return r.db.WithContext(ctx).Delete(&Product{}, id).Error
 }
 
+func (r *repository) UpdateFields(ctx context.Context, id uint, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&Product{}).Where("id = ?", id).Updates(fields).Error
+}
+
+func (r *repository) GetTrashed(ctx context.Context) ([]Product, error) {
+	var products []Product
+	err := r.db.WithContext(ctx).Unscoped().
+		Scopes(preloadDetails).
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Find(&products).Error
+	return products, err
+}
+
+func (r *repository) GetTrashedByID(ctx context.Context, id uint) (*Product, error) {
+	var product Product
+	err := r.db.WithContext(ctx).Unscoped().
+		Scopes(preloadDetails).
+		Where("deleted_at IS NOT NULL").
+		First(&product, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetTrashedBefore returns products deleted before the given time (retention expired)
+func (r *repository) GetTrashedBefore(ctx context.Context, before time.Time) ([]Product, error) {
+	var products []Product
+	err := r.db.WithContext(ctx).Unscoped().
+		Preload("Images").
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *repository) Restore(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Unscoped().Model(&Product{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
+}
+
+// GetDueForPublish returns scheduled products whose publish time has passed
+func (r *repository) GetDueForPublish(ctx context.Context, now time.Time) ([]Product, error) {
+	var products []Product
+	err := r.db.WithContext(ctx).
+		Scopes(preloadDetails).
+		Where("status = ? AND publish_at <= ?", StatusScheduled, now).
+		Find(&products).Error
+	return products, err
+}
+
+// GetDueForUnpublish returns published products whose unpublish time has passed
+func (r *repository) GetDueForUnpublish(ctx context.Context, now time.Time) ([]Product, error) {
+	var products []Product
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND unpublish_at <= ?", StatusPublished, now).
+		Find(&products).Error
+	return products, err
+}
+
+// GetVariantsWithDueSales returns inactive sales whose window has opened
+func (r *repository) GetVariantsWithDueSales(ctx context.Context, now time.Time) ([]ProductVariant, error) {
+	var variants []ProductVariant
+	err := r.db.WithContext(ctx).
+		Where("sale_active = ? AND sale_price IS NOT NULL", false).
+		Where("(sale_starts_at IS NULL OR sale_starts_at <= ?)", now).
+		Where("(sale_ends_at IS NULL OR sale_ends_at > ?)", now).
+		Find(&variants).Error
+	return variants, err
+}
+
+// GetVariantsWithExpiredSales returns variants whose sale has ended
+func (r *repository) GetVariantsWithExpiredSales(ctx context.Context, now time.Time) ([]ProductVariant, error) {
+	var variants []ProductVariant
+	err := r.db.WithContext(ctx).
+		Where("sale_price IS NOT NULL AND sale_ends_at <= ?", now).
+		Find(&variants).Error
+	return variants, err
+}
+
 // Variant operations
 func (r *repository) CreateVariant(ctx context.Context, variant *ProductVariant) error {
 	return r.db.WithContext(ctx).Create(variant).Error
 }
 
+func (r *repository) GetVariantByID(ctx context.Context, productID, variantID uint) (*ProductVariant, error) {
+	var variant ProductVariant
+	err := r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		First(&variant, variantID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
 func (r *repository) UpdateVariantSKU(ctx context.Context, variantID uint, sku string) error {
 	return r.db.WithContext(ctx).Model(&ProductVariant{}).
 		Where("id = ?", variantID).
 		Update("sku", sku).Error
 }
 
+func (r *repository) UpdateVariantFields(ctx context.Context, variantID uint, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&ProductVariant{}).
+		Where("id = ?", variantID).
+		Updates(fields).Error
+}
+
 func (r *repository) GetVariantsByProductID(ctx context.Context, productID uint) ([]ProductVariant, error) {
 	var variants []ProductVariant
 	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Find(&variants).Error
 	return variants, err
 }
 
+func (r *repository) GetVariantsBySKUs(ctx context.Context, skus []string) ([]ProductVariant, error) {
+	var variants []ProductVariant
+	err := r.db.WithContext(ctx).Where("sku IN ?", skus).Find(&variants).Error
+	return variants, err
+}
+
 // Image operations
 func (r *repository) CreateImage(ctx context.Context, image *ProductImage) error {
 	return r.db.WithContext(ctx).Create(image).Error
@@ -113,3 +317,183 @@ func (r *repository) GetImagesByProductID(ctx context.Context, productID uint) (
 func (r *repository) DeleteImage(ctx context.Context, imageID uint) error {
 	return r.db.WithContext(ctx).Delete(&ProductImage{}, imageID).Error
 }
+
+// Import jobs
+func (r *repository) CreateImportJob(ctx context.Context, job *ImportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *repository) GetImportJob(ctx context.Context, id uint) (*ImportJob, error) {
+	var job ImportJob
+	err := r.db.WithContext(ctx).First(&job, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *repository) SaveImportJob(ctx context.Context, job *ImportJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+// FailUnfinishedImportJobs marks jobs cut off by a restart as failed
+func (r *repository) FailUnfinishedImportJobs(ctx context.Context) error {
+	return r.db.WithContext(ctx).Model(&ImportJob{}).
+		Where("status IN ?", []ImportJobStatus{ImportPending, ImportRunning}).
+		Updates(map[string]interface{}{"status": ImportFailed, "finished_at": time.Now()}).Error
+}
+
+// Price history operations
+func (r *repository) GetPriceHistory(ctx context.Context, variantID uint) ([]PriceHistory, error) {
+	var history []PriceHistory
+	err := r.db.WithContext(ctx).
+		Where("variant_id = ?", variantID).
+		Order("id DESC").
+		Find(&history).Error
+	return history, err
+}
+
+// GetLowestPrices returns the lowest price each variant had since the given time
+// The price in effect at since (the last entry before it) counts as well.
+func (r *repository) GetLowestPrices(ctx context.Context, variantIDs []uint, since time.Time) (map[uint]money.Money, error) {
+	var rows []struct {
+		VariantID uint
+		Lowest    money.Money
+	}
+	err := r.db.WithContext(ctx).
+		Table("product_price_histories AS h").
+		Select("h.variant_id, MIN(h.price) AS lowest").
+		Where("h.variant_id IN ?", variantIDs).
+		Where("(h.created_at >= ? OR h.id = (SELECT MAX(p.id) FROM product_price_histories p WHERE p.variant_id = h.variant_id AND p.created_at < ?))", since, since).
+		Group("h.variant_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	lowest := make(map[uint]money.Money, len(rows))
+	for _, row := range rows {
+		lowest[row.VariantID] = row.Lowest
+	}
+	return lowest, nil
+}
+
+// Stock ledger operations
+func (r *repository) GetStockMovements(ctx context.Context, variantID uint) ([]StockMovement, error) {
+	var movements []StockMovement
+	err := r.db.WithContext(ctx).
+		Where("variant_id = ?", variantID).
+		Order("id DESC").
+		Find(&movements).Error
+	return movements, err
+}
+
+// GetStockDiscrepancies compares variant stock and product total stock with the ledger
+// Trashed products are included, they keep their stock.
+func (r *repository) GetStockDiscrepancies(ctx context.Context) ([]StockDiscrepancy, error) {
+	var variants []StockDiscrepancy
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT v.product_id, v.id AS variant_id, v.stock, COALESCE(SUM(m.quantity), 0) AS ledger
+		FROM product_variants v
+		LEFT JOIN product_stock_movements m ON m.variant_id = v.id
+		GROUP BY v.id, v.product_id, v.stock
+		HAVING v.stock <> COALESCE(SUM(m.quantity), 0)
+		ORDER BY v.product_id, v.id`).
+		Scan(&variants).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var products []StockDiscrepancy
+	err = r.db.WithContext(ctx).Raw(`
+		SELECT p.id AS product_id, p.total_stock AS stock, COALESCE(SUM(m.quantity), 0) AS ledger
+		FROM products p
+		LEFT JOIN product_variants v ON v.product_id = p.id
+		LEFT JOIN product_stock_movements m ON m.variant_id = v.id
+		GROUP BY p.id, p.total_stock
+		HAVING p.total_stock <> COALESCE(SUM(m.quantity), 0)
+		ORDER BY p.id`).
+		Scan(&products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var levels []StockDiscrepancy
+	err = r.db.WithContext(ctx).Raw(`
+		SELECT s.product_id, s.variant_id, s.warehouse_id, s.stock, COALESCE(SUM(m.quantity), 0) AS ledger
+		FROM variant_stocks s
+		LEFT JOIN product_stock_movements m ON m.variant_id = s.variant_id AND m.warehouse_id = s.warehouse_id
+		GROUP BY s.id, s.product_id, s.variant_id, s.warehouse_id, s.stock
+		HAVING s.stock <> COALESCE(SUM(m.quantity), 0)
+		ORDER BY s.product_id, s.variant_id, s.warehouse_id`).
+		Scan(&levels).Error
+	if err != nil {
+		return nil, err
+	}
+	return append(append(variants, levels...), products...), nil
+}
+
+// RecordOpeningBalances writes an adjustment for the stock of variants that have
+// no ledger entries yet (created before the ledger existed)
+func (r *repository) RecordOpeningBalances(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO product_stock_movements (product_id, variant_id, warehouse_id, type, quantity, stock_after, reason, actor, created_at)
+		SELECT v.product_id, v.id, (SELECT id FROM warehouses WHERE is_default LIMIT 1), ?, v.stock, v.stock, 'opening balance', ?, NOW()
+		FROM product_variants v
+		WHERE v.stock <> 0
+		AND NOT EXISTS (SELECT 1 FROM product_stock_movements m WHERE m.variant_id = v.id)`,
+		MovementAdjustment, ActorSystem)
+	return result.RowsAffected, result.Error
+}
+
+// GetStockLevels returns the stock of a variant per warehouse, default warehouse first
+func (r *repository) GetStockLevels(ctx context.Context, variantID uint) ([]StockLevelResponse, error) {
+	var levels []StockLevelResponse
+	err := r.db.WithContext(ctx).Table("variant_stocks s").
+		Select("s.warehouse_id, w.code AS warehouse_code, w.name AS warehouse_name, s.stock").
+		Joins("JOIN warehouses w ON w.id = s.warehouse_id").
+		Where("s.variant_id = ?", variantID).
+		Order("w.is_default DESC, w.code ASC").
+		Scan(&levels).Error
+	return levels, err
+}
+
+// AssignToWarehouse moves stock kept before warehouses existed into warehouseID
+// Variants without per-warehouse stock get a row with their whole stock, and
+// ledger entries and reservations without a warehouse are attributed to it.
+// Running it again changes nothing.
+func (r *repository) AssignToWarehouse(ctx context.Context, warehouseID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			INSERT INTO variant_stocks (variant_id, warehouse_id, product_id, stock, updated_at)
+			SELECT v.id, ?, v.product_id, v.stock, NOW()
+			FROM product_variants v
+			WHERE NOT EXISTS (SELECT 1 FROM variant_stocks s WHERE s.variant_id = v.id)`,
+			warehouseID).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&StockMovement{}).
+			Where("warehouse_id IS NULL OR warehouse_id = 0").
+			Update("warehouse_id", warehouseID).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&StockReservation{}).
+			Where("warehouse_id IS NULL OR warehouse_id = 0").
+			Update("warehouse_id", warehouseID).Error
+	})
+}
+
+// GetLowStock returns variants with a reorder threshold whose stock fell to it, emptiest first
+// Archived and trashed products are left out, nobody reorders them.
+func (r *repository) GetLowStock(ctx context.Context) ([]LowStockItem, error) {
+	var items []LowStockItem
+	err := r.db.WithContext(ctx).Table("product_variants v").
+		Select("v.product_id, p.name AS product_name, v.id AS variant_id, v.sku, v.size, v.stock, v.reorder_threshold").
+		Joins("JOIN products p ON p.id = v.product_id AND p.deleted_at IS NULL").
+		Where("v.reorder_threshold > 0 AND v.stock <= v.reorder_threshold AND p.status <> ?", StatusArchived).
+		Order("v.stock ASC, p.name ASC, v.id ASC").
+		Scan(&items).Error
+	return items, err
+}
@@ -0,0 +1,125 @@
+package product
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"go-ecommerce/internal/shared/jobs"
+
+	"gorm.io/gorm"
+)
+
+// Scheduler publishes and unpublishes products at their scheduled times
+// and starts and ends variant sales.
+type Scheduler struct {
+	repo     Repository
+	interval time.Duration
+}
+
+// NewScheduler creates a new product scheduler
+func NewScheduler(repo Repository, interval time.Duration) *Scheduler {
+	return &Scheduler{repo: repo, interval: interval}
+}
+
+// Start runs the scheduler in the background until ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	jobs.Every(ctx, "product-scheduler", s.interval, func(ctx context.Context) error {
+		s.RunOnce(ctx)
+		return nil
+	})
+}
+
+// RunOnce processes every product whose publish or unpublish time has passed
+func (s *Scheduler) RunOnce(ctx context.Context) {
+	now := time.Now()
+	s.runSales(ctx, now)
+
+	due, err := s.repo.GetDueForPublish(ctx, now)
+	if err != nil {
+		log.Printf("Scheduler: load products to publish failed: %v", err)
+	}
+	for _, p := range due {
+		// Readiness may have changed since scheduling (e.g. stock sold out).
+		// The product goes back to draft with the reason, instead of being retried every tick.
+		if issues := publishReadinessIssues(&p); len(issues) > 0 {
+			log.Printf("Scheduler: product %d not published: %v", p.ID, issues)
+			reason := []rune("not published at the scheduled time: " + strings.Join(issues, "; "))
+			if len(reason) > 500 {
+				reason = reason[:500]
+			}
+			err := s.repo.UpdateFields(ctx, p.ID, map[string]interface{}{
+				"status":        StatusDraft,
+				"publish_at":    nil,
+				"publish_error": string(reason),
+			})
+			if err != nil {
+				log.Printf("Scheduler: move product %d back to draft failed: %v", p.ID, err)
+			}
+			continue
+		}
+
+		err := s.repo.UpdateFields(ctx, p.ID, map[string]interface{}{
+			"status":        StatusPublished,
+			"publish_at":    nil,
+			"published_at":  now,
+			"publish_error": "",
+		})
+		if err != nil {
+			log.Printf("Scheduler: publish product %d failed: %v", p.ID, err)
+		}
+	}
+
+	expired, err := s.repo.GetDueForUnpublish(ctx, now)
+	if err != nil {
+		log.Printf("Scheduler: load products to unpublish failed: %v", err)
+	}
+	for _, p := range expired {
+		err := s.repo.UpdateFields(ctx, p.ID, map[string]interface{}{
+			"status":       StatusArchived,
+			"unpublish_at": nil,
+		})
+		if err != nil {
+			log.Printf("Scheduler: unpublish product %d failed: %v", p.ID, err)
+		}
+	}
+}
+
+// runSales ends expired sales before starting due ones, so a sale that was
+// scheduled and already over is never switched on.
+func (s *Scheduler) runSales(ctx context.Context, now time.Time) {
+	expired, err := s.repo.GetVariantsWithExpiredSales(ctx, now)
+	if err != nil {
+		log.Printf("Scheduler: load expired sales failed: %v", err)
+	}
+	for i := range expired {
+		v := &expired[i]
+		err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+			return endSale(tx.WithContext(ctx), v, ActorSystem)
+		})
+		if err != nil {
+			log.Printf("Scheduler: end sale of variant %d failed: %v", v.ID, err)
+		}
+	}
+
+	due, err := s.repo.GetVariantsWithDueSales(ctx, now)
+	if err != nil {
+		log.Printf("Scheduler: load due sales failed: %v", err)
+	}
+	for i := range due {
+		v := &due[i]
+		before := *v
+		v.SaleActive = true
+		err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+			tx = tx.WithContext(ctx)
+			if err := tx.Model(v).Update("sale_active", true).Error; err != nil {
+				return err
+			}
+			return logPriceChange(tx, &before, v, PriceSaleStarted, ActorSystem, "")
+		})
+		if err != nil {
+			log.Printf("Scheduler: start sale of variant %d failed: %v", v.ID, err)
+		}
+	}
+}
@@ -4,48 +4,115 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
 	"mime/multipart"
+	"strings"
+	"time"
 
 	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/internal/shared/slug"
 	"go-ecommerce/pkg/cloudinary"
+	"go-ecommerce/pkg/money"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// lowestPriceDays is the window for the "lowest price in the last 30 days" shown with sales
+const lowestPriceDays = 30
+
 // Service interface
 type Service interface {
-	Create(ctx context.Context, req CreateProductRequest, variantsJSON string, imageFiles []*multipart.FileHeader, categoryName, brandName string) (*ProductResponse, error)
+	Create(ctx context.Context, req CreateProductRequest, variantsJSON, optionsJSON string, imageFiles []*multipart.FileHeader, categoryName, brandName string) (*ProductResponse, error)
 	GetByID(ctx context.Context, id uint) (*ProductResponse, error)
-	GetAll(ctx context.Context) ([]ProductResponse, error)
+	GetAll(ctx context.Context, filter ProductFilter) ([]ProductResponse, error)
 	Update(ctx context.Context, id uint, req UpdateProductRequest) (*ProductResponse, error)
 	Delete(ctx context.Context, id uint) error
+	GenerateVariants(ctx context.Context, id uint, req GenerateVariantsRequest, categoryName string) (*ProductResponse, error)
+
+	// Variant management
+	AddVariant(ctx context.Context, id uint, input VariantInput, categoryName string) (*ProductResponse, error)
+	UpdateVariant(ctx context.Context, id, variantID uint, req UpdateVariantRequest, actor string) (*ProductResponse, error)
+	DeleteVariant(ctx context.Context, id, variantID uint, actor string) (*ProductResponse, error)
+	SetSale(ctx context.Context, id, variantID uint, req SetSaleRequest, actor string) (*ProductResponse, error)
+	ClearSale(ctx context.Context, id, variantID uint, actor string) (*ProductResponse, error)
+	SetBackorder(ctx context.Context, id, variantID uint, req SetBackorderRequest) (*ProductResponse, error)
+	GetPriceHistory(ctx context.Context, id, variantID uint) ([]PriceHistory, error)
+	MoveStock(ctx context.Context, id, variantID uint, req StockMovementRequest, actor string) (*ProductResponse, error)
+	GetStockMovements(ctx context.Context, id, variantID uint) ([]StockMovement, error)
+	TransferStock(ctx context.Context, id, variantID uint, req TransferStockRequest, actor string) (*ProductResponse, error)
+	GetStockLevels(ctx context.Context, id, variantID uint) ([]StockLevelResponse, error)
+	GetLowStock(ctx context.Context) ([]LowStockItem, error)
+
+	// Image management
+	AddImages(ctx context.Context, id uint, imageFiles []*multipart.FileHeader) (*ProductResponse, error)
+	ReplaceImage(ctx context.Context, id, imageID uint, imageFile *multipart.FileHeader) (*ProductResponse, error)
+	DeleteImage(ctx context.Context, id, imageID uint) (*ProductResponse, error)
+	ReorderImages(ctx context.Context, id uint, req ReorderImagesRequest) (*ProductResponse, error)
+
+	// Lifecycle
+	CheckReadiness(ctx context.Context, id uint) (*ReadinessResponse, error)
+	Publish(ctx context.Context, id uint) (*ProductResponse, error)
+	Unpublish(ctx context.Context, id uint) (*ProductResponse, error)
+	Archive(ctx context.Context, id uint) (*ProductResponse, error)
+	Schedule(ctx context.Context, id uint, req ScheduleProductRequest) (*ProductResponse, error)
+
+	// Trash
+	GetTrash(ctx context.Context) ([]ProductResponse, error)
+	GetTrashedByID(ctx context.Context, id uint) (*ProductResponse, error)
+	Restore(ctx context.Context, id uint) (*ProductResponse, error)
+	Purge(ctx context.Context, id uint) error
+	PurgeExpired(ctx context.Context) error
+
+	// Storefront
+	GetPublished(ctx context.Context, filter ProductFilter) ([]ProductResponse, error)
+	GetPublishedBySlug(ctx context.Context, productSlug string) (*ProductResponse, error)
+	ResolveOldSlug(ctx context.Context, oldSlug string) (string, error)
+
+	// Bulk import
+	CreateImported(ctx context.Context, input ImportProduct) (*ProductResponse, error)
 }
 
 type service struct {
-	repo       Repository
-	cloudinary *cloudinary.Client
+	repo           Repository
+	cloudinary     *cloudinary.Client
+	slugs          *slug.Registry
+	trashRetention time.Duration
 }
 
 // NewService creates a new product service
-func NewService(repo Repository, cloudinary *cloudinary.Client) Service {
-	return &service{repo: repo, cloudinary: cloudinary}
+// Deleted products stay restorable in trash for trashRetention.
+func NewService(repo Repository, cloudinary *cloudinary.Client, slugs *slug.Registry, trashRetention time.Duration) Service {
+	return &service{repo: repo, cloudinary: cloudinary, slugs: slugs, trashRetention: trashRetention}
 }
 
-func (s *service) Create(ctx context.Context, req CreateProductRequest, variantsJSON string, imageFiles []*multipart.FileHeader, categoryName, brandName string) (*ProductResponse, error) {
-	// 1. Validate variants JSON
-	if len(variantsJSON) == 0 {
-		return nil, fmt.Errorf("variants are required")
+func (s *service) Create(ctx context.Context, req CreateProductRequest, variantsJSON, optionsJSON string, imageFiles []*multipart.FileHeader, categoryName, brandName string) (*ProductResponse, error) {
+	// 1. Parse options from JSON string (optional)
+	var optionInputs []OptionInput
+	if len(optionsJSON) > 0 {
+		if err := json.Unmarshal([]byte(optionsJSON), &optionInputs); err != nil {
+			return nil, fmt.Errorf("invalid options JSON: %w", err)
+		}
+		if err := validateOptionInputs(optionInputs); err != nil {
+			return nil, err
+		}
 	}
 
 	// 2. Parse variants from JSON string
+	// Variants may be omitted when options are given; the full matrix is generated instead
 	var variantInputs []VariantInput
-	if err := json.Unmarshal([]byte(variantsJSON), &variantInputs); err != nil {
-		return nil, fmt.Errorf("invalid variants JSON: %w", err)
+	if len(variantsJSON) > 0 {
+		if err := json.Unmarshal([]byte(variantsJSON), &variantInputs); err != nil {
+			return nil, fmt.Errorf("invalid variants JSON: %w", err)
+		}
 	}
 
-	if len(variantInputs) == 0 {
+	if len(variantInputs) == 0 && len(optionInputs) == 0 {
 		return nil, fmt.Errorf("at least 1 variant is required")
 	}
+	if err := validateVariantCount(optionInputs, variantInputs); err != nil {
+		return nil, err
+	}
 
 	// 3. Validate images (min 1, max 5)
 	if len(imageFiles) == 0 {
@@ -55,63 +122,118 @@ func (s *service) Create(ctx context.Context, req CreateProductRequest, variants
 		return nil, fmt.Errorf("maximum 5 images allowed")
 	}
 
+	// 4. Upload images to Cloudinary
+	uploaded, err := s.uploadImages(ctx, imageFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.createProduct(ctx, req, optionInputs, variantInputs, uploaded, categoryName)
+	if err != nil {
+		s.deleteUploads(ctx, uploaded)
+		return nil, err
+	}
+
+	return ToProductResponse(product), nil
+}
+
+// CreateImported creates one product of a bulk import, fetching its images by URL
+func (s *service) CreateImported(ctx context.Context, input ImportProduct) (*ProductResponse, error) {
+	if err := validateVariantCount(input.Options, input.Variants); err != nil {
+		return nil, err
+	}
+
+	var uploaded []*cloudinary.UploadResult
+	for _, url := range input.ImageURLs {
+		result, err := s.cloudinary.UploadURL(ctx, url, "products")
+		if err != nil {
+			s.deleteUploads(ctx, uploaded)
+			return nil, fmt.Errorf("failed to upload image %s: %w", url, err)
+		}
+		uploaded = append(uploaded, result)
+	}
+
+	product, err := s.createProduct(ctx, input.Request, input.Options, input.Variants, uploaded, input.CategoryName)
+	if err != nil {
+		s.deleteUploads(ctx, uploaded)
+		return nil, err
+	}
+
+	return ToProductResponse(product), nil
+}
+
+// createProduct stores a product with its options, variants and already uploaded images
+func (s *service) createProduct(ctx context.Context, req CreateProductRequest, optionInputs []OptionInput, variantInputs []VariantInput, uploaded []*cloudinary.UploadResult, categoryName string) (*Product, error) {
 	var createdProduct *Product
 
-	// 4. Start Transaction
 	err := s.repo.WithTransaction(func(tx *gorm.DB) error {
-		// Step 4.1: Create Product
+		tx = tx.WithContext(ctx)
+
+		// Step 1: Create Product with a slug no other product uses
+		productSlug, err := s.slugs.Unique(tx, slug.EntityProduct, slug.Make(req.Name), 0)
+		if err != nil {
+			return fmt.Errorf("failed to generate slug: %w", err)
+		}
+
 		product := &Product{
 			Name:        req.Name,
-			Slug:        generateSlug(req.Name),
+			Slug:        productSlug,
 			Description: req.Description,
 			CategoryID:  req.CategoryID,
 			BrandID:     req.BrandID,
 			TotalStock:  0, // Will be calculated later
+			Status:      StatusDraft,
 		}
 
 		if err := tx.Create(product).Error; err != nil {
 			return fmt.Errorf("failed to create product: %w", err)
 		}
 
-		// Step 4.2: Create Variants with 2-step SKU generation
-		var variants []ProductVariant
-		for _, input := range variantInputs {
-			variant := ProductVariant{
-				ProductID: product.ID,
-				Price:     input.Price,
-				Stock:     input.Stock,
-				Size:      input.Size,
-			}
-
-			// Create variant to get ID
-			if err := tx.Create(&variant).Error; err != nil {
-				return fmt.Errorf("failed to create variant: %w", err)
-			}
+		// Step 2: Create option definitions
+		options, err := createOptions(tx, product.ID, optionInputs)
+		if err != nil {
+			return err
+		}
 
-			// Generate SKU with the new ID
-			sku := generateSKU(categoryName, product.Name, variant.ID)
-			if err := tx.Model(&variant).Update("sku", sku).Error; err != nil {
-				return fmt.Errorf("failed to update SKU: %w", err)
+		// Step 3: Create Variants with 2-step SKU generation
+		var variants []ProductVariant
+		if len(variantInputs) == 0 {
+			for _, combo := range generateVariantMatrix(options) {
+				variant, err := createVariant(tx, product, categoryName, req.DefaultPrice, req.DefaultStock, "", combo)
+				if err != nil {
+					return err
+				}
+				variants = append(variants, *variant)
 			}
-
-			variant.SKU = sku
-			variants = append(variants, variant)
 		}
 
-		// Step 4.3: Upload images to Cloudinary
-		var images []ProductImage
-		for i, fileHeader := range imageFiles {
-			file, err := fileHeader.Open()
+		seen := make(map[string]bool)
+		for _, input := range variantInputs {
+			values, err := resolveOptionValues(options, input.Options)
 			if err != nil {
-				return fmt.Errorf("failed to open image file: %w", err)
+				return err
+			}
+			if len(values) == 0 && strings.TrimSpace(input.Size) == "" {
+				return fmt.Errorf("variant size is required")
+			}
+			if len(values) > 0 {
+				key := optionKey(values)
+				if seen[key] {
+					return fmt.Errorf("duplicate variant: %s", variantLabel(values))
+				}
+				seen[key] = true
 			}
-			defer file.Close()
 
-			result, err := s.cloudinary.Upload(ctx, file, "products")
+			variant, err := createVariant(tx, product, categoryName, input.Price, input.Stock, input.Size, values)
 			if err != nil {
-				return fmt.Errorf("failed to upload image: %w", err)
+				return err
 			}
+			variants = append(variants, *variant)
+		}
 
+		// Step 4: Save uploaded images
+		var images []ProductImage
+		for i, result := range uploaded {
 			image := ProductImage{
 				ProductID:     product.ID,
 				ImageURL:      result.URL,
@@ -126,13 +248,14 @@ func (s *service) Create(ctx context.Context, req CreateProductRequest, variants
 			images = append(images, image)
 		}
 
-		// Step 4.4: Calculate and update total_stock
+		// Step 5: Calculate and update total_stock
 		totalStock := calculateTotalStock(variants)
 		if err := tx.Model(&product).Update("total_stock", totalStock).Error; err != nil {
 			return fmt.Errorf("failed to update total stock: %w", err)
 		}
 
 		product.TotalStock = totalStock
+		product.Options = options
 		product.Variants = variants
 		product.Images = images
 		createdProduct = product
@@ -144,7 +267,7 @@ func (s *service) Create(ctx context.Context, req CreateProductRequest, variants
 		return nil, err
 	}
 
-	return ToProductResponse(createdProduct), nil
+	return createdProduct, nil
 }
 
 func (s *service) GetByID(ctx context.Context, id uint) (*ProductResponse, error) {
@@ -152,11 +275,18 @@ func (s *service) GetByID(ctx context.Context, id uint) (*ProductResponse, error
 	if err != nil {
 		return nil, errors.ErrRecordNotFound
 	}
-	return ToProductResponse(product), nil
+	return s.toResponse(ctx, product), nil
+}
+
+// toResponse converts a product and adds the values that need extra queries
+func (s *service) toResponse(ctx context.Context, product *Product) *ProductResponse {
+	responses := []ProductResponse{*ToProductResponse(product)}
+	s.attachLowestPrices(ctx, responses)
+	return &responses[0]
 }
 
-func (s *service) GetAll(ctx context.Context) ([]ProductResponse, error) {
-	products, err := s.repo.GetAll(ctx)
+func (s *service) GetAll(ctx context.Context, filter ProductFilter) ([]ProductResponse, error) {
+	products, err := s.repo.GetAll(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -165,6 +295,7 @@ func (s *service) GetAll(ctx context.Context) ([]ProductResponse, error) {
 	for _, p := range products {
 		responses = append(responses, *ToProductResponse(&p))
 	}
+	s.attachLowestPrices(ctx, responses)
 	return responses, nil
 }
 
@@ -174,10 +305,6 @@ func (s *service) Update(ctx context.Context, id uint, req UpdateProductRequest)
 		return nil, errors.ErrRecordNotFound
 	}
 
-	if req.Name != "" {
-		product.Name = req.Name
-		product.Slug = generateSlug(req.Name)
-	}
 	if req.Description != "" {
 		product.Description = req.Description
 	}
@@ -188,25 +315,1191 @@ func (s *service) Update(ctx context.Context, id uint, req UpdateProductRequest)
 		product.BrandID = req.BrandID
 	}
 
-	if err := s.repo.Update(ctx, product); err != nil {
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		if req.Name != "" && req.Name != product.Name {
+			// Renaming changes the URL; the old slug is kept for redirects
+			newSlug, err := s.slugs.Unique(tx, slug.EntityProduct, slug.Make(req.Name), product.ID)
+			if err != nil {
+				return fmt.Errorf("failed to generate slug: %w", err)
+			}
+			if err := s.slugs.Rename(tx, slug.EntityProduct, product.ID, product.Slug, newSlug); err != nil {
+				return fmt.Errorf("failed to record slug history: %w", err)
+			}
+			product.Name = req.Name
+			product.Slug = newSlug
+		}
+		return tx.Omit(clause.Associations).Save(product).Error
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return ToProductResponse(product), nil
 }
 
+// Delete moves a product to trash; images are kept until it is purged
 func (s *service) Delete(ctx context.Context, id uint) error {
-	product, err := s.repo.GetByID(ctx, id)
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return errors.ErrRecordNotFound
+	}
+
+	return s.repo.Delete(ctx, id)
+}
+
+// GetTrash lists soft-deleted products
+func (s *service) GetTrash(ctx context.Context) ([]ProductResponse, error) {
+	products, err := s.repo.GetTrashed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []ProductResponse
+	for _, p := range products {
+		responses = append(responses, *ToProductResponse(&p))
+	}
+	return responses, nil
+}
+
+func (s *service) GetTrashedByID(ctx context.Context, id uint) (*ProductResponse, error) {
+	product, err := s.repo.GetTrashedByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	return ToProductResponse(product), nil
+}
+
+// Restore brings a product back from trash within the retention window
+func (s *service) Restore(ctx context.Context, id uint) (*ProductResponse, error) {
+	product, err := s.repo.GetTrashedByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	if time.Since(product.DeletedAt.Time) > s.trashRetention {
+		return nil, errors.ErrRestoreExpired
+	}
+
+	if err := s.repo.Restore(ctx, id); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Purge permanently deletes a trashed product and its Cloudinary images
+func (s *service) Purge(ctx context.Context, id uint) error {
+	product, err := s.repo.GetTrashedByID(ctx, id)
 	if err != nil {
 		return errors.ErrRecordNotFound
 	}
+	return s.purge(ctx, product)
+}
+
+// PurgeExpired permanently deletes products whose retention window has passed
+func (s *service) PurgeExpired(ctx context.Context) error {
+	products, err := s.repo.GetTrashedBefore(ctx, time.Now().Add(-s.trashRetention))
+	if err != nil {
+		return err
+	}
+
+	for i := range products {
+		err := s.purge(ctx, &products[i])
+		if err == errors.ErrVariantReserved {
+			// Tried again on the next run, once its orders are settled
+			log.Printf("Purge: product %d still has stock reserved for orders, kept in trash", products[i].ID)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to purge product %d: %w", products[i].ID, err)
+		}
+	}
+	return nil
+}
+
+// purge permanently deletes a product with everything keyed by it
+// ErrVariantReserved while stock is held or backordered for an order.
+func (s *service) purge(ctx context.Context, product *Product) error {
+	err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+
+		// Locked like in Reserve, so no order can take its stock meanwhile
+		var variants []ProductVariant
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ?", product.ID).Find(&variants).Error
+		if err != nil {
+			return err
+		}
+		var reserved int64
+		err = tx.Model(&StockReservation{}).
+			Where("product_id = ? AND (status = ? OR (status = ? AND backordered = ?))", product.ID, ReservationHeld, ReservationCommitted, true).
+			Count(&reserved).Error
+		if err != nil {
+			return err
+		}
+		if reserved > 0 {
+			return errors.ErrVariantReserved
+		}
+		for _, v := range variants {
+			if v.Backordered > 0 {
+				return errors.ErrVariantReserved
+			}
+		}
+
+		// These have no foreign key. Carts and wishlists import this module, so their
+		// tables are cleared by name. Order items keep their snapshot.
+		for _, model := range []interface{}{&StockReservation{}, &VariantStock{}, &StockMovement{}, &PriceHistory{}} {
+			if err := tx.Where("product_id = ?", product.ID).Delete(model).Error; err != nil {
+				return fmt.Errorf("failed to delete %T rows: %w", model, err)
+			}
+		}
+		for _, table := range []string{"cart_items", "wishlist_items"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE product_id = ?", product.ID).Error; err != nil {
+				return fmt.Errorf("failed to delete %s rows: %w", table, err)
+			}
+		}
+
+		// Options, variants and images cascade
+		return tx.Unscoped().Delete(&Product{}, product.ID).Error
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.slugs.Forget(ctx, slug.EntityProduct, product.ID); err != nil {
+		return err
+	}
 
-	// Delete images from Cloudinary
+	// Delete images from Cloudinary only once the product is gone for good
 	for _, img := range product.Images {
-		if img.ImagePublicID != "" {
-			_ = s.cloudinary.Delete(ctx, img.ImagePublicID)
+		if img.ImagePublicID == "" {
+			continue
+		}
+		if err := s.cloudinary.Delete(ctx, img.ImagePublicID); err != nil {
+			log.Printf("Purge: delete image %s of product %d failed: %v", img.ImagePublicID, product.ID, err)
 		}
 	}
+	return nil
+}
 
-	return s.repo.Delete(ctx, id)
+// GenerateVariants creates every missing option combination of a product
+func (s *service) GenerateVariants(ctx context.Context, id uint, req GenerateVariantsRequest, categoryName string) (*ProductResponse, error) {
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	if len(product.Options) == 0 {
+		return nil, errors.ErrProductHasNoOptions
+	}
+
+	counts := make([]int, len(product.Options))
+	for i, opt := range product.Options {
+		counts[i] = len(opt.Values)
+	}
+	if matrixSize(counts...) > maxVariants {
+		return nil, errors.ErrTooManyVariants
+	}
+
+	existing := make(map[string]bool)
+	for _, v := range product.Variants {
+		if v.OptionKey != "" {
+			existing[v.OptionKey] = true
+		}
+	}
+	var missing [][]ProductOptionValue
+	for _, combo := range generateVariantMatrix(product.Options) {
+		if !existing[optionKey(combo)] {
+			missing = append(missing, combo)
+		}
+	}
+	if len(product.Variants)+len(missing) > maxVariants {
+		return nil, errors.ErrTooManyVariants
+	}
+
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		for _, combo := range missing {
+			if _, err := createVariant(tx, product, categoryName, req.Price, req.Stock, "", combo); err != nil {
+				return err
+			}
+		}
+		return syncTotalStock(tx, product.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// CheckReadiness reports whether a product can be published
+func (s *service) CheckReadiness(ctx context.Context, id uint) (*ReadinessResponse, error) {
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	issues := publishReadinessIssues(product)
+	return &ReadinessResponse{Ready: len(issues) == 0, Issues: issues}, nil
+}
+
+// Publish makes a product visible on the storefront immediately
+func (s *service) Publish(ctx context.Context, id uint) (*ProductResponse, error) {
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	if len(publishReadinessIssues(product)) > 0 {
+		return nil, errors.ErrProductNotReady
+	}
+
+	now := time.Now()
+	err = s.repo.UpdateFields(ctx, id, map[string]interface{}{
+		"status":        StatusPublished,
+		"publish_at":    nil,
+		"published_at":  now,
+		"publish_error": "",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Unpublish takes a product back to draft, clearing any schedule
+func (s *service) Unpublish(ctx context.Context, id uint) (*ProductResponse, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	err := s.repo.UpdateFields(ctx, id, map[string]interface{}{
+		"status":       StatusDraft,
+		"publish_at":   nil,
+		"unpublish_at": nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Archive hides a product from the storefront without deleting it
+func (s *service) Archive(ctx context.Context, id uint) (*ProductResponse, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	err := s.repo.UpdateFields(ctx, id, map[string]interface{}{
+		"status":       StatusArchived,
+		"publish_at":   nil,
+		"unpublish_at": nil,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Schedule sets publish/unpublish times processed by the Scheduler
+// A future publish time moves the product to scheduled; readiness is checked
+// now and again when the scheduler publishes it.
+func (s *service) Schedule(ctx context.Context, id uint, req ScheduleProductRequest) (*ProductResponse, error) {
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	now := time.Now()
+	if req.PublishAt != nil && !req.PublishAt.After(now) {
+		return nil, errors.ErrInvalidSchedule
+	}
+	if req.UnpublishAt != nil {
+		if !req.UnpublishAt.After(now) {
+			return nil, errors.ErrInvalidSchedule
+		}
+		if req.PublishAt != nil && !req.UnpublishAt.After(*req.PublishAt) {
+			return nil, errors.ErrInvalidSchedule
+		}
+	}
+
+	fields := map[string]interface{}{
+		"publish_at":    req.PublishAt,
+		"unpublish_at":  req.UnpublishAt,
+		"publish_error": "",
+	}
+	if req.PublishAt != nil {
+		if len(publishReadinessIssues(product)) > 0 {
+			return nil, errors.ErrProductNotReady
+		}
+		// Scheduling a live product takes it offline until the new publish time
+		fields["status"] = StatusScheduled
+	} else if product.Status == StatusScheduled {
+		fields["status"] = StatusDraft
+	}
+
+	if err := s.repo.UpdateFields(ctx, id, fields); err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// GetPublished lists products visible on the storefront
+func (s *service) GetPublished(ctx context.Context, filter ProductFilter) ([]ProductResponse, error) {
+	filter.Statuses = []ProductStatus{StatusPublished}
+	return s.GetAll(ctx, filter)
+}
+
+// GetPublishedBySlug returns a storefront product; unpublished products are not found
+func (s *service) GetPublishedBySlug(ctx context.Context, productSlug string) (*ProductResponse, error) {
+	product, err := s.repo.GetBySlug(ctx, productSlug)
+	if err != nil || product.Status != StatusPublished {
+		return nil, errors.ErrRecordNotFound
+	}
+	return s.toResponse(ctx, product), nil
+}
+
+// ResolveOldSlug returns the current slug of a published product that used oldSlug before
+func (s *service) ResolveOldSlug(ctx context.Context, oldSlug string) (string, error) {
+	id, err := s.slugs.Resolve(ctx, slug.EntityProduct, oldSlug)
+	if err != nil {
+		return "", errors.ErrRecordNotFound
+	}
+
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil || product.Status != StatusPublished {
+		return "", errors.ErrRecordNotFound
+	}
+	return product.Slug, nil
+}
+
+// AddVariant adds a single variant to an existing product
+func (s *service) AddVariant(ctx context.Context, id uint, input VariantInput, categoryName string) (*ProductResponse, error) {
+	product, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	values, err := resolveOptionValues(product.Options, input.Options)
+	if err != nil {
+		return nil, errors.ErrInvalidVariant
+	}
+	if len(values) == 0 && strings.TrimSpace(input.Size) == "" {
+		return nil, errors.ErrInvalidVariant
+	}
+
+	key := optionKey(values)
+	for _, v := range product.Variants {
+		if key != "" && v.OptionKey == key {
+			return nil, errors.ErrDuplicateVariant
+		}
+	}
+	if len(product.Variants) >= maxVariants {
+		return nil, errors.ErrTooManyVariants
+	}
+
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		if _, err := createVariant(tx, product, categoryName, input.Price, input.Stock, input.Size, values); err != nil {
+			return err
+		}
+		return syncTotalStock(tx, product.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// UpdateVariant changes price, stock or size of a variant
+// Price changes are recorded in the price history with actor and req.Reason; a new
+// stock count is recorded in the stock ledger as an adjustment by the difference.
+func (s *service) UpdateVariant(ctx context.Context, id, variantID uint, req UpdateVariantRequest, actor string) (*ProductResponse, error) {
+	variant, err := s.repo.GetVariantByID(ctx, id, variantID)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	before := *variant
+
+	updates := make(map[string]interface{})
+	if req.Price != nil {
+		// A running or scheduled sale must stay below the regular price
+		if !req.Price.IsValidAmount() || (variant.SalePrice != nil && !variant.SalePrice.LessThan(*req.Price)) {
+			return nil, errors.ErrInvalidPrice
+		}
+		updates["price"] = *req.Price
+		variant.Price = *req.Price
+	}
+	if req.CompareAtPrice != nil {
+		if req.CompareAtPrice.IsZero() {
+			updates["compare_at_price"] = nil
+		} else if !req.CompareAtPrice.IsValidAmount() || !req.CompareAtPrice.GreaterThan(variant.Price) {
+			return nil, errors.ErrInvalidPrice
+		} else {
+			updates["compare_at_price"] = *req.CompareAtPrice
+		}
+	}
+	if req.Size != nil {
+		updates["size"] = *req.Size
+	}
+	if req.ReorderThreshold != nil {
+		updates["reorder_threshold"] = *req.ReorderThreshold
+	}
+
+	if len(updates) > 0 || req.Stock != nil {
+		err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+			tx = tx.WithContext(ctx)
+			if len(updates) > 0 {
+				if err := tx.Model(variant).Updates(updates).Error; err != nil {
+					return fmt.Errorf("failed to update variant: %w", err)
+				}
+				if err := logPriceChange(tx, &before, variant, PriceManual, actor, req.Reason); err != nil {
+					return err
+				}
+			}
+			if req.Stock == nil {
+				return nil
+			}
+
+			warehouseID, err := resolveWarehouse(tx, req.WarehouseID)
+			if err != nil {
+				return err
+			}
+			// Lock the variant before reading the count so a concurrent checkout is not overwritten
+			err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&ProductVariant{}, variantID).Error
+			if err != nil {
+				return err
+			}
+			var current VariantStock
+			err = tx.Where("variant_id = ? AND warehouse_id = ?", variantID, warehouseID).Limit(1).Find(&current).Error
+			if err != nil {
+				return err
+			}
+			reason := req.Reason
+			if reason == "" {
+				reason = "stock count"
+			}
+			return moveStock(tx, warehouseID, id, variantID, MovementAdjustment, *req.Stock-current.Stock, reason, actor, nil)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// SetSale puts a variant on sale, now or within a window
+// The scheduler starts and ends windowed sales; one that is already running applies immediately.
+func (s *service) SetSale(ctx context.Context, id, variantID uint, req SetSaleRequest, actor string) (*ProductResponse, error) {
+	variant, err := s.repo.GetVariantByID(ctx, id, variantID)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	before := *variant
+
+	if !req.SalePrice.IsValidAmount() || !req.SalePrice.LessThan(variant.Price) {
+		return nil, errors.ErrInvalidPrice
+	}
+	now := time.Now()
+	if req.EndsAt != nil {
+		if !req.EndsAt.After(now) {
+			return nil, errors.ErrInvalidSalePeriod
+		}
+		if req.StartsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+			return nil, errors.ErrInvalidSalePeriod
+		}
+	}
+
+	variant.SalePrice = req.SalePrice
+	variant.SaleStartsAt = req.StartsAt
+	variant.SaleEndsAt = req.EndsAt
+	variant.SaleActive = variant.saleRunningAt(now)
+
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		err := tx.Model(variant).Updates(map[string]interface{}{
+			"sale_price":     variant.SalePrice,
+			"sale_starts_at": variant.SaleStartsAt,
+			"sale_ends_at":   variant.SaleEndsAt,
+			"sale_active":    variant.SaleActive,
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update sale: %w", err)
+		}
+		return logPriceChange(tx, &before, variant, PriceSaleStarted, actor, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// ClearSale removes the sale of a variant and reverts it to the regular price
+func (s *service) ClearSale(ctx context.Context, id, variantID uint, actor string) (*ProductResponse, error) {
+	variant, err := s.repo.GetVariantByID(ctx, id, variantID)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		return endSale(tx.WithContext(ctx), variant, actor)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// SetBackorder configures whether a variant can be ordered beyond its stock
+func (s *service) SetBackorder(ctx context.Context, id, variantID uint, req SetBackorderRequest) (*ProductResponse, error) {
+	variant, err := s.repo.GetVariantByID(ctx, id, variantID)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	updates := map[string]interface{}{
+		"backorder_mode":  req.Mode,
+		"backorder_limit": req.Limit,
+		"available_at":    req.AvailableAt,
+	}
+	switch req.Mode {
+	case BackorderNone:
+		updates["backorder_limit"] = 0
+		updates["available_at"] = nil
+	case BackorderPreorder:
+		if req.Limit == 0 || req.AvailableAt == nil {
+			return nil, errors.ErrInvalidBackorder
+		}
+	default:
+		if req.Limit == 0 {
+			return nil, errors.ErrInvalidBackorder
+		}
+	}
+
+	if err := s.repo.UpdateVariantFields(ctx, variant.ID, updates); err != nil {
+		return nil, fmt.Errorf("failed to update backorder settings: %w", err)
+	}
+	return s.GetByID(ctx, id)
+}
+
+// GetPriceHistory lists the price changes of a variant, newest first
+func (s *service) GetPriceHistory(ctx context.Context, id, variantID uint) ([]PriceHistory, error) {
+	if _, err := s.repo.GetVariantByID(ctx, id, variantID); err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	return s.repo.GetPriceHistory(ctx, variantID)
+}
+
+// MoveStock records a manual stock movement and applies it to the variant
+func (s *service) MoveStock(ctx context.Context, id, variantID uint, req StockMovementRequest, actor string) (*ProductResponse, error) {
+	quantity := req.Quantity
+	switch req.Type {
+	case MovementReceipt, MovementReturn:
+		if quantity < 0 {
+			return nil, errors.ErrInvalidStockMovement
+		}
+	case MovementDamage:
+		if quantity < 0 {
+			return nil, errors.ErrInvalidStockMovement
+		}
+		quantity = -quantity
+	case MovementAdjustment:
+	default:
+		return nil, errors.ErrInvalidStockMovement
+	}
+
+	err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		warehouseID, err := resolveWarehouse(tx, req.WarehouseID)
+		if err != nil {
+			return err
+		}
+		return moveStock(tx, warehouseID, id, variantID, req.Type, quantity, req.Reason, actor, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// TransferStock moves stock of a variant from one warehouse to another
+// Both legs are written to the ledger in one transaction, so the variant total is unchanged.
+func (s *service) TransferStock(ctx context.Context, id, variantID uint, req TransferStockRequest, actor string) (*ProductResponse, error) {
+	if req.FromWarehouseID == req.ToWarehouseID {
+		return nil, errors.ErrInvalidTransfer
+	}
+
+	err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		for _, warehouseID := range []uint{req.FromWarehouseID, req.ToWarehouseID} {
+			if _, err := resolveWarehouse(tx, &warehouseID); err != nil {
+				if err == errors.ErrUnknownWarehouse {
+					return errors.ErrInvalidTransfer
+				}
+				return err
+			}
+		}
+		reason := req.Reason
+		if reason == "" {
+			reason = "transfer"
+		}
+		if err := moveStock(tx, req.FromWarehouseID, id, variantID, MovementTransfer, -req.Quantity, reason, actor, nil); err != nil {
+			return err
+		}
+		return moveStock(tx, req.ToWarehouseID, id, variantID, MovementTransfer, req.Quantity, reason, actor, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// GetStockLevels lists the stock of a variant per warehouse
+func (s *service) GetStockLevels(ctx context.Context, id, variantID uint) ([]StockLevelResponse, error) {
+	if _, err := s.repo.GetVariantByID(ctx, id, variantID); err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	return s.repo.GetStockLevels(ctx, variantID)
+}
+
+// GetLowStock lists the variants at or below their reorder threshold
+func (s *service) GetLowStock(ctx context.Context) ([]LowStockItem, error) {
+	return s.repo.GetLowStock(ctx)
+}
+
+// GetStockMovements lists the stock ledger of a variant, newest first
+func (s *service) GetStockMovements(ctx context.Context, id, variantID uint) ([]StockMovement, error) {
+	if _, err := s.repo.GetVariantByID(ctx, id, variantID); err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	return s.repo.GetStockMovements(ctx, variantID)
+}
+
+// attachLowestPrices fills LowestPrice30d of the variants that are on sale
+// Failures only leave the value out; it is informational.
+func (s *service) attachLowestPrices(ctx context.Context, responses []ProductResponse) {
+	var ids []uint
+	for _, p := range responses {
+		for _, v := range p.Variants {
+			if v.OnSale {
+				ids = append(ids, v.ID)
+			}
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	lowest, err := s.repo.GetLowestPrices(ctx, ids, time.Now().AddDate(0, 0, -lowestPriceDays))
+	if err != nil {
+		return
+	}
+	for i := range responses {
+		for j := range responses[i].Variants {
+			v := &responses[i].Variants[j]
+			if price, ok := lowest[v.ID]; ok && v.OnSale {
+				v.LowestPrice30d = &price
+			}
+		}
+	}
+}
+
+// DeleteVariant removes a variant; the last variant of a product cannot be removed
+// Neither can one with stock held for unconfirmed orders or owed to backorders.
+// Stock left in warehouses is written off as an adjustment so the ledger still
+// adds up, and the per-warehouse levels go with the variant.
+func (s *service) DeleteVariant(ctx context.Context, id, variantID uint, actor string) (*ProductResponse, error) {
+	variants, err := s.repo.GetVariantsByProductID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	for _, v := range variants {
+		if v.ID == variantID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.ErrRecordNotFound
+	}
+	if len(variants) == 1 {
+		return nil, errors.ErrLastVariant
+	}
+
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+
+		// Locked like in Reserve, so no order can take the variant meanwhile
+		var variant ProductVariant
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND product_id = ?", variantID, id).
+			First(&variant).Error
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrRecordNotFound
+		}
+		if err != nil {
+			return err
+		}
+		var reserved int64
+		err = tx.Model(&StockReservation{}).
+			Where("variant_id = ? AND (status = ? OR (status = ? AND backordered = ?))", variantID, ReservationHeld, ReservationCommitted, true).
+			Count(&reserved).Error
+		if err != nil {
+			return err
+		}
+		if reserved > 0 || variant.Backordered > 0 {
+			return errors.ErrVariantReserved
+		}
+
+		var levels []VariantStock
+		if err := tx.Where("variant_id = ? AND stock > 0", variantID).Find(&levels).Error; err != nil {
+			return err
+		}
+		for _, level := range levels {
+			if err := moveStock(tx, level.WarehouseID, id, variantID, MovementAdjustment, -level.Stock, "variant deleted", actor, nil); err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("variant_id = ?", variantID).Delete(&VariantStock{}).Error; err != nil {
+			return fmt.Errorf("failed to delete variant stock levels: %w", err)
+		}
+
+		if err := tx.Delete(&ProductVariant{}, variantID).Error; err != nil {
+			return fmt.Errorf("failed to delete variant: %w", err)
+		}
+		return syncTotalStock(tx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// AddImages uploads new images and appends them after the existing ones
+func (s *service) AddImages(ctx context.Context, id uint, imageFiles []*multipart.FileHeader) (*ProductResponse, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	images, err := s.repo.GetImagesByProductID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(images)+len(imageFiles) > 5 {
+		return nil, errors.ErrTooManyImages
+	}
+
+	uploaded, err := s.uploadImages(ctx, imageFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		for i, result := range uploaded {
+			image := ProductImage{
+				ProductID:     id,
+				ImageURL:      result.URL,
+				ImagePublicID: result.PublicID,
+				DisplayOrder:  len(images) + i + 1,
+			}
+			if err := tx.Create(&image).Error; err != nil {
+				return fmt.Errorf("failed to save image: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		s.deleteUploads(ctx, uploaded)
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// ReplaceImage swaps the file of an image, keeping its display order
+func (s *service) ReplaceImage(ctx context.Context, id, imageID uint, imageFile *multipart.FileHeader) (*ProductResponse, error) {
+	image, err := s.findImage(ctx, id, imageID)
+	if err != nil {
+		return nil, err
+	}
+
+	uploaded, err := s.uploadImages(ctx, []*multipart.FileHeader{imageFile})
+	if err != nil {
+		return nil, err
+	}
+
+	oldPublicID := image.ImagePublicID
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		return tx.WithContext(ctx).Model(image).Updates(map[string]interface{}{
+			"image_url":       uploaded[0].URL,
+			"image_public_id": uploaded[0].PublicID,
+		}).Error
+	})
+	if err != nil {
+		s.deleteUploads(ctx, uploaded)
+		return nil, fmt.Errorf("failed to replace image: %w", err)
+	}
+
+	if oldPublicID != "" {
+		_ = s.cloudinary.Delete(ctx, oldPublicID)
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// DeleteImage removes an image and closes the gap in display order
+func (s *service) DeleteImage(ctx context.Context, id, imageID uint) (*ProductResponse, error) {
+	images, err := s.repo.GetImagesByProductID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted *ProductImage
+	var remaining []ProductImage
+	for i := range images {
+		if images[i].ID == imageID {
+			deleted = &images[i]
+			continue
+		}
+		remaining = append(remaining, images[i])
+	}
+	if deleted == nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	if len(remaining) == 0 {
+		return nil, errors.ErrLastImage
+	}
+
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		if err := tx.Delete(&ProductImage{}, imageID).Error; err != nil {
+			return fmt.Errorf("failed to delete image: %w", err)
+		}
+		return setDisplayOrder(tx, remaining)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Only remove the file once the row is gone
+	if deleted.ImagePublicID != "" {
+		_ = s.cloudinary.Delete(ctx, deleted.ImagePublicID)
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// ReorderImages sets display order 1..n following the given image IDs
+func (s *service) ReorderImages(ctx context.Context, id uint, req ReorderImagesRequest) (*ProductResponse, error) {
+	images, err := s.repo.GetImagesByProductID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) == 0 {
+		return nil, errors.ErrRecordNotFound
+	}
+	if len(req.ImageIDs) != len(images) {
+		return nil, errors.ErrInvalidImageOrder
+	}
+
+	byID := make(map[uint]ProductImage, len(images))
+	for _, img := range images {
+		byID[img.ID] = img
+	}
+
+	ordered := make([]ProductImage, 0, len(images))
+	for _, imageID := range req.ImageIDs {
+		img, ok := byID[imageID]
+		if !ok {
+			return nil, errors.ErrInvalidImageOrder
+		}
+		delete(byID, imageID) // Rejects duplicated IDs
+		ordered = append(ordered, img)
+	}
+
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		return setDisplayOrder(tx.WithContext(ctx), ordered)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// findImage returns an image that belongs to the product
+func (s *service) findImage(ctx context.Context, id, imageID uint) (*ProductImage, error) {
+	images, err := s.repo.GetImagesByProductID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for i := range images {
+		if images[i].ID == imageID {
+			return &images[i], nil
+		}
+	}
+	return nil, errors.ErrRecordNotFound
+}
+
+// uploadImages uploads files to Cloudinary, cleaning up on partial failure
+func (s *service) uploadImages(ctx context.Context, imageFiles []*multipart.FileHeader) ([]*cloudinary.UploadResult, error) {
+	var uploaded []*cloudinary.UploadResult
+	for _, fileHeader := range imageFiles {
+		file, err := fileHeader.Open()
+		if err != nil {
+			s.deleteUploads(ctx, uploaded)
+			return nil, fmt.Errorf("failed to open image file: %w", err)
+		}
+
+		result, err := s.cloudinary.Upload(ctx, file, "products")
+		file.Close()
+		if err != nil {
+			s.deleteUploads(ctx, uploaded)
+			return nil, fmt.Errorf("failed to upload image: %w", err)
+		}
+		uploaded = append(uploaded, result)
+	}
+	return uploaded, nil
+}
+
+// deleteUploads removes files that were uploaded but never saved
+func (s *service) deleteUploads(ctx context.Context, uploaded []*cloudinary.UploadResult) {
+	for _, result := range uploaded {
+		_ = s.cloudinary.Delete(ctx, result.PublicID)
+	}
+}
+
+// setDisplayOrder numbers images 1..n in slice order (display_order is checked to be 1..5)
+func setDisplayOrder(tx *gorm.DB, images []ProductImage) error {
+	for i, img := range images {
+		if err := tx.Model(&ProductImage{}).Where("id = ?", img.ID).Update("display_order", i+1).Error; err != nil {
+			return fmt.Errorf("failed to update display order: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateOptionInputs checks option names and values are present and unique
+func validateOptionInputs(inputs []OptionInput) error {
+	names := make(map[string]bool)
+	for _, opt := range inputs {
+		name := strings.TrimSpace(opt.Name)
+		if name == "" {
+			return fmt.Errorf("option name is required")
+		}
+		if names[strings.ToLower(name)] {
+			return fmt.Errorf("duplicate option: %s", name)
+		}
+		names[strings.ToLower(name)] = true
+
+		if len(opt.Values) == 0 {
+			return fmt.Errorf("option %s needs at least 1 value", name)
+		}
+		values := make(map[string]bool)
+		for _, v := range opt.Values {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				return fmt.Errorf("option %s has an empty value", name)
+			}
+			if values[strings.ToLower(v)] {
+				return fmt.Errorf("option %s has duplicate value: %s", name, v)
+			}
+			values[strings.ToLower(v)] = true
+		}
+	}
+	return nil
+}
+
+// createOptions stores option definitions and their values with SKU codes
+func createOptions(tx *gorm.DB, productID uint, inputs []OptionInput) ([]ProductOption, error) {
+	var options []ProductOption
+	for i, input := range inputs {
+		option := ProductOption{
+			ProductID: productID,
+			Name:      strings.TrimSpace(input.Name),
+			Position:  i + 1,
+		}
+
+		codes := uniqueOptionCodes(input.Values)
+		for j, v := range input.Values {
+			option.Values = append(option.Values, ProductOptionValue{
+				Value:    strings.TrimSpace(v),
+				Code:     codes[j],
+				Position: j + 1,
+			})
+		}
+
+		if err := tx.Create(&option).Error; err != nil {
+			return nil, fmt.Errorf("failed to create option: %w", err)
+		}
+		options = append(options, option)
+	}
+	return options, nil
+}
+
+// resolveOptionValues maps {"Màu sắc": "Đỏ"} to option values, in option order
+// Every option of the product must be given exactly once.
+func resolveOptionValues(options []ProductOption, selected map[string]string) ([]ProductOptionValue, error) {
+	if len(options) == 0 {
+		if len(selected) > 0 {
+			return nil, fmt.Errorf("product has no options")
+		}
+		return nil, nil
+	}
+	if len(selected) != len(options) {
+		return nil, fmt.Errorf("variant must specify a value for each of the %d options", len(options))
+	}
+
+	var values []ProductOptionValue
+	for _, opt := range options {
+		var chosen string
+		var found bool
+		for name, value := range selected {
+			if strings.EqualFold(strings.TrimSpace(name), opt.Name) {
+				chosen, found = strings.TrimSpace(value), true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("missing value for option %s", opt.Name)
+		}
+
+		var match *ProductOptionValue
+		for i := range opt.Values {
+			if strings.EqualFold(opt.Values[i].Value, chosen) {
+				match = &opt.Values[i]
+				break
+			}
+		}
+		if match == nil {
+			return nil, fmt.Errorf("invalid value %q for option %s", chosen, opt.Name)
+		}
+		values = append(values, *match)
+	}
+	return values, nil
+}
+
+// createVariant inserts a variant, links its option values and assigns the SKU
+func createVariant(tx *gorm.DB, product *Product, categoryName string, price money.Money, stock int, size string, values []ProductOptionValue) (*ProductVariant, error) {
+	if !price.IsValidAmount() {
+		return nil, errors.ErrInvalidPrice
+	}
+	variant := ProductVariant{
+		ProductID:    product.ID,
+		Price:        price,
+		Stock:        stock,
+		Size:         size,
+		OptionKey:    optionKey(values),
+		OptionValues: values,
+	}
+	if len(values) > 0 && variant.Size == "" {
+		variant.Size = variantLabel(values)
+	}
+
+	// Create variant to get ID
+	if err := tx.Omit("OptionValues.*").Create(&variant).Error; err != nil {
+		return nil, fmt.Errorf("failed to create variant: %w", err)
+	}
+	if err := logPriceChange(tx, nil, &variant, PriceCreated, "", ""); err != nil {
+		return nil, err
+	}
+	if stock > 0 {
+		warehouseID, err := defaultWarehouseID(tx)
+		if err != nil {
+			return nil, err
+		}
+		level := VariantStock{VariantID: variant.ID, WarehouseID: warehouseID, ProductID: product.ID, Stock: stock}
+		if err := tx.Create(&level).Error; err != nil {
+			return nil, fmt.Errorf("failed to create variant stock: %w", err)
+		}
+		if err := recordMovement(tx, warehouseID, product.ID, variant.ID, MovementReceipt, stock, stock, "initial stock", "", nil); err != nil {
+			return nil, err
+		}
+	}
+
+	// Generate SKU with the new ID
+	sku := generateSKU(categoryName, product.Name, variant.ID, optionCodes(values)...)
+	if err := tx.Model(&variant).Update("sku", sku).Error; err != nil {
+		return nil, fmt.Errorf("failed to update SKU: %w", err)
+	}
+
+	variant.SKU = sku
+	return &variant, nil
+}
+
+// endSale clears the sale of a variant and logs the return to the regular price
+func endSale(tx *gorm.DB, variant *ProductVariant, actor string) error {
+	before := *variant
+	variant.SalePrice = nil
+	variant.SaleStartsAt = nil
+	variant.SaleEndsAt = nil
+	variant.SaleActive = false
+
+	err := tx.Model(variant).Updates(map[string]interface{}{
+		"sale_price":     nil,
+		"sale_starts_at": nil,
+		"sale_ends_at":   nil,
+		"sale_active":    false,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("failed to clear sale: %w", err)
+	}
+	return logPriceChange(tx, &before, variant, PriceSaleEnded, actor, "")
+}
+
+// logPriceChange appends a price history entry when the effective or regular
+// price of a variant changed; before is nil for a new variant.
+func logPriceChange(tx *gorm.DB, before, after *ProductVariant, reason PriceChangeReason, actor, note string) error {
+	entry := PriceHistory{
+		ProductID:    after.ProductID,
+		VariantID:    after.ID,
+		Price:        after.EffectivePrice(),
+		RegularPrice: after.Price,
+		Reason:       reason,
+		Note:         note,
+		Actor:        actor,
+	}
+	if before != nil {
+		if before.EffectivePrice().Equal(entry.Price) && before.Price.Equal(entry.RegularPrice) {
+			return nil
+		}
+		oldPrice := before.EffectivePrice()
+		entry.OldPrice = &oldPrice
+	}
+
+	if err := tx.Create(&entry).Error; err != nil {
+		return fmt.Errorf("failed to record price history: %w", err)
+	}
+	return nil
+}
+
+// syncTotalStock recalculates products.total_stock from the variants table
+// The product row is locked first: two transactions changing different variants
+// of one product would otherwise each miss the other's change.
+func syncTotalStock(tx *gorm.DB, productID uint) error {
+	var product Product
+	err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&product, productID).Error
+	if err != nil {
+		return err
+	}
+
+	var variants []ProductVariant
+	if err := tx.Where("product_id = ?", productID).Find(&variants).Error; err != nil {
+		return err
+	}
+	return tx.Model(&Product{}).Where("id = ?", productID).
+		Update("total_stock", calculateTotalStock(variants)).Error
 }
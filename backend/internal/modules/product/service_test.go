@@ -0,0 +1,101 @@
+package product
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/internal/shared/slug"
+	"go-ecommerce/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+func TestPurge(t *testing.T) {
+	tests := []struct {
+		name     string
+		reserve  bool // An order holds a unit
+		wantErr  error
+		wantGone bool
+	}{
+		{name: "dependent rows are deleted", wantGone: true},
+		{name: "stock held for an order", reserve: true, wantErr: errors.ErrVariantReserved},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openStockDB(t)
+			if err := db.AutoMigrate(&PriceHistory{}, &slug.History{}); err != nil {
+				t.Fatalf("migrate: %v", err)
+			}
+			// Owned by the cart and wishlist modules, which import this one
+			for _, table := range []string{"cart_items", "wishlist_items"} {
+				if err := db.Exec("CREATE TABLE " + table + " (id serial PRIMARY KEY, product_id bigint NOT NULL)").Error; err != nil {
+					t.Fatalf("create %s: %v", table, err)
+				}
+			}
+
+			productID, variantID := seedVariant(t, db, 3)
+			const otherProductID = 999
+			seed := []interface{}{
+				&PriceHistory{ProductID: productID, VariantID: variantID, Price: money.VNDOf(150000), RegularPrice: money.VNDOf(150000), Reason: PriceCreated},
+				&StockReservation{OrderID: 1, ProductID: productID, VariantID: variantID, Quantity: 1, Status: ReservationReleased, ExpiresAt: time.Now()},
+			}
+			for _, row := range seed {
+				if err := db.Create(row).Error; err != nil {
+					t.Fatalf("seed %T: %v", row, err)
+				}
+			}
+			for _, table := range []string{"cart_items", "wishlist_items"} {
+				db.Exec("INSERT INTO "+table+" (product_id) VALUES (?), (?)", productID, otherProductID)
+			}
+			if tt.reserve {
+				err := db.Transaction(func(tx *gorm.DB) error {
+					_, _, err := NewStockUpdater().Reserve(tx, 2, "", productID, variantID, 1, time.Now().Add(time.Hour))
+					return err
+				})
+				if err != nil {
+					t.Fatalf("reserve: %v", err)
+				}
+			}
+			if err := db.Delete(&Product{}, productID).Error; err != nil {
+				t.Fatalf("trash product: %v", err)
+			}
+
+			svc := NewService(NewRepository(db), nil, slug.NewRegistry(db), 30*24*time.Hour)
+			if err := svc.Purge(context.Background(), productID); err != tt.wantErr {
+				t.Fatalf("Purge: err = %v, want %v", err, tt.wantErr)
+			}
+
+			tables := []string{
+				"products", "product_variants", "product_options", "product_images",
+				"variant_stocks", "stock_reservations", "product_stock_movements", "product_price_histories",
+				"cart_items", "wishlist_items",
+			}
+			for _, table := range tables {
+				column := "product_id"
+				if table == "products" {
+					column = "id"
+				}
+				var count int64
+				if err := db.Table(table).Where(column+" = ?", productID).Count(&count).Error; err != nil {
+					t.Fatalf("count %s: %v", table, err)
+				}
+				if tt.wantGone && count != 0 {
+					t.Errorf("%s has %d rows of the purged product", table, count)
+				}
+				if !tt.wantGone && table != "product_options" && table != "product_images" && count == 0 {
+					t.Errorf("%s rows were deleted although the purge was refused", table)
+				}
+			}
+			for _, table := range []string{"cart_items", "wishlist_items"} {
+				var count int64
+				db.Table(table).Where("product_id = ?", otherProductID).Count(&count)
+				if count != 1 {
+					t.Errorf("%s of another product: %d rows, want 1", table, count)
+				}
+			}
+		})
+	}
+}
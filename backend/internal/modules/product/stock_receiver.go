@@ -0,0 +1,19 @@
+package product
+
+import (
+	"gorm.io/gorm"
+)
+
+// StockReceiver books goods received from suppliers into the stock ledger
+// It works inside the caller's transaction so stock never drifts from receipts.
+type StockReceiver struct{}
+
+func NewStockReceiver() *StockReceiver {
+	return &StockReceiver{}
+}
+
+// Receive adds quantity units of a variant to a warehouse as a receipt
+// Returns ErrRecordNotFound when the variant no longer exists.
+func (r *StockReceiver) Receive(tx *gorm.DB, warehouseID, productID, variantID uint, quantity int, reason, actor string) error {
+	return moveStock(tx, warehouseID, productID, variantID, MovementReceipt, quantity, reason, actor, nil)
+}
@@ -0,0 +1,158 @@
+package product
+
+import (
+	"time"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StockUpdater reserves, commits and releases variant stock for orders
+// It works inside the caller's transaction so stock never drifts from orders.
+// Callers reserving several variants should do so in (product ID, variant ID)
+// order, so concurrent checkouts lock rows in the same order and cannot deadlock.
+type StockUpdater struct{}
+
+func NewStockUpdater() *StockUpdater {
+	return &StockUpdater{}
+}
+
+// Reserve takes quantity units of a variant for an order until expiresAt
+// and returns the warehouse that ships them and how many units are backordered.
+// The warehouse serving the shipping province is tried first, then the others by
+// most stock; a line is only split when no warehouse has all of it, and then the
+// rest is backordered if the variant allows it. A line with no stock anywhere
+// is backordered whole and ships from the warehouse serving the province.
+// The decrement is conditional, so two checkouts racing for the last unit
+// cannot both succeed. Returns ErrInsufficientStock when the line cannot be sold.
+// The sale is written to the stock ledger.
+func (u *StockUpdater) Reserve(tx *gorm.DB, orderID uint, province string, productID, variantID uint, quantity int, expiresAt time.Time) (uint, int, error) {
+	preferred, err := servingWarehouseID(tx, province)
+	if err != nil {
+		return 0, 0, err
+	}
+	byPreference := clause.OrderBy{Expression: clause.Expr{SQL: "warehouse_id = ? DESC, stock DESC, warehouse_id ASC", Vars: []interface{}{preferred}, WithoutParentheses: true}}
+	reservation := StockReservation{
+		OrderID:   orderID,
+		ProductID: productID,
+		VariantID: variantID,
+		Quantity:  quantity,
+		Status:    ReservationHeld,
+		ExpiresAt: expiresAt,
+	}
+
+	var candidates []uint
+	err = tx.Model(&VariantStock{}).
+		Where("variant_id = ? AND stock >= ?", variantID, quantity).
+		Order(byPreference).
+		Pluck("warehouse_id", &candidates).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, warehouseID := range candidates {
+		err := moveStock(tx, warehouseID, productID, variantID, MovementSale, -quantity, "order placed", ActorSystem, &orderID)
+		if err == errors.ErrInsufficientStock {
+			continue // Taken by a concurrent checkout since the candidates were read
+		}
+		if err == errors.ErrRecordNotFound {
+			return 0, 0, errors.ErrInsufficientStock // Variant deleted since it was added to the cart
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+
+		reservation.WarehouseID = warehouseID
+		return warehouseID, 0, tx.Create(&reservation).Error
+	}
+
+	// No warehouse has it all: take what the best one has and backorder the rest.
+	// With the variant locked the levels cannot change under us.
+	var variant ProductVariant
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND product_id = ?", variantID, productID).
+		First(&variant).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, 0, errors.ErrInsufficientStock
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	var level VariantStock
+	result := tx.Where("variant_id = ? AND stock > 0", variantID).Order(byPreference).Limit(1).Find(&level)
+	if result.Error != nil {
+		return 0, 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		level = VariantStock{WarehouseID: preferred}
+	}
+	taken := min(level.Stock, quantity)
+	owed := quantity - taken
+	if owed > variant.BackorderRoom() {
+		return 0, 0, errors.ErrInsufficientStock
+	}
+
+	if taken > 0 {
+		err := moveStock(tx, level.WarehouseID, productID, variantID, MovementSale, -taken, "order placed", ActorSystem, &orderID)
+		if err != nil {
+			return 0, 0, err
+		}
+		held := reservation
+		held.WarehouseID = level.WarehouseID
+		held.Quantity = taken
+		if err := tx.Create(&held).Error; err != nil {
+			return 0, 0, err
+		}
+	}
+
+	reservation.Quantity = owed
+	reservation.Backordered = true
+	if err := tx.Create(&reservation).Error; err != nil {
+		return 0, 0, err
+	}
+	err = tx.Model(&ProductVariant{}).Where("id = ?", variantID).
+		UpdateColumn("backordered", gorm.Expr("backordered + ?", owed)).Error
+	return level.WarehouseID, owed, err
+}
+
+// Commit marks the held reservations of an order as sold
+func (u *StockUpdater) Commit(tx *gorm.DB, orderID uint) error {
+	return tx.Model(&StockReservation{}).
+		Where("order_id = ? AND status = ?", orderID, ReservationHeld).
+		Update("status", ReservationCommitted).Error
+}
+
+// Release puts the stock of an order's reservations back through the ledger
+// Releasing twice is a no-op. Variants deleted in the meantime are skipped.
+// Backordered units are released first, so the stock put back is not
+// allocated to the order being cancelled.
+func (u *StockUpdater) Release(tx *gorm.DB, orderID uint) error {
+	var reservations []StockReservation
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("order_id = ? AND status IN ?", orderID, []ReservationStatus{ReservationHeld, ReservationCommitted}).
+		Order("backordered DESC, product_id ASC, variant_id ASC").
+		Find(&reservations).Error
+	if err != nil {
+		return err
+	}
+
+	for _, r := range reservations {
+		if r.Backordered {
+			err = tx.Model(&ProductVariant{}).Where("id = ?", r.VariantID).
+				UpdateColumn("backordered", gorm.Expr("GREATEST(backordered - ?, 0)", r.Quantity)).Error
+		} else {
+			err = moveStock(tx, r.WarehouseID, r.ProductID, r.VariantID, MovementSale, r.Quantity, "order cancelled", ActorSystem, &orderID)
+		}
+		if err != nil && err != errors.ErrRecordNotFound {
+			return err
+		}
+
+		err = tx.Model(&StockReservation{}).Where("id = ?", r.ID).Update("status", ReservationReleased).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package product
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go-ecommerce/internal/database/dbtest"
+	"go-ecommerce/internal/modules/warehouse"
+	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/pkg/money"
+
+	"gorm.io/gorm"
+)
+
+func TestReserveDoesNotOversell(t *testing.T) {
+	tests := []struct {
+		name   string
+		levels []int // Stock per warehouse, the first one is the default
+		want   int   // Orders that get their unit
+	}{
+		{name: "last unit", levels: []int{1}, want: 1},
+		{name: "one unit in each of two warehouses", levels: []int{1, 1}, want: 2},
+	}
+
+	const buyers = 20
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openStockDB(t)
+			productID, variantID := seedVariant(t, db, tt.levels...)
+
+			updater := NewStockUpdater()
+			expiresAt := time.Now().Add(15 * time.Minute)
+			results := make([]error, buyers)
+			start := make(chan struct{})
+			var wg sync.WaitGroup
+			for i := range buyers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					<-start
+					results[i] = db.Transaction(func(tx *gorm.DB) error {
+						_, _, err := updater.Reserve(tx, uint(i+1), "", productID, variantID, 1, expiresAt)
+						return err
+					})
+				}()
+			}
+			close(start)
+			wg.Wait()
+
+			succeeded := 0
+			for i, err := range results {
+				switch err {
+				case nil:
+					succeeded++
+				case errors.ErrInsufficientStock:
+				default:
+					t.Errorf("buyer %d: unexpected error: %v", i+1, err)
+				}
+			}
+			if succeeded != tt.want {
+				t.Errorf("%d orders reserved stock, want %d", succeeded, tt.want)
+			}
+
+			var held int64
+			db.Model(&StockReservation{}).Where("variant_id = ? AND status = ?", variantID, ReservationHeld).Count(&held)
+			if held != int64(tt.want) {
+				t.Errorf("%d held reservations, want %d", held, tt.want)
+			}
+			assertStockMatchesLedger(t, db, variantID, 0)
+		})
+	}
+}
+
+func TestReserveBackorder(t *testing.T) {
+	tests := []struct {
+		name          string
+		levels        []int
+		drained       bool   // Stock sold out before the order
+		province      string // Served by K2 when set
+		quantity      int
+		wantWarehouse string
+		wantOwed      int
+	}{
+		{name: "part in stock", levels: []int{1}, quantity: 3, wantWarehouse: "K1", wantOwed: 2},
+		{name: "none in stock ships from the default warehouse", levels: []int{1, 1}, drained: true, quantity: 2, wantWarehouse: "K1", wantOwed: 2},
+		{name: "none in stock ships from the serving warehouse", levels: []int{1, 1}, drained: true, province: "Hà Nội", quantity: 2, wantWarehouse: "K2", wantOwed: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openStockDB(t)
+			productID, variantID := seedVariant(t, db, tt.levels...)
+			err := db.Model(&ProductVariant{}).Where("id = ?", variantID).
+				Updates(map[string]interface{}{"backorder_mode": BackorderAllowed, "backorder_limit": 5}).Error
+			if err != nil {
+				t.Fatalf("allow backorders: %v", err)
+			}
+			warehouses := make(map[string]uint)
+			var rows []warehouse.Warehouse
+			db.Find(&rows)
+			for _, w := range rows {
+				warehouses[w.Code] = w.ID
+			}
+			if tt.province != "" {
+				p := warehouse.Province{WarehouseID: warehouses["K2"], Name: tt.province, Key: warehouse.ProvinceKey(tt.province)}
+				if err := db.Create(&p).Error; err != nil {
+					t.Fatalf("create province: %v", err)
+				}
+			}
+			if tt.drained {
+				err := db.Transaction(func(tx *gorm.DB) error {
+					for i := range tt.levels {
+						id := warehouses[fmt.Sprintf("K%d", i+1)]
+						if err := moveStock(tx, id, productID, variantID, MovementDamage, -tt.levels[i], "damaged", "test", nil); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("drain stock: %v", err)
+				}
+			}
+
+			var warehouseID uint
+			var owed int
+			err = db.Transaction(func(tx *gorm.DB) error {
+				var err error
+				warehouseID, owed, err = NewStockUpdater().Reserve(tx, 1, tt.province, productID, variantID, tt.quantity, time.Now().Add(time.Hour))
+				return err
+			})
+			if err != nil {
+				t.Fatalf("Reserve: %v", err)
+			}
+			if warehouseID != warehouses[tt.wantWarehouse] || owed != tt.wantOwed {
+				t.Errorf("Reserve = warehouse %d, %d owed; want %s (%d), %d owed", warehouseID, owed, tt.wantWarehouse, warehouses[tt.wantWarehouse], tt.wantOwed)
+			}
+
+			var backordered StockReservation
+			if err := db.Where("order_id = ? AND backordered = ?", 1, true).First(&backordered).Error; err != nil {
+				t.Fatalf("load backordered reservation: %v", err)
+			}
+			if backordered.Quantity != tt.wantOwed || backordered.WarehouseID != 0 {
+				t.Errorf("backordered reservation = %d units from warehouse %d, want %d from none yet", backordered.Quantity, backordered.WarehouseID, tt.wantOwed)
+			}
+			assertStockMatchesLedger(t, db, variantID, 0)
+		})
+	}
+}
+
+func openStockDB(t *testing.T) *gorm.DB {
+	return dbtest.Open(t,
+		&Product{}, &ProductOption{}, &ProductOptionValue{}, &ProductVariant{}, &ProductImage{},
+		&StockMovement{}, &StockReservation{}, &VariantStock{},
+		&warehouse.Warehouse{}, &warehouse.Province{},
+	)
+}
+
+// seedVariant creates a product with one variant and receives stock into one
+// warehouse per level through the ledger
+func seedVariant(t *testing.T, db *gorm.DB, levels ...int) (uint, uint) {
+	t.Helper()
+	product := Product{Name: "Áo thun", Slug: "ao-thun", CategoryID: 1, BrandID: 1, Status: StatusPublished}
+	if err := db.Create(&product).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	variant := ProductVariant{ProductID: product.ID, Price: money.VNDOf(150000), Size: "M", SKU: "AT1"}
+	if err := db.Create(&variant).Error; err != nil {
+		t.Fatalf("create variant: %v", err)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for i, stock := range levels {
+			w := warehouse.Warehouse{Code: fmt.Sprintf("K%d", i+1), Name: fmt.Sprintf("Kho %d", i+1), IsDefault: i == 0}
+			if err := tx.Create(&w).Error; err != nil {
+				return err
+			}
+			if err := moveStock(tx, w.ID, product.ID, variant.ID, MovementReceipt, stock, "initial stock", "test", nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("receive stock: %v", err)
+	}
+	return product.ID, variant.ID
+}
+
+// assertStockMatchesLedger checks the variant is at want, no warehouse is below 0,
+// and the ledger adds up to the stock per warehouse and in total
+func assertStockMatchesLedger(t *testing.T, db *gorm.DB, variantID uint, want int) {
+	t.Helper()
+	var variant ProductVariant
+	if err := db.First(&variant, variantID).Error; err != nil {
+		t.Fatalf("load variant: %v", err)
+	}
+	if variant.Stock != want {
+		t.Errorf("product_variants.stock = %d, want %d", variant.Stock, want)
+	}
+
+	var levels []VariantStock
+	if err := db.Where("variant_id = ?", variantID).Find(&levels).Error; err != nil {
+		t.Fatalf("load stock levels: %v", err)
+	}
+	total := 0
+	for _, level := range levels {
+		if level.Stock < 0 {
+			t.Errorf("variant_stocks in warehouse %d is %d", level.WarehouseID, level.Stock)
+		}
+		var ledger int
+		db.Model(&StockMovement{}).
+			Where("variant_id = ? AND warehouse_id = ?", variantID, level.WarehouseID).
+			Select("COALESCE(SUM(quantity), 0)").Scan(&ledger)
+		if ledger != level.Stock {
+			t.Errorf("ledger of warehouse %d sums to %d, stock is %d", level.WarehouseID, ledger, level.Stock)
+		}
+		total += level.Stock
+	}
+	if total != variant.Stock {
+		t.Errorf("variant_stocks sum to %d, product_variants.stock is %d", total, variant.Stock)
+	}
+
+	var ledger int
+	db.Model(&StockMovement{}).Where("variant_id = ?", variantID).
+		Select("COALESCE(SUM(quantity), 0)").Scan(&ledger)
+	if ledger != variant.Stock {
+		t.Errorf("ledger sums to %d, product_variants.stock is %d", ledger, variant.Stock)
+	}
+}
@@ -0,0 +1,76 @@
+package purchasing
+
+import (
+	"context"
+
+	"go-ecommerce/internal/modules/product"
+	"go-ecommerce/internal/modules/warehouse"
+	"go-ecommerce/internal/shared/errors"
+
+	"gorm.io/gorm"
+)
+
+// ProductRepoAdapter adapts product.Repository to VariantGetter
+type ProductRepoAdapter struct {
+	repo product.Repository
+}
+
+func NewProductRepoAdapter(repo product.Repository) *ProductRepoAdapter {
+	return &ProductRepoAdapter{repo: repo}
+}
+
+func (a *ProductRepoAdapter) GetBySKUs(ctx context.Context, skus []string) (map[string]*VariantInfo, error) {
+	variants, err := a.repo.GetVariantsBySKUs(ctx, skus)
+	if err != nil {
+		return nil, err
+	}
+	productIDs := make([]uint, 0, len(variants))
+	for _, v := range variants {
+		productIDs = append(productIDs, v.ProductID)
+	}
+	products, err := a.repo.GetByIDs(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(products))
+	for _, p := range products {
+		names[p.ID] = p.Name
+	}
+
+	res := make(map[string]*VariantInfo, len(variants))
+	for _, v := range variants {
+		name, ok := names[v.ProductID]
+		if !ok {
+			continue // Product in trash
+		}
+		res[v.SKU] = &VariantInfo{ProductID: v.ProductID, VariantID: v.ID, SKU: v.SKU, Name: name, Size: v.Size}
+	}
+	return res, nil
+}
+
+// WarehouseRepoAdapter adapts warehouse.Repository to WarehouseResolver
+type WarehouseRepoAdapter struct {
+	repo warehouse.Repository
+}
+
+func NewWarehouseRepoAdapter(repo warehouse.Repository) *WarehouseRepoAdapter {
+	return &WarehouseRepoAdapter{repo: repo}
+}
+
+func (a *WarehouseRepoAdapter) Resolve(ctx context.Context, id uint) (uint, error) {
+	if id == 0 {
+		w, err := a.repo.GetDefault(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return w.ID, nil
+	}
+	w, err := a.repo.GetByID(ctx, id)
+	if err == gorm.ErrRecordNotFound {
+		return 0, errors.ErrUnknownWarehouse
+	}
+	if err != nil {
+		return 0, err
+	}
+	return w.ID, nil
+}
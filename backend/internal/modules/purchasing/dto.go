@@ -0,0 +1,62 @@
+package purchasing
+
+import (
+	"time"
+
+	"go-ecommerce/pkg/money"
+)
+
+// SupplierRequest - Request body for creating or updating a supplier
+type SupplierRequest struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	ContactName string `json:"contact_name" binding:"max=100"`
+	Email       string `json:"email" binding:"omitempty,email,max=255"`
+	Phone       string `json:"phone" binding:"max=20"`
+	Address     string `json:"address" binding:"max=255"`
+	Note        string `json:"note"`
+}
+
+// PurchaseOrderRequest - Request body for creating or updating a draft purchase order
+// Lines replaces all lines of the purchase order.
+type PurchaseOrderRequest struct {
+	SupplierID  uint                  `json:"supplier_id" binding:"required"`
+	WarehouseID uint                  `json:"warehouse_id"` // Optional, 0 uses the default warehouse
+	ExpectedAt  *time.Time            `json:"expected_at"`
+	Note        string                `json:"note" binding:"max=500"`
+	Lines       []PurchaseLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+// PurchaseLineRequest - One SKU of a purchase order
+type PurchaseLineRequest struct {
+	SKU      string      `json:"sku" binding:"required"`
+	Quantity int         `json:"quantity" binding:"required,min=1"`
+	UnitCost money.Money `json:"unit_cost"`
+}
+
+// ReceiveRequest - Request body for recording goods received
+type ReceiveRequest struct {
+	Note  string               `json:"note" binding:"max=500"`
+	Lines []ReceiveLineRequest `json:"lines" binding:"required,min=1,dive"`
+}
+
+// ReceiveLineRequest - Quantity received for one purchase order line
+type ReceiveLineRequest struct {
+	LineID   uint         `json:"line_id" binding:"required"`
+	Quantity int          `json:"quantity" binding:"required,min=1"`
+	UnitCost *money.Money `json:"unit_cost"` // Optional, the cost agreed on the line
+}
+
+// POFilter - Filters for purchase order listing
+type POFilter struct {
+	Status     POStatus // Empty means any status
+	SupplierID uint     // 0 means any supplier
+}
+
+// VariantInfo - variant a purchase order line refers to
+type VariantInfo struct {
+	ProductID uint
+	VariantID uint
+	SKU       string
+	Name      string
+	Size      string
+}
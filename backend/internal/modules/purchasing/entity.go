@@ -0,0 +1,115 @@
+package purchasing
+
+import (
+	"time"
+
+	"go-ecommerce/pkg/money"
+)
+
+// POStatus enum
+type POStatus string
+
+const (
+	StatusDraft             POStatus = "draft" // Lines can still be changed
+	StatusSent              POStatus = "sent"  // Ordered from the supplier, waiting for goods
+	StatusPartiallyReceived POStatus = "partially_received"
+	StatusClosed            POStatus = "closed" // Fully received, or closed short by staff
+)
+
+// Supplier entity
+type Supplier struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Name        string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"name"`
+	ContactName string    `gorm:"type:varchar(100)" json:"contact_name"`
+	Email       string    `gorm:"type:varchar(255)" json:"email"`
+	Phone       string    `gorm:"type:varchar(20)" json:"phone"`
+	Address     string    `gorm:"type:varchar(255)" json:"address"`
+	Note        string    `gorm:"type:text" json:"note"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (Supplier) TableName() string {
+	return "suppliers"
+}
+
+// PurchaseOrder entity - goods ordered from a supplier for one warehouse
+type PurchaseOrder struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	Number      string     `gorm:"type:varchar(20);index" json:"number"` // PO000042, derived from the ID
+	SupplierID  uint       `gorm:"not null;index" json:"supplier_id"`
+	WarehouseID uint       `gorm:"not null" json:"warehouse_id"` // Receipts are stocked here
+	Status      POStatus   `gorm:"type:varchar(20);not null;index" json:"status"`
+	Note        string     `gorm:"type:varchar(500)" json:"note"`
+	ExpectedAt  *time.Time `json:"expected_at"`
+	SentAt      *time.Time `json:"sent_at"`
+	ClosedAt    *time.Time `json:"closed_at"`
+	CreatedBy   string     `gorm:"type:varchar(36)" json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	Supplier *Supplier           `gorm:"foreignKey:SupplierID" json:"supplier,omitempty"`
+	Lines    []PurchaseOrderLine `gorm:"foreignKey:PurchaseOrderID;constraint:OnDelete:CASCADE" json:"lines,omitempty"`
+	Receipts []GoodsReceipt      `gorm:"foreignKey:PurchaseOrderID;constraint:OnDelete:CASCADE" json:"receipts,omitempty"`
+}
+
+func (PurchaseOrder) TableName() string {
+	return "purchase_orders"
+}
+
+// PurchaseOrderLine entity - one SKU of a purchase order
+// Product name, SKU and size are copied so the PO reads the same after renames.
+type PurchaseOrderLine struct {
+	ID               uint        `gorm:"primaryKey" json:"id"`
+	PurchaseOrderID  uint        `gorm:"not null;index" json:"purchase_order_id"`
+	ProductID        uint        `gorm:"not null" json:"product_id"`
+	VariantID        uint        `gorm:"not null;index" json:"variant_id"`
+	SKU              string      `gorm:"type:varchar(100);not null" json:"sku"`
+	ProductName      string      `gorm:"type:varchar(255)" json:"product_name"`
+	Size             string      `gorm:"type:varchar(100)" json:"size"`
+	Quantity         int         `gorm:"not null;check:quantity > 0" json:"quantity"`
+	ReceivedQuantity int         `gorm:"not null;default:0;check:received_quantity >= 0" json:"received_quantity"`
+	UnitCost         money.Money `gorm:"not null" json:"unit_cost"` // Agreed cost; receipts may record another
+}
+
+func (PurchaseOrderLine) TableName() string {
+	return "purchase_order_lines"
+}
+
+// Outstanding is the quantity still to be received
+func (l *PurchaseOrderLine) Outstanding() int {
+	return l.Quantity - l.ReceivedQuantity
+}
+
+// GoodsReceipt entity - one delivery received against a purchase order
+type GoodsReceipt struct {
+	ID              uint      `gorm:"primaryKey" json:"id"`
+	PurchaseOrderID uint      `gorm:"not null;index" json:"purchase_order_id"`
+	WarehouseID     uint      `gorm:"not null" json:"warehouse_id"`
+	Note            string    `gorm:"type:varchar(500)" json:"note"`
+	ReceivedBy      string    `gorm:"type:varchar(36)" json:"received_by"`
+	CreatedAt       time.Time `json:"created_at"`
+
+	Lines []GoodsReceiptLine `gorm:"foreignKey:GoodsReceiptID;constraint:OnDelete:CASCADE" json:"lines,omitempty"`
+}
+
+func (GoodsReceipt) TableName() string {
+	return "goods_receipts"
+}
+
+// GoodsReceiptLine entity - quantity and actual cost price of one SKU in a receipt
+// Indexed by variant so margins can be computed from the cost of each receipt.
+type GoodsReceiptLine struct {
+	ID                  uint        `gorm:"primaryKey" json:"id"`
+	GoodsReceiptID      uint        `gorm:"not null;index" json:"goods_receipt_id"`
+	PurchaseOrderLineID uint        `gorm:"not null;index" json:"purchase_order_line_id"`
+	ProductID           uint        `gorm:"not null" json:"product_id"`
+	VariantID           uint        `gorm:"not null;index" json:"variant_id"`
+	Quantity            int         `gorm:"not null;check:quantity > 0" json:"quantity"`
+	UnitCost            money.Money `gorm:"not null" json:"unit_cost"`
+	CreatedAt           time.Time   `json:"created_at"`
+}
+
+func (GoodsReceiptLine) TableName() string {
+	return "goods_receipt_lines"
+}
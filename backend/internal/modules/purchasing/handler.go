@@ -0,0 +1,278 @@
+package purchasing
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles supplier and purchase order HTTP requests
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new purchasing handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetSuppliers handles GET /admin/suppliers
+func (h *Handler) GetSuppliers(c *gin.Context) {
+	res, err := h.service.GetSuppliers(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách nhà cung cấp"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// CreateSupplier handles POST /admin/suppliers
+// @Summary Thêm nhà cung cấp
+// @Tags Purchasing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body SupplierRequest true "Nhà cung cấp"
+// @Success 201 {object} Supplier
+// @Router /admin/suppliers [post]
+func (h *Handler) CreateSupplier(c *gin.Context) {
+	var req SupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.CreateSupplier(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Thêm nhà cung cấp thành công",
+		"data":    res,
+	})
+}
+
+// UpdateSupplier handles PUT /admin/suppliers/:id
+func (h *Handler) UpdateSupplier(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req SupplierRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.UpdateSupplier(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cập nhật nhà cung cấp thành công",
+		"data":    res,
+	})
+}
+
+// GetAll handles GET /admin/purchase-orders
+// Optional ?status= and ?supplier_id= filters.
+func (h *Handler) GetAll(c *gin.Context) {
+	filter := POFilter{Status: POStatus(c.Query("status"))}
+	switch filter.Status {
+	case "", StatusDraft, StatusSent, StatusPartiallyReceived, StatusClosed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Trạng thái không hợp lệ"})
+		return
+	}
+	if v := c.Query("supplier_id"); v != "" {
+		supplierID, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "supplier_id không hợp lệ"})
+			return
+		}
+		filter.SupplierID = uint(supplierID)
+	}
+
+	res, err := h.service.GetAll(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách đơn nhập hàng"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// GetByID handles GET /admin/purchase-orders/:id
+func (h *Handler) GetByID(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// Create handles POST /admin/purchase-orders
+// @Summary Tạo đơn nhập hàng
+// @Description Đơn mới ở trạng thái nháp; các dòng tham chiếu SKU của biến thể
+// @Tags Purchasing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body PurchaseOrderRequest true "Đơn nhập hàng"
+// @Success 201 {object} PurchaseOrder
+// @Router /admin/purchase-orders [post]
+func (h *Handler) Create(c *gin.Context) {
+	var req PurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Create(c.Request.Context(), req, c.GetString("userID"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Tạo đơn nhập hàng thành công",
+		"data":    res,
+	})
+}
+
+// Update handles PUT /admin/purchase-orders/:id
+func (h *Handler) Update(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req PurchaseOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Update(c.Request.Context(), id, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cập nhật đơn nhập hàng thành công",
+		"data":    res,
+	})
+}
+
+// Send handles POST /admin/purchase-orders/:id/send
+func (h *Handler) Send(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.Send(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Đã gửi đơn nhập hàng",
+		"data":    res,
+	})
+}
+
+// Receive handles POST /admin/purchase-orders/:id/receipts
+// @Summary Nhận hàng
+// @Description Nhận một phần hoặc toàn bộ; tồn kho tăng qua sổ kho, giá vốn được lưu theo từng lần nhận
+// @Tags Purchasing
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Purchase order ID"
+// @Param request body ReceiveRequest true "Hàng nhận được"
+// @Success 200 {object} PurchaseOrder
+// @Failure 409 {object} map[string]string
+// @Router /admin/purchase-orders/{id}/receipts [post]
+func (h *Handler) Receive(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var req ReceiveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Receive(c.Request.Context(), id, req, c.GetString("userID"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Đã nhận hàng",
+		"data":    res,
+	})
+}
+
+// Close handles POST /admin/purchase-orders/:id/close
+func (h *Handler) Close(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.Close(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Đã đóng đơn nhập hàng",
+		"data":    res,
+	})
+}
+
+func respondError(c *gin.Context, err error) {
+	switch err {
+	case errors.ErrRecordNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy đơn nhập hàng, nhà cung cấp hoặc sản phẩm"})
+	case errors.ErrDuplicateSupplier:
+		c.JSON(http.StatusConflict, gin.H{"error": "Tên nhà cung cấp đã tồn tại"})
+	case errors.ErrUnknownSKU:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "SKU không tồn tại"})
+	case errors.ErrUnknownWarehouse:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Kho không tồn tại"})
+	case errors.ErrDuplicatePOLine, errors.ErrInvalidCost, errors.ErrInvalidReceipt:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.ErrPONotEditable:
+		c.JSON(http.StatusConflict, gin.H{"error": "Chỉ sửa được đơn nhập hàng nháp"})
+	case errors.ErrPOStatus:
+		c.JSON(http.StatusConflict, gin.H{"error": "Không thể chuyển đơn nhập hàng sang trạng thái này"})
+	case errors.ErrOverReceipt:
+		c.JSON(http.StatusConflict, gin.H{"error": "Số lượng nhận vượt quá số lượng còn lại"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+	}
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return 0, false
+	}
+	return uint(id), true
+}
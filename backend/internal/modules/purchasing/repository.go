@@ -0,0 +1,93 @@
+package purchasing
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repository interface
+type Repository interface {
+	// Suppliers
+	GetSuppliers(ctx context.Context) ([]Supplier, error)
+	GetSupplierByID(ctx context.Context, id uint) (*Supplier, error)
+	GetSupplierByName(ctx context.Context, name string) (*Supplier, error)
+	SaveSupplier(ctx context.Context, supplier *Supplier) error
+
+	// Purchase orders
+	GetAll(ctx context.Context, filter POFilter) ([]PurchaseOrder, error)
+	GetByID(ctx context.Context, id uint) (*PurchaseOrder, error)
+
+	// Transaction support
+	WithTransaction(fn func(*gorm.DB) error) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new purchasing repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// WithTransaction executes a function within a transaction
+func (r *repository) WithTransaction(fn func(*gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
+func (r *repository) GetSuppliers(ctx context.Context) ([]Supplier, error) {
+	var suppliers []Supplier
+	err := r.db.WithContext(ctx).Order("name ASC").Find(&suppliers).Error
+	return suppliers, err
+}
+
+func (r *repository) GetSupplierByID(ctx context.Context, id uint) (*Supplier, error) {
+	var supplier Supplier
+	if err := r.db.WithContext(ctx).First(&supplier, id).Error; err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+func (r *repository) GetSupplierByName(ctx context.Context, name string) (*Supplier, error) {
+	var supplier Supplier
+	if err := r.db.WithContext(ctx).Where("LOWER(name) = LOWER(?)", name).First(&supplier).Error; err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+func (r *repository) SaveSupplier(ctx context.Context, supplier *Supplier) error {
+	return r.db.WithContext(ctx).Save(supplier).Error
+}
+
+// GetAll returns purchase orders newest first, without receipts
+func (r *repository) GetAll(ctx context.Context, filter POFilter) ([]PurchaseOrder, error) {
+	var orders []PurchaseOrder
+	query := r.db.WithContext(ctx).
+		Preload("Supplier").
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") })
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.SupplierID != 0 {
+		query = query.Where("supplier_id = ?", filter.SupplierID)
+	}
+	err := query.Order("id DESC").Find(&orders).Error
+	return orders, err
+}
+
+func (r *repository) GetByID(ctx context.Context, id uint) (*PurchaseOrder, error) {
+	var order PurchaseOrder
+	err := r.db.WithContext(ctx).
+		Preload("Supplier").
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Receipts", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		Preload("Receipts.Lines", func(db *gorm.DB) *gorm.DB { return db.Order("id ASC") }).
+		First(&order, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
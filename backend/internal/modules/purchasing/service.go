@@ -0,0 +1,368 @@
+package purchasing
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VariantGetter interface for looking up variants by SKU
+type VariantGetter interface {
+	// GetBySKUs returns the variants found, keyed by SKU; trashed products are missing
+	GetBySKUs(ctx context.Context, skus []string) (map[string]*VariantInfo, error)
+}
+
+// WarehouseResolver interface for checking the warehouse of a purchase order
+type WarehouseResolver interface {
+	// Resolve returns id if the warehouse exists, or the default warehouse for 0
+	Resolve(ctx context.Context, id uint) (uint, error)
+}
+
+// StockReceiver interface for booking received goods into the stock ledger
+type StockReceiver interface {
+	Receive(tx *gorm.DB, warehouseID, productID, variantID uint, quantity int, reason, actor string) error
+}
+
+// Service interface
+type Service interface {
+	// Suppliers
+	GetSuppliers(ctx context.Context) ([]Supplier, error)
+	CreateSupplier(ctx context.Context, req SupplierRequest) (*Supplier, error)
+	UpdateSupplier(ctx context.Context, id uint, req SupplierRequest) (*Supplier, error)
+
+	// Purchase orders
+	GetAll(ctx context.Context, filter POFilter) ([]PurchaseOrder, error)
+	GetByID(ctx context.Context, id uint) (*PurchaseOrder, error)
+	Create(ctx context.Context, req PurchaseOrderRequest, actor string) (*PurchaseOrder, error)
+	Update(ctx context.Context, id uint, req PurchaseOrderRequest) (*PurchaseOrder, error)
+	Send(ctx context.Context, id uint) (*PurchaseOrder, error)
+	Receive(ctx context.Context, id uint, req ReceiveRequest, actor string) (*PurchaseOrder, error)
+	Close(ctx context.Context, id uint) (*PurchaseOrder, error)
+}
+
+type service struct {
+	repo       Repository
+	variants   VariantGetter
+	warehouses WarehouseResolver
+	stock      StockReceiver
+}
+
+// NewService creates a new purchasing service
+func NewService(repo Repository, variants VariantGetter, warehouses WarehouseResolver, stock StockReceiver) Service {
+	return &service{repo: repo, variants: variants, warehouses: warehouses, stock: stock}
+}
+
+func (s *service) GetSuppliers(ctx context.Context) ([]Supplier, error) {
+	return s.repo.GetSuppliers(ctx)
+}
+
+func (s *service) CreateSupplier(ctx context.Context, req SupplierRequest) (*Supplier, error) {
+	supplier := &Supplier{}
+	return s.saveSupplier(ctx, supplier, req)
+}
+
+func (s *service) UpdateSupplier(ctx context.Context, id uint, req SupplierRequest) (*Supplier, error) {
+	supplier, err := s.repo.GetSupplierByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	return s.saveSupplier(ctx, supplier, req)
+}
+
+// saveSupplier applies req and saves; supplier names are unique ignoring case
+func (s *service) saveSupplier(ctx context.Context, supplier *Supplier, req SupplierRequest) (*Supplier, error) {
+	name := strings.TrimSpace(req.Name)
+	if other, err := s.repo.GetSupplierByName(ctx, name); err == nil && other.ID != supplier.ID {
+		return nil, errors.ErrDuplicateSupplier
+	}
+
+	supplier.Name = name
+	supplier.ContactName = req.ContactName
+	supplier.Email = req.Email
+	supplier.Phone = req.Phone
+	supplier.Address = req.Address
+	supplier.Note = req.Note
+	if err := s.repo.SaveSupplier(ctx, supplier); err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+func (s *service) GetAll(ctx context.Context, filter POFilter) ([]PurchaseOrder, error) {
+	return s.repo.GetAll(ctx, filter)
+}
+
+func (s *service) GetByID(ctx context.Context, id uint) (*PurchaseOrder, error) {
+	order, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	return order, nil
+}
+
+// Create adds a draft purchase order
+func (s *service) Create(ctx context.Context, req PurchaseOrderRequest, actor string) (*PurchaseOrder, error) {
+	order := &PurchaseOrder{Status: StatusDraft, CreatedBy: actor}
+	if err := s.apply(ctx, order, req); err != nil {
+		return nil, err
+	}
+
+	err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		if err := tx.Create(order).Error; err != nil {
+			return fmt.Errorf("failed to create purchase order: %w", err)
+		}
+		order.Number = fmt.Sprintf("PO%06d", order.ID)
+		return tx.Model(order).Update("number", order.Number).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, order.ID)
+}
+
+// Update changes a draft purchase order and replaces its lines
+func (s *service) Update(ctx context.Context, id uint, req PurchaseOrderRequest) (*PurchaseOrder, error) {
+	order, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	if order.Status != StatusDraft {
+		return nil, errors.ErrPONotEditable
+	}
+	if err := s.apply(ctx, order, req); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		// Only a draft can change, so a concurrent send wins
+		result := tx.Model(&PurchaseOrder{}).
+			Where("id = ? AND status = ?", id, StatusDraft).
+			Updates(map[string]interface{}{
+				"supplier_id":  order.SupplierID,
+				"warehouse_id": order.WarehouseID,
+				"expected_at":  order.ExpectedAt,
+				"note":         order.Note,
+				"updated_at":   time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrPONotEditable
+		}
+
+		if err := tx.Where("purchase_order_id = ?", id).Delete(&PurchaseOrderLine{}).Error; err != nil {
+			return err
+		}
+		for i := range order.Lines {
+			order.Lines[i].PurchaseOrderID = id
+		}
+		return tx.Create(&order.Lines).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Send marks a draft as ordered from the supplier; from then on goods can be received
+func (s *service) Send(ctx context.Context, id uint) (*PurchaseOrder, error) {
+	now := time.Now()
+	return s.changeStatus(ctx, id, []POStatus{StatusDraft}, map[string]interface{}{"status": StatusSent, "sent_at": now, "updated_at": now})
+}
+
+// Close ends a purchase order; what is still outstanding is not expected anymore
+func (s *service) Close(ctx context.Context, id uint) (*PurchaseOrder, error) {
+	now := time.Now()
+	return s.changeStatus(ctx, id, []POStatus{StatusDraft, StatusSent, StatusPartiallyReceived}, map[string]interface{}{"status": StatusClosed, "closed_at": now, "updated_at": now})
+}
+
+func (s *service) changeStatus(ctx context.Context, id uint, from []POStatus, updates map[string]interface{}) (*PurchaseOrder, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+		result := tx.WithContext(ctx).Model(&PurchaseOrder{}).
+			Where("id = ? AND status IN ?", id, from).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrPOStatus
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// Receive records a delivery and stocks it in the warehouse of the purchase order
+// Receipts can be partial; the order closes itself once every line is fully received.
+// Each line is written to the stock ledger with the PO number as reason.
+func (s *service) Receive(ctx context.Context, id uint, req ReceiveRequest, actor string) (*PurchaseOrder, error) {
+	for _, line := range req.Lines {
+		if line.UnitCost != nil && !line.UnitCost.IsValidAmount() {
+			return nil, errors.ErrInvalidCost
+		}
+	}
+
+	err := s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+
+		// Lock the order so concurrent receipts cannot both take the outstanding quantity
+		var order PurchaseOrder
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, id).Error
+		if err == gorm.ErrRecordNotFound {
+			return errors.ErrRecordNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if order.Status != StatusSent && order.Status != StatusPartiallyReceived {
+			return errors.ErrPOStatus
+		}
+
+		var lines []PurchaseOrderLine
+		if err := tx.Where("purchase_order_id = ?", id).Find(&lines).Error; err != nil {
+			return err
+		}
+		byID := make(map[uint]*PurchaseOrderLine, len(lines))
+		for i := range lines {
+			byID[lines[i].ID] = &lines[i]
+		}
+
+		receipt := GoodsReceipt{PurchaseOrderID: id, WarehouseID: order.WarehouseID, Note: req.Note, ReceivedBy: actor}
+		seen := make(map[uint]bool)
+		for _, r := range req.Lines {
+			line, ok := byID[r.LineID]
+			if !ok || seen[r.LineID] {
+				return errors.ErrInvalidReceipt
+			}
+			seen[r.LineID] = true
+			if r.Quantity > line.Outstanding() {
+				return errors.ErrOverReceipt
+			}
+
+			cost := line.UnitCost
+			if r.UnitCost != nil {
+				cost = *r.UnitCost
+			}
+			line.ReceivedQuantity += r.Quantity
+			receipt.Lines = append(receipt.Lines, GoodsReceiptLine{
+				PurchaseOrderLineID: line.ID,
+				ProductID:           line.ProductID,
+				VariantID:           line.VariantID,
+				Quantity:            r.Quantity,
+				UnitCost:            cost,
+			})
+		}
+		if err := tx.Create(&receipt).Error; err != nil {
+			return fmt.Errorf("failed to create goods receipt: %w", err)
+		}
+
+		// Stock in (product ID, variant ID) order, like checkouts, so they cannot deadlock
+		stocked := append([]GoodsReceiptLine{}, receipt.Lines...)
+		sort.Slice(stocked, func(i, j int) bool {
+			if stocked[i].ProductID != stocked[j].ProductID {
+				return stocked[i].ProductID < stocked[j].ProductID
+			}
+			return stocked[i].VariantID < stocked[j].VariantID
+		})
+		for _, l := range stocked {
+			err := tx.Model(&PurchaseOrderLine{}).Where("id = ?", l.PurchaseOrderLineID).
+				UpdateColumn("received_quantity", byID[l.PurchaseOrderLineID].ReceivedQuantity).Error
+			if err != nil {
+				return err
+			}
+			err = s.stock.Receive(tx, order.WarehouseID, l.ProductID, l.VariantID, l.Quantity, "PO "+order.Number, actor)
+			if err != nil {
+				return err
+			}
+		}
+
+		status := StatusClosed
+		for i := range lines {
+			if lines[i].Outstanding() > 0 {
+				status = StatusPartiallyReceived
+				break
+			}
+		}
+		updates := map[string]interface{}{"status": status, "updated_at": time.Now()}
+		if status == StatusClosed {
+			updates["closed_at"] = time.Now()
+		}
+		return tx.Model(&order).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(ctx, id)
+}
+
+// apply validates req and copies it onto order, resolving SKUs to variants
+func (s *service) apply(ctx context.Context, order *PurchaseOrder, req PurchaseOrderRequest) error {
+	if _, err := s.repo.GetSupplierByID(ctx, req.SupplierID); err != nil {
+		return errors.ErrRecordNotFound
+	}
+	warehouseID, err := s.warehouses.Resolve(ctx, req.WarehouseID)
+	if err != nil {
+		return err
+	}
+
+	skus := make([]string, 0, len(req.Lines))
+	seen := make(map[string]bool)
+	for _, line := range req.Lines {
+		sku := strings.TrimSpace(line.SKU)
+		if seen[sku] {
+			return errors.ErrDuplicatePOLine
+		}
+		seen[sku] = true
+		if !line.UnitCost.IsValidAmount() {
+			return errors.ErrInvalidCost
+		}
+		skus = append(skus, sku)
+	}
+	variants, err := s.variants.GetBySKUs(ctx, skus)
+	if err != nil {
+		return err
+	}
+
+	order.SupplierID = req.SupplierID
+	order.WarehouseID = warehouseID
+	order.ExpectedAt = req.ExpectedAt
+	order.Note = req.Note
+	order.Lines = make([]PurchaseOrderLine, 0, len(req.Lines))
+	for i, line := range req.Lines {
+		variant, ok := variants[skus[i]]
+		if !ok {
+			return errors.ErrUnknownSKU
+		}
+		order.Lines = append(order.Lines, PurchaseOrderLine{
+			ProductID:   variant.ProductID,
+			VariantID:   variant.VariantID,
+			SKU:         variant.SKU,
+			ProductName: variant.Name,
+			Size:        variant.Size,
+			Quantity:    line.Quantity,
+			UnitCost:    line.UnitCost,
+		})
+	}
+	return nil
+}
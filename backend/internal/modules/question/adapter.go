@@ -0,0 +1,26 @@
+package question
+
+import (
+	"context"
+
+	"go-ecommerce/internal/modules/user"
+
+	"github.com/google/uuid"
+)
+
+// UserRepoAdapter adapts user.Repository to UserGetter
+type UserRepoAdapter struct {
+	repo user.Repository
+}
+
+func NewUserRepoAdapter(repo user.Repository) *UserRepoAdapter {
+	return &UserRepoAdapter{repo: repo}
+}
+
+func (a *UserRepoAdapter) GetEmail(ctx context.Context, id uuid.UUID) (string, error) {
+	u, err := a.repo.GetByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return u.Email, nil
+}
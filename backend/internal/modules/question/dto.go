@@ -0,0 +1,69 @@
+package question
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AskRequest - Request body for asking a question
+type AskRequest struct {
+	ProductID uint   `json:"product_id" binding:"required"`
+	Content   string `json:"content" binding:"required,min=5,max=1000"`
+}
+
+// AnswerRequest - Request body for answering a question
+type AnswerRequest struct {
+	Content string `json:"content" binding:"required,min=2,max=2000"`
+}
+
+// QuestionFilter - Filters for question listing
+type QuestionFilter struct {
+	ProductID    uint
+	Status       QuestionStatus // Empty means any status
+	AnsweredOnly bool
+}
+
+// QuestionResponse - Response DTO
+type QuestionResponse struct {
+	ID        uint             `json:"id"`
+	ProductID uint             `json:"product_id"`
+	UserID    uuid.UUID        `json:"user_id"`
+	Content   string           `json:"content"`
+	Status    QuestionStatus   `json:"status"`
+	Answers   []AnswerResponse `json:"answers"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// AnswerResponse - Response DTO
+type AnswerResponse struct {
+	ID        uint      `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Content   string    `json:"content"`
+	IsSeller  bool      `json:"is_seller"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ToQuestionResponse converts entity to response DTO
+func ToQuestionResponse(q *Question) *QuestionResponse {
+	answers := []AnswerResponse{}
+	for _, a := range q.Answers {
+		answers = append(answers, AnswerResponse{
+			ID:        a.ID,
+			UserID:    a.UserID,
+			Content:   a.Content,
+			IsSeller:  a.IsSeller,
+			CreatedAt: a.CreatedAt,
+		})
+	}
+
+	return &QuestionResponse{
+		ID:        q.ID,
+		ProductID: q.ProductID,
+		UserID:    q.UserID,
+		Content:   q.Content,
+		Status:    q.Status,
+		Answers:   answers,
+		CreatedAt: q.CreatedAt,
+	}
+}
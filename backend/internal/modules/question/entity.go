@@ -0,0 +1,48 @@
+package question
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// QuestionStatus Enum
+type QuestionStatus string
+
+const (
+	StatusPending  QuestionStatus = "pending" // Waiting for moderation
+	StatusApproved QuestionStatus = "approved"
+	StatusHidden   QuestionStatus = "hidden"
+)
+
+// Question entity - a shopper's question about a product
+type Question struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	ProductID uint           `gorm:"not null;index" json:"product_id"`
+	UserID    uuid.UUID      `gorm:"type:uuid;not null;index" json:"user_id"`
+	Content   string         `gorm:"type:text;not null" json:"content"`
+	Status    QuestionStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+
+	// Relationships
+	Answers []Answer `gorm:"foreignKey:QuestionID;constraint:OnDelete:CASCADE" json:"answers,omitempty"`
+}
+
+func (Question) TableName() string {
+	return "product_questions"
+}
+
+// Answer entity - answered by an admin (seller) or a verified buyer
+type Answer struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	QuestionID uint      `gorm:"not null;index" json:"question_id"`
+	UserID     uuid.UUID `gorm:"type:uuid;not null" json:"user_id"`
+	Content    string    `gorm:"type:text;not null" json:"content"`
+	IsSeller   bool      `gorm:"not null;default:false" json:"is_seller"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (Answer) TableName() string {
+	return "product_answers"
+}
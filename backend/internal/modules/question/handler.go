@@ -0,0 +1,220 @@
+package question
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler handles question HTTP requests
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new question handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Ask handles POST /questions
+// @Summary Đặt câu hỏi về sản phẩm
+// @Description Câu hỏi hiển thị công khai sau khi được duyệt
+// @Tags Questions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body AskRequest true "Câu hỏi"
+// @Success 201 {object} QuestionResponse
+// @Router /questions [post]
+func (h *Handler) Ask(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req AskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Ask(c.Request.Context(), userID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi gửi câu hỏi"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Gửi câu hỏi thành công, câu hỏi sẽ hiển thị sau khi được duyệt",
+		"data":    res,
+	})
+}
+
+// Answer handles POST /questions/:id/answers
+// @Summary Trả lời câu hỏi
+// @Description Admin hoặc khách đã mua sản phẩm trả lời; người hỏi nhận email thông báo
+// @Tags Questions
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "ID câu hỏi"
+// @Param request body AnswerRequest true "Câu trả lời"
+// @Success 201 {object} QuestionResponse
+// @Failure 403 {object} map[string]string
+// @Router /questions/{id}/answers [post]
+func (h *Handler) Answer(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	var req AnswerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	role, _ := c.Get("role")
+	isAdmin := role == "admin"
+
+	res, err := h.service.Answer(c.Request.Context(), uint(id), userID, isAdmin, req)
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy câu hỏi"})
+		case errors.ErrNotBuyer:
+			c.JSON(http.StatusForbidden, gin.H{"error": "Chỉ người bán hoặc khách đã mua sản phẩm mới được trả lời"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi gửi câu trả lời"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Trả lời thành công",
+		"data":    res,
+	})
+}
+
+// GetByProduct handles GET /questions?product_id= (answered questions only)
+func (h *Handler) GetByProduct(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Query("product_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "product_id không hợp lệ"})
+		return
+	}
+
+	res, err := h.service.GetAnsweredByProduct(c.Request.Context(), uint(productID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// GetAll handles GET /admin/questions (moderation queue, filter by ?status, ?product_id)
+func (h *Handler) GetAll(c *gin.Context) {
+	var filter QuestionFilter
+
+	if productID := c.Query("product_id"); productID != "" {
+		id, err := strconv.ParseUint(productID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "product_id không hợp lệ"})
+			return
+		}
+		filter.ProductID = uint(id)
+	}
+
+	switch status := QuestionStatus(c.Query("status")); status {
+	case "", StatusPending, StatusApproved, StatusHidden:
+		filter.Status = status
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Trạng thái không hợp lệ"})
+		return
+	}
+
+	res, err := h.service.GetAll(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// Approve handles PUT /admin/questions/:id/approve
+func (h *Handler) Approve(c *gin.Context) {
+	h.moderate(c, h.service.Approve, "Duyệt câu hỏi thành công")
+}
+
+// Hide handles PUT /admin/questions/:id/hide
+func (h *Handler) Hide(c *gin.Context) {
+	h.moderate(c, h.service.Hide, "Ẩn câu hỏi thành công")
+}
+
+// DeleteAnswer handles DELETE /admin/answers/:id
+func (h *Handler) DeleteAnswer(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	if err := h.service.DeleteAnswer(c.Request.Context(), uint(id)); err != nil {
+		if err == errors.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy câu trả lời"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Xóa câu trả lời thành công"})
+}
+
+func (h *Handler) moderate(c *gin.Context, action func(ctx context.Context, id uint) (*QuestionResponse, error), message string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	res, err := action(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == errors.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy câu hỏi"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    res,
+	})
+}
+
+// currentUserID reads the user ID set by AuthMiddleware
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
@@ -0,0 +1,74 @@
+package question
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repository interface
+type Repository interface {
+	Create(ctx context.Context, question *Question) error
+	GetByID(ctx context.Context, id uint) (*Question, error)
+	GetAll(ctx context.Context, filter QuestionFilter) ([]Question, error)
+	UpdateStatus(ctx context.Context, id uint, status QuestionStatus) error
+
+	// Answer operations
+	CreateAnswer(ctx context.Context, answer *Answer) error
+	DeleteAnswer(ctx context.Context, id uint) (bool, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new question repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, question *Question) error {
+	return r.db.WithContext(ctx).Create(question).Error
+}
+
+func (r *repository) GetByID(ctx context.Context, id uint) (*Question, error) {
+	var question Question
+	err := r.db.WithContext(ctx).
+		Preload("Answers", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		First(&question, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+func (r *repository) GetAll(ctx context.Context, filter QuestionFilter) ([]Question, error) {
+	var questions []Question
+	query := r.db.WithContext(ctx).
+		Preload("Answers", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") })
+	if filter.ProductID != 0 {
+		query = query.Where("product_id = ?", filter.ProductID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.AnsweredOnly {
+		query = query.Where("EXISTS (SELECT 1 FROM product_answers a WHERE a.question_id = product_questions.id)")
+	}
+	err := query.Order("created_at DESC").Find(&questions).Error
+	return questions, err
+}
+
+func (r *repository) UpdateStatus(ctx context.Context, id uint, status QuestionStatus) error {
+	return r.db.WithContext(ctx).Model(&Question{}).Where("id = ?", id).Update("status", status).Error
+}
+
+func (r *repository) CreateAnswer(ctx context.Context, answer *Answer) error {
+	return r.db.WithContext(ctx).Create(answer).Error
+}
+
+// DeleteAnswer reports whether an answer was deleted
+func (r *repository) DeleteAnswer(ctx context.Context, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Delete(&Answer{}, id)
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,167 @@
+package question
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/pkg/mailer"
+
+	"github.com/google/uuid"
+)
+
+// BuyerChecker checks whether a customer bought a product
+type BuyerChecker interface {
+	HasPurchased(ctx context.Context, userID uuid.UUID, productID uint) (bool, error)
+}
+
+// UserGetter interface for looking up the email of an asker
+type UserGetter interface {
+	GetEmail(ctx context.Context, id uuid.UUID) (string, error)
+}
+
+// Service interface
+type Service interface {
+	Ask(ctx context.Context, userID uuid.UUID, req AskRequest) (*QuestionResponse, error)
+	Answer(ctx context.Context, id uint, userID uuid.UUID, isAdmin bool, req AnswerRequest) (*QuestionResponse, error)
+	GetAnsweredByProduct(ctx context.Context, productID uint) ([]QuestionResponse, error)
+
+	// Moderation
+	GetAll(ctx context.Context, filter QuestionFilter) ([]QuestionResponse, error)
+	Approve(ctx context.Context, id uint) (*QuestionResponse, error)
+	Hide(ctx context.Context, id uint) (*QuestionResponse, error)
+	DeleteAnswer(ctx context.Context, answerID uint) error
+}
+
+type service struct {
+	repo   Repository
+	buyers BuyerChecker
+	users  UserGetter
+	mailer mailer.Mailer
+}
+
+// NewService creates a new question service
+// Without a buyer checker only admins can answer.
+func NewService(repo Repository, buyers BuyerChecker, users UserGetter, mailer mailer.Mailer) Service {
+	return &service{repo: repo, buyers: buyers, users: users, mailer: mailer}
+}
+
+// Ask posts a question; it is shown once an admin approves it
+func (s *service) Ask(ctx context.Context, userID uuid.UUID, req AskRequest) (*QuestionResponse, error) {
+	question := &Question{
+		ProductID: req.ProductID,
+		UserID:    userID,
+		Content:   req.Content,
+		Status:    StatusPending,
+	}
+	if err := s.repo.Create(ctx, question); err != nil {
+		return nil, err
+	}
+	return ToQuestionResponse(question), nil
+}
+
+// Answer adds an answer from an admin or a verified buyer and notifies the asker
+// Admins may answer pending questions; buyers only see approved ones.
+func (s *service) Answer(ctx context.Context, id uint, userID uuid.UUID, isAdmin bool, req AnswerRequest) (*QuestionResponse, error) {
+	question, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	if !isAdmin {
+		if question.Status != StatusApproved {
+			return nil, errors.ErrRecordNotFound
+		}
+		if s.buyers == nil {
+			return nil, errors.ErrNotBuyer
+		}
+		bought, err := s.buyers.HasPurchased(ctx, userID, question.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		if !bought {
+			return nil, errors.ErrNotBuyer
+		}
+	}
+
+	answer := &Answer{
+		QuestionID: id,
+		UserID:     userID,
+		Content:    req.Content,
+		IsSeller:   isAdmin,
+	}
+	if err := s.repo.CreateAnswer(ctx, answer); err != nil {
+		return nil, err
+	}
+
+	if question.UserID != userID {
+		// Sending mail must not hold up or fail the request
+		go s.notifyAsker(context.Background(), question, answer)
+	}
+
+	question.Answers = append(question.Answers, *answer)
+	return ToQuestionResponse(question), nil
+}
+
+// GetAnsweredByProduct returns approved questions that have at least one answer
+func (s *service) GetAnsweredByProduct(ctx context.Context, productID uint) ([]QuestionResponse, error) {
+	return s.GetAll(ctx, QuestionFilter{ProductID: productID, Status: StatusApproved, AnsweredOnly: true})
+}
+
+func (s *service) GetAll(ctx context.Context, filter QuestionFilter) ([]QuestionResponse, error) {
+	questions, err := s.repo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []QuestionResponse{}
+	for i := range questions {
+		responses = append(responses, *ToQuestionResponse(&questions[i]))
+	}
+	return responses, nil
+}
+
+func (s *service) Approve(ctx context.Context, id uint) (*QuestionResponse, error) {
+	return s.setStatus(ctx, id, StatusApproved)
+}
+
+func (s *service) Hide(ctx context.Context, id uint) (*QuestionResponse, error) {
+	return s.setStatus(ctx, id, StatusHidden)
+}
+
+func (s *service) DeleteAnswer(ctx context.Context, answerID uint) error {
+	deleted, err := s.repo.DeleteAnswer(ctx, answerID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *service) setStatus(ctx context.Context, id uint, status QuestionStatus) (*QuestionResponse, error) {
+	question, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	if err := s.repo.UpdateStatus(ctx, id, status); err != nil {
+		return nil, err
+	}
+	question.Status = status
+	return ToQuestionResponse(question), nil
+}
+
+func (s *service) notifyAsker(ctx context.Context, question *Question, answer *Answer) {
+	email, err := s.users.GetEmail(ctx, question.UserID)
+	if err != nil {
+		log.Printf("Question %d: load asker email failed: %v", question.ID, err)
+		return
+	}
+
+	body := fmt.Sprintf("Câu hỏi của bạn:\n%s\n\nCâu trả lời:\n%s\n", question.Content, answer.Content)
+	if err := s.mailer.Send(ctx, email, "Câu hỏi của bạn đã có câu trả lời", body); err != nil {
+		log.Printf("Question %d: notify asker failed: %v", question.ID, err)
+	}
+}
@@ -0,0 +1,90 @@
+package review
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateReviewRequest - Request body (multipart/form-data)
+// Photos are uploaded as "images" files.
+type CreateReviewRequest struct {
+	ProductID uint   `form:"product_id" binding:"required"`
+	Rating    int    `form:"rating" binding:"required,min=1,max=5"`
+	Content   string `form:"content" binding:"max=5000"`
+}
+
+// ReplyRequest - Request body for a seller reply
+type ReplyRequest struct {
+	Reply string `json:"reply" binding:"required,max=2000"`
+}
+
+// ReviewFilter - Filters for review listing
+type ReviewFilter struct {
+	ProductID uint
+	Rating    int          // 0 means any rating
+	Status    ReviewStatus // Empty means any status (admin only)
+	Sort      string       // "newest" (default) or "helpful"
+}
+
+// ReviewResponse - Response DTO
+type ReviewResponse struct {
+	ID           uint         `json:"id"`
+	ProductID    uint         `json:"product_id"`
+	UserID       uuid.UUID    `json:"user_id"`
+	Rating       int          `json:"rating"`
+	Content      string       `json:"content"`
+	Status       ReviewStatus `json:"status"`
+	HelpfulCount int          `json:"helpful_count"`
+	Reply        string       `json:"reply,omitempty"`
+	RepliedAt    *time.Time   `json:"replied_at,omitempty"`
+	Images       []string     `json:"images"`
+	CreatedAt    time.Time    `json:"created_at"`
+}
+
+// RatingSummaryResponse - Rating average, count and per-star histogram
+type RatingSummaryResponse struct {
+	ProductID   uint        `json:"product_id"`
+	RatingAvg   float64     `json:"rating_avg"`
+	ReviewCount int         `json:"review_count"`
+	Histogram   map[int]int `json:"histogram"` // star -> number of reviews
+}
+
+// ProductReviewsResponse - Reviews of a product with its summary
+type ProductReviewsResponse struct {
+	Summary RatingSummaryResponse `json:"summary"`
+	Reviews []ReviewResponse      `json:"reviews"`
+}
+
+// ToReviewResponse converts entity to response DTO
+func ToReviewResponse(r *Review) *ReviewResponse {
+	images := []string{}
+	for _, img := range r.Images {
+		images = append(images, img.ImageURL)
+	}
+
+	return &ReviewResponse{
+		ID:           r.ID,
+		ProductID:    r.ProductID,
+		UserID:       r.UserID,
+		Rating:       r.Rating,
+		Content:      r.Content,
+		Status:       r.Status,
+		HelpfulCount: r.HelpfulCount,
+		Reply:        r.Reply,
+		RepliedAt:    r.RepliedAt,
+		Images:       images,
+		CreatedAt:    r.CreatedAt,
+	}
+}
+
+// ToRatingSummaryResponse converts entity to response DTO
+func ToRatingSummaryResponse(s *RatingSummary) *RatingSummaryResponse {
+	return &RatingSummaryResponse{
+		ProductID:   s.ProductID,
+		RatingAvg:   math.Round(s.Average()*100) / 100,
+		ReviewCount: s.Total(),
+		Histogram:   map[int]int{1: s.Star1, 2: s.Star2, 3: s.Star3, 4: s.Star4, 5: s.Star5},
+	}
+}
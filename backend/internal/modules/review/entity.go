@@ -0,0 +1,102 @@
+package review
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewStatus Enum
+type ReviewStatus string
+
+const (
+	StatusPending  ReviewStatus = "pending" // Waiting for moderation
+	StatusApproved ReviewStatus = "approved"
+	StatusHidden   ReviewStatus = "hidden"
+)
+
+// Review entity
+// PurchaseID is the purchased item the review is for, so a customer can review
+// a product once per purchase. Only approved reviews count towards the rating.
+type Review struct {
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	ProductID    uint         `gorm:"not null;index" json:"product_id"`
+	UserID       uuid.UUID    `gorm:"type:uuid;not null;index" json:"user_id"`
+	PurchaseID   uint         `gorm:"not null;uniqueIndex" json:"purchase_id"`
+	Rating       int          `gorm:"not null;check:rating >= 1 AND rating <= 5" json:"rating"`
+	Content      string       `gorm:"type:text" json:"content"`
+	Status       ReviewStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	HelpfulCount int          `gorm:"not null;default:0" json:"helpful_count"`
+
+	// Seller reply
+	Reply     string     `gorm:"type:text" json:"reply"`
+	RepliedAt *time.Time `json:"replied_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relationships
+	Images []ReviewImage `gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE" json:"images,omitempty"`
+}
+
+func (Review) TableName() string {
+	return "reviews"
+}
+
+// ReviewImage entity - customer photo attached to a review
+type ReviewImage struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ReviewID      uint      `gorm:"not null;index" json:"review_id"`
+	ImageURL      string    `gorm:"type:text;not null" json:"image_url"`
+	ImagePublicID string    `gorm:"type:varchar(255)" json:"-"`
+	DisplayOrder  int       `gorm:"not null" json:"display_order"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (ReviewImage) TableName() string {
+	return "review_images"
+}
+
+// ReviewVote entity - a customer marking a review as helpful (once per review)
+type ReviewVote struct {
+	ReviewID  uint      `gorm:"primaryKey" json:"review_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Review Review `gorm:"foreignKey:ReviewID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (ReviewVote) TableName() string {
+	return "review_votes"
+}
+
+// RatingSummary entity - per-star histogram of approved reviews of a product
+// Kept in the same transaction as products.rating_avg and review_count.
+type RatingSummary struct {
+	ProductID uint      `gorm:"primaryKey" json:"product_id"`
+	Star1     int       `gorm:"not null;default:0" json:"star_1"`
+	Star2     int       `gorm:"not null;default:0" json:"star_2"`
+	Star3     int       `gorm:"not null;default:0" json:"star_3"`
+	Star4     int       `gorm:"not null;default:0" json:"star_4"`
+	Star5     int       `gorm:"not null;default:0" json:"star_5"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (RatingSummary) TableName() string {
+	return "product_rating_summaries"
+}
+
+// Total returns the number of approved reviews
+func (s *RatingSummary) Total() int {
+	return s.Star1 + s.Star2 + s.Star3 + s.Star4 + s.Star5
+}
+
+// Average returns the mean rating, 0 when there are no reviews
+func (s *RatingSummary) Average() float64 {
+	total := s.Total()
+	if total == 0 {
+		return 0
+	}
+	sum := s.Star1 + 2*s.Star2 + 3*s.Star3 + 4*s.Star4 + 5*s.Star5
+	return float64(sum) / float64(total)
+}
@@ -0,0 +1,272 @@
+package review
+
+import (
+	"context"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler handles review HTTP requests
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new review handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// Create handles POST /reviews
+// @Summary Viết đánh giá sản phẩm
+// @Description Khách đã mua sản phẩm đánh giá 1-5 sao kèm nội dung và tối đa 5 ảnh (multipart/form-data).
+// @Description Mỗi lần mua được đánh giá một lần; đánh giá chờ duyệt trước khi hiển thị.
+// @Tags Reviews
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param product_id formData int true "ID sản phẩm"
+// @Param rating formData int true "Số sao (1-5)"
+// @Param content formData string false "Nội dung"
+// @Param images formData file false "Ảnh (tối đa 5)"
+// @Success 201 {object} ReviewResponse
+// @Failure 403 {object} map[string]string
+// @Router /reviews [post]
+func (h *Handler) Create(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req CreateReviewRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var images []*multipart.FileHeader
+	if form, err := c.MultipartForm(); err == nil {
+		images = form.File["images"]
+	}
+
+	res, err := h.service.Create(c.Request.Context(), userID, req, images)
+	if err != nil {
+		switch err {
+		case errors.ErrNotPurchased:
+			c.JSON(http.StatusForbidden, gin.H{"error": "Bạn cần mua sản phẩm trước khi đánh giá"})
+		case errors.ErrAlreadyReviewed:
+			c.JSON(http.StatusConflict, gin.H{"error": "Bạn đã đánh giá tất cả lần mua sản phẩm này"})
+		case errors.ErrTooManyImages:
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Tối đa 5 ảnh"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi tạo đánh giá"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Gửi đánh giá thành công, đánh giá sẽ hiển thị sau khi được duyệt",
+		"data":    res,
+	})
+}
+
+// GetProductReviews handles GET /reviews?product_id=
+// @Summary Đánh giá của sản phẩm
+// @Description Danh sách đánh giá đã duyệt kèm điểm trung bình và số lượng theo từng mức sao
+// @Tags Reviews
+// @Produce json
+// @Param product_id query int true "ID sản phẩm"
+// @Param rating query int false "Lọc theo số sao"
+// @Param sort query string false "newest (mặc định) hoặc helpful"
+// @Success 200 {object} ProductReviewsResponse
+// @Router /reviews [get]
+func (h *Handler) GetProductReviews(c *gin.Context) {
+	filter, ok := parseFilter(c)
+	if !ok {
+		return
+	}
+	if filter.ProductID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Thiếu product_id"})
+		return
+	}
+
+	res, err := h.service.GetProductReviews(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// GetAll handles GET /admin/reviews (moderation queue, filter by ?status)
+func (h *Handler) GetAll(c *gin.Context) {
+	filter, ok := parseFilter(c)
+	if !ok {
+		return
+	}
+
+	switch status := ReviewStatus(c.Query("status")); status {
+	case "", StatusPending, StatusApproved, StatusHidden:
+		filter.Status = status
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Trạng thái không hợp lệ"})
+		return
+	}
+
+	res, err := h.service.GetAll(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// Approve handles PUT /admin/reviews/:id/approve
+func (h *Handler) Approve(c *gin.Context) {
+	h.moderate(c, h.service.Approve, "Duyệt đánh giá thành công")
+}
+
+// Hide handles PUT /admin/reviews/:id/hide
+func (h *Handler) Hide(c *gin.Context) {
+	h.moderate(c, h.service.Hide, "Ẩn đánh giá thành công")
+}
+
+// Reply handles PUT /admin/reviews/:id/reply
+func (h *Handler) Reply(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	var req ReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Reply(c.Request.Context(), uint(id), req)
+	if err != nil {
+		if err == errors.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy đánh giá"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Trả lời đánh giá thành công",
+		"data":    res,
+	})
+}
+
+// MarkHelpful handles POST /reviews/:id/helpful
+func (h *Handler) MarkHelpful(c *gin.Context) {
+	h.vote(c, h.service.MarkHelpful)
+}
+
+// UnmarkHelpful handles DELETE /reviews/:id/helpful
+func (h *Handler) UnmarkHelpful(c *gin.Context) {
+	h.vote(c, h.service.UnmarkHelpful)
+}
+
+func (h *Handler) moderate(c *gin.Context, action func(ctx context.Context, id uint) (*ReviewResponse, error), message string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	res, err := action(c.Request.Context(), uint(id))
+	if err != nil {
+		if err == errors.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy đánh giá"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"data":    res,
+	})
+}
+
+func (h *Handler) vote(c *gin.Context, action func(ctx context.Context, id uint, userID uuid.UUID) (*ReviewResponse, error)) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	res, err := action(c.Request.Context(), uint(id), userID)
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy đánh giá"})
+		case errors.ErrAlreadyVoted:
+			c.JSON(http.StatusConflict, gin.H{"error": "Bạn đã đánh dấu hữu ích rồi"})
+		case errors.ErrOwnReview:
+			c.JSON(http.StatusForbidden, gin.H{"error": "Không thể bình chọn đánh giá của chính bạn"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		}
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// currentUserID reads the user ID set by AuthMiddleware
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+// parseFilter reads ?product_id, ?rating and ?sort
+func parseFilter(c *gin.Context) (ReviewFilter, bool) {
+	var filter ReviewFilter
+
+	if productID := c.Query("product_id"); productID != "" {
+		id, err := strconv.ParseUint(productID, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "product_id không hợp lệ"})
+			return filter, false
+		}
+		filter.ProductID = uint(id)
+	}
+	if rating := c.Query("rating"); rating != "" {
+		r, err := strconv.Atoi(rating)
+		if err != nil || r < 1 || r > 5 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "rating phải từ 1 đến 5"})
+			return filter, false
+		}
+		filter.Rating = r
+	}
+	filter.Sort = c.DefaultQuery("sort", "newest")
+	if filter.Sort != "newest" && filter.Sort != "helpful" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort phải là newest hoặc helpful"})
+		return filter, false
+	}
+
+	return filter, true
+}
@@ -0,0 +1,123 @@
+package review
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository interface
+type Repository interface {
+	Create(ctx context.Context, review *Review) error
+	GetByID(ctx context.Context, id uint) (*Review, error)
+	GetAll(ctx context.Context, filter ReviewFilter) ([]Review, error)
+	UpdateFields(ctx context.Context, id uint, fields map[string]interface{}) error
+	GetReviewedPurchaseIDs(ctx context.Context, purchaseIDs []uint) ([]uint, error)
+	GetSummary(ctx context.Context, productID uint) (*RatingSummary, error)
+
+	// Transaction support
+	WithTransaction(fn func(*gorm.DB) error) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new review repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// WithTransaction executes a function within a transaction
+func (r *repository) WithTransaction(fn func(*gorm.DB) error) error {
+	return r.db.Transaction(fn)
+}
+
+func (r *repository) Create(ctx context.Context, review *Review) error {
+	return r.db.WithContext(ctx).Create(review).Error
+}
+
+func (r *repository) GetByID(ctx context.Context, id uint) (*Review, error) {
+	var review Review
+	err := r.db.WithContext(ctx).
+		Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Order("display_order ASC") }).
+		First(&review, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *repository) GetAll(ctx context.Context, filter ReviewFilter) ([]Review, error) {
+	var reviews []Review
+	query := r.db.WithContext(ctx).
+		Preload("Images", func(db *gorm.DB) *gorm.DB { return db.Order("display_order ASC") })
+	if filter.ProductID != 0 {
+		query = query.Where("product_id = ?", filter.ProductID)
+	}
+	if filter.Rating != 0 {
+		query = query.Where("rating = ?", filter.Rating)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Sort == "helpful" {
+		query = query.Order("helpful_count DESC")
+	}
+	err := query.Order("created_at DESC").Find(&reviews).Error
+	return reviews, err
+}
+
+func (r *repository) UpdateFields(ctx context.Context, id uint, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&Review{}).Where("id = ?", id).Updates(fields).Error
+}
+
+// GetReviewedPurchaseIDs returns which of the given purchases already have a review
+func (r *repository) GetReviewedPurchaseIDs(ctx context.Context, purchaseIDs []uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&Review{}).
+		Where("purchase_id IN ?", purchaseIDs).
+		Pluck("purchase_id", &ids).Error
+	return ids, err
+}
+
+// GetSummary returns the rating histogram; products without reviews get an empty one
+func (r *repository) GetSummary(ctx context.Context, productID uint) (*RatingSummary, error) {
+	summary := RatingSummary{ProductID: productID}
+	err := r.db.WithContext(ctx).Where("product_id = ?", productID).Limit(1).Find(&summary).Error
+	return &summary, err
+}
+
+// recalculateSummary rebuilds the histogram of a product from its approved reviews
+func recalculateSummary(tx *gorm.DB, productID uint) (*RatingSummary, error) {
+	var rows []struct {
+		Rating int
+		Count  int
+	}
+	err := tx.Model(&Review{}).
+		Select("rating, COUNT(*) AS count").
+		Where("product_id = ? AND status = ?", productID, StatusApproved).
+		Group("rating").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summary := RatingSummary{ProductID: productID}
+	stars := map[int]*int{1: &summary.Star1, 2: &summary.Star2, 3: &summary.Star3, 4: &summary.Star4, 5: &summary.Star5}
+	for _, row := range rows {
+		if star, ok := stars[row.Rating]; ok {
+			*star = row.Count
+		}
+	}
+
+	err = tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "product_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"star1", "star2", "star3", "star4", "star5", "updated_at"}),
+	}).Create(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
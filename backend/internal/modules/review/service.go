@@ -0,0 +1,311 @@
+package review
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"mime/multipart"
+	"time"
+
+	"go-ecommerce/internal/database"
+	"go-ecommerce/internal/shared/errors"
+	"go-ecommerce/pkg/cloudinary"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const maxReviewImages = 5
+
+// PurchaseVerifier finds the purchases a customer can review
+type PurchaseVerifier interface {
+	// PurchasedItemIDs returns IDs of the user's completed purchases of the product
+	PurchasedItemIDs(ctx context.Context, userID uuid.UUID, productID uint) ([]uint, error)
+}
+
+// ProductRatingUpdater keeps products.rating_avg and review_count in sync
+type ProductRatingUpdater interface {
+	LockProduct(tx *gorm.DB, productID uint) error
+	UpdateRating(tx *gorm.DB, productID uint, avg float64, count int) error
+}
+
+// Service interface
+type Service interface {
+	Create(ctx context.Context, userID uuid.UUID, req CreateReviewRequest, imageFiles []*multipart.FileHeader) (*ReviewResponse, error)
+	GetProductReviews(ctx context.Context, filter ReviewFilter) (*ProductReviewsResponse, error)
+	GetAll(ctx context.Context, filter ReviewFilter) ([]ReviewResponse, error)
+
+	// Moderation
+	Approve(ctx context.Context, id uint) (*ReviewResponse, error)
+	Hide(ctx context.Context, id uint) (*ReviewResponse, error)
+	Reply(ctx context.Context, id uint, req ReplyRequest) (*ReviewResponse, error)
+
+	// Helpful votes
+	MarkHelpful(ctx context.Context, id uint, userID uuid.UUID) (*ReviewResponse, error)
+	UnmarkHelpful(ctx context.Context, id uint, userID uuid.UUID) (*ReviewResponse, error)
+}
+
+type service struct {
+	repo       Repository
+	cloudinary *cloudinary.Client
+	purchases  PurchaseVerifier
+	products   ProductRatingUpdater
+}
+
+// NewService creates a new review service
+// Without a purchase verifier nobody is allowed to review.
+func NewService(repo Repository, cloudinary *cloudinary.Client, purchases PurchaseVerifier, products ProductRatingUpdater) Service {
+	return &service{repo: repo, cloudinary: cloudinary, purchases: purchases, products: products}
+}
+
+// Create adds a pending review for the first purchase of the product not reviewed yet
+func (s *service) Create(ctx context.Context, userID uuid.UUID, req CreateReviewRequest, imageFiles []*multipart.FileHeader) (*ReviewResponse, error) {
+	if len(imageFiles) > maxReviewImages {
+		return nil, errors.ErrTooManyImages
+	}
+	if s.purchases == nil {
+		return nil, errors.ErrNotPurchased
+	}
+
+	purchaseIDs, err := s.purchases.PurchasedItemIDs(ctx, userID, req.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	if len(purchaseIDs) == 0 {
+		return nil, errors.ErrNotPurchased
+	}
+
+	reviewed, err := s.repo.GetReviewedPurchaseIDs(ctx, purchaseIDs)
+	if err != nil {
+		return nil, err
+	}
+	purchaseID, ok := firstUnreviewed(purchaseIDs, reviewed)
+	if !ok {
+		return nil, errors.ErrAlreadyReviewed
+	}
+
+	review := &Review{
+		ProductID:  req.ProductID,
+		UserID:     userID,
+		PurchaseID: purchaseID,
+		Rating:     req.Rating,
+		Content:    req.Content,
+		Status:     StatusPending,
+	}
+
+	// Upload photos before the insert; remove them again if it fails
+	for i, fileHeader := range imageFiles {
+		file, err := fileHeader.Open()
+		if err != nil {
+			s.deleteImages(ctx, review.Images)
+			return nil, fmt.Errorf("failed to open image file: %w", err)
+		}
+		result, err := s.cloudinary.Upload(ctx, file, "reviews")
+		file.Close()
+		if err != nil {
+			s.deleteImages(ctx, review.Images)
+			return nil, fmt.Errorf("failed to upload image: %w", err)
+		}
+		review.Images = append(review.Images, ReviewImage{
+			ImageURL:      result.URL,
+			ImagePublicID: result.PublicID,
+			DisplayOrder:  i + 1,
+		})
+	}
+
+	if err := s.repo.Create(ctx, review); err != nil {
+		s.deleteImages(ctx, review.Images)
+		// A concurrent submission took the same purchase first
+		if database.IsUniqueViolation(err, "idx_reviews_purchase_id") {
+			return nil, errors.ErrAlreadyReviewed
+		}
+		return nil, err
+	}
+
+	return ToReviewResponse(review), nil
+}
+
+// GetProductReviews returns approved reviews of a product with its rating summary
+func (s *service) GetProductReviews(ctx context.Context, filter ReviewFilter) (*ProductReviewsResponse, error) {
+	filter.Status = StatusApproved
+	reviews, err := s.GetAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.repo.GetSummary(ctx, filter.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ProductReviewsResponse{
+		Summary: *ToRatingSummaryResponse(summary),
+		Reviews: reviews,
+	}, nil
+}
+
+func (s *service) GetAll(ctx context.Context, filter ReviewFilter) ([]ReviewResponse, error) {
+	reviews, err := s.repo.GetAll(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []ReviewResponse{}
+	for i := range reviews {
+		responses = append(responses, *ToReviewResponse(&reviews[i]))
+	}
+	return responses, nil
+}
+
+// Approve publishes a review and counts it towards the product rating
+func (s *service) Approve(ctx context.Context, id uint) (*ReviewResponse, error) {
+	return s.setStatus(ctx, id, StatusApproved)
+}
+
+// Hide removes a review from the storefront and from the product rating
+func (s *service) Hide(ctx context.Context, id uint) (*ReviewResponse, error) {
+	return s.setStatus(ctx, id, StatusHidden)
+}
+
+// setStatus changes the status and recalculates the rating in one transaction
+func (s *service) setStatus(ctx context.Context, id uint, status ReviewStatus) (*ReviewResponse, error) {
+	review, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	if review.Status == status {
+		return ToReviewResponse(review), nil
+	}
+
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		// Serialize rating updates of the same product
+		if err := s.products.LockProduct(tx, review.ProductID); err != nil {
+			return errors.ErrRecordNotFound
+		}
+		if err := tx.Model(&Review{}).Where("id = ?", id).Update("status", status).Error; err != nil {
+			return err
+		}
+
+		summary, err := recalculateSummary(tx, review.ProductID)
+		if err != nil {
+			return fmt.Errorf("failed to update rating summary: %w", err)
+		}
+		avg := math.Round(summary.Average()*100) / 100
+		return s.products.UpdateRating(tx, review.ProductID, avg, summary.Total())
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	review.Status = status
+	return ToReviewResponse(review), nil
+}
+
+// Reply sets (or replaces) the seller reply of a review
+func (s *service) Reply(ctx context.Context, id uint, req ReplyRequest) (*ReviewResponse, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	err := s.repo.UpdateFields(ctx, id, map[string]interface{}{
+		"reply":      req.Reply,
+		"replied_at": time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.getByID(ctx, id)
+}
+
+// MarkHelpful records a helpful vote; each customer votes once per review
+func (s *service) MarkHelpful(ctx context.Context, id uint, userID uuid.UUID) (*ReviewResponse, error) {
+	review, err := s.getVotable(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&ReviewVote{ReviewID: review.ID, UserID: userID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrAlreadyVoted
+		}
+		return tx.Model(&Review{}).Where("id = ?", id).
+			UpdateColumn("helpful_count", gorm.Expr("helpful_count + 1")).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.getByID(ctx, id)
+}
+
+// UnmarkHelpful takes a helpful vote back
+func (s *service) UnmarkHelpful(ctx context.Context, id uint, userID uuid.UUID) (*ReviewResponse, error) {
+	review, err := s.getVotable(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.WithTransaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		result := tx.Where("review_id = ? AND user_id = ?", review.ID, userID).Delete(&ReviewVote{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrRecordNotFound
+		}
+		return tx.Model(&Review{}).Where("id = ?", id).
+			UpdateColumn("helpful_count", gorm.Expr("helpful_count - 1")).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.getByID(ctx, id)
+}
+
+// getVotable returns an approved review the user did not write
+func (s *service) getVotable(ctx context.Context, id uint, userID uuid.UUID) (*Review, error) {
+	review, err := s.repo.GetByID(ctx, id)
+	if err != nil || review.Status != StatusApproved {
+		return nil, errors.ErrRecordNotFound
+	}
+	if review.UserID == userID {
+		return nil, errors.ErrOwnReview
+	}
+	return review, nil
+}
+
+func (s *service) getByID(ctx context.Context, id uint) (*ReviewResponse, error) {
+	review, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+	return ToReviewResponse(review), nil
+}
+
+func (s *service) deleteImages(ctx context.Context, images []ReviewImage) {
+	for _, img := range images {
+		_ = s.cloudinary.Delete(ctx, img.ImagePublicID)
+	}
+}
+
+// firstUnreviewed picks the first purchase without a review
+func firstUnreviewed(purchaseIDs, reviewed []uint) (uint, bool) {
+	done := make(map[uint]bool, len(reviewed))
+	for _, id := range reviewed {
+		done[id] = true
+	}
+	for _, id := range purchaseIDs {
+		if !done[id] {
+			return id, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,97 @@
+package review_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go-ecommerce/internal/database/dbtest"
+	"go-ecommerce/internal/modules/product"
+	"go-ecommerce/internal/modules/review"
+	"go-ecommerce/internal/shared/errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// onePurchase is a customer who bought the product once
+type onePurchase struct{}
+
+func (onePurchase) PurchasedItemIDs(ctx context.Context, userID uuid.UUID, productID uint) ([]uint, error) {
+	return []uint{7}, nil
+}
+
+func openReviewDB(t *testing.T) (*gorm.DB, review.Service, uint) {
+	t.Helper()
+	db := dbtest.Open(t, &product.Product{}, &review.Review{}, &review.ReviewImage{}, &review.ReviewVote{}, &review.RatingSummary{})
+	p := product.Product{Name: "Áo thun", Slug: "ao-thun", CategoryID: 1, BrandID: 1, Status: product.StatusPublished}
+	if err := db.Create(&p).Error; err != nil {
+		t.Fatalf("create product: %v", err)
+	}
+	svc := review.NewService(review.NewRepository(db), nil, onePurchase{}, product.NewRatingUpdater())
+	return db, svc, p.ID
+}
+
+func TestCreateConcurrentForOnePurchase(t *testing.T) {
+	_, svc, productID := openReviewDB(t)
+	userID := uuid.New()
+
+	const submissions = 10
+	errs := make([]error, submissions)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := range submissions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, errs[i] = svc.Create(context.Background(), userID, review.CreateReviewRequest{ProductID: productID, Rating: 5}, nil)
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	created := 0
+	for i, err := range errs {
+		switch err {
+		case nil:
+			created++
+		case errors.ErrAlreadyReviewed:
+		default:
+			t.Errorf("submission %d: unexpected error: %v", i+1, err)
+		}
+	}
+	if created != 1 {
+		t.Errorf("%d reviews created for one purchase, want 1", created)
+	}
+}
+
+func TestModerateReviewOfTrashedProduct(t *testing.T) {
+	db, svc, productID := openReviewDB(t)
+	created, err := svc.Create(context.Background(), uuid.New(), review.CreateReviewRequest{ProductID: productID, Rating: 4}, nil)
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if err := db.Delete(&product.Product{}, productID).Error; err != nil {
+		t.Fatalf("trash product: %v", err)
+	}
+
+	approved, err := svc.Approve(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if approved.Status != review.StatusApproved {
+		t.Errorf("status = %s, want %s", approved.Status, review.StatusApproved)
+	}
+	var p product.Product
+	if err := db.Unscoped().First(&p, productID).Error; err != nil {
+		t.Fatalf("load product: %v", err)
+	}
+	if p.ReviewCount != 1 || p.RatingAvg != 4 {
+		t.Errorf("product rating = %v over %d reviews, want 4 over 1", p.RatingAvg, p.ReviewCount)
+	}
+
+	if _, err := svc.Hide(context.Background(), created.ID); err != nil {
+		t.Fatalf("Hide: %v", err)
+	}
+}
@@ -37,3 +37,14 @@ type LoginResponse struct {
 	ExpiresIn    int64        `json:"expires_in"` // Giây
 	User         UserResponse `json:"user"`
 }
+
+// AddressRequest: Thêm/sửa địa chỉ trong sổ địa chỉ
+type AddressRequest struct {
+	RecipientName  string `json:"recipient_name" binding:"required,max=100"`
+	RecipientPhone string `json:"recipient_phone" binding:"required,max=20"`
+	Street         string `json:"street" binding:"required,max=255"`
+	City           string `json:"city" binding:"required,max=100"`
+	District       string `json:"district" binding:"max=100"`
+	Ward           string `json:"ward" binding:"max=100"`
+	IsDefault      bool   `json:"is_default"`
+}
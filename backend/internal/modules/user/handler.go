@@ -1,7 +1,10 @@
 package user
 
 import (
+	"context"
+	"log"
 	"net/http"
+	"strconv"
 
 	"go-ecommerce/internal/shared/errors"
 
@@ -12,11 +15,17 @@ import (
 // Handler xử lý các request liên quan đến User
 type Handler struct {
 	service Service
+	carts   CartMerger
+}
+
+// CartMerger gộp giỏ hàng của khách (header X-Cart-Token) vào giỏ của user khi đăng nhập
+type CartMerger interface {
+	MergeGuestCart(ctx context.Context, token string, userID uuid.UUID) error
 }
 
 // NewHandler khởi tạo Handler
-func NewHandler(service Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(service Service, carts CartMerger) *Handler {
+	return &Handler{service: service, carts: carts}
 }
 
 // Register xử lý request đăng ký tài khoản
@@ -90,6 +99,13 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
+	// Gộp giỏ hàng của khách; lỗi không chặn việc đăng nhập
+	if token := c.GetHeader("X-Cart-Token"); token != "" && h.carts != nil {
+		if err := h.carts.MergeGuestCart(c.Request.Context(), token, res.User.ID); err != nil {
+			log.Printf("Merge guest cart into user %s failed: %v", res.User.ID, err)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Đăng nhập thành công",
 		"data":    res,
@@ -194,3 +210,144 @@ func (h *Handler) GetProfile(c *gin.Context) {
 		"data": res,
 	})
 }
+
+// GetAddresses lấy sổ địa chỉ
+// @Summary Sổ địa chỉ
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} Address
+// @Router /me/addresses [get]
+func (h *Handler) GetAddresses(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GetAddresses(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// CreateAddress thêm địa chỉ giao hàng
+// @Summary Thêm địa chỉ
+// @Description Địa chỉ đầu tiên tự động là địa chỉ mặc định
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body AddressRequest true "Địa chỉ"
+// @Success 201 {object} Address
+// @Router /me/addresses [post]
+func (h *Handler) CreateAddress(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.CreateAddress(c.Request.Context(), userID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Thêm địa chỉ thành công",
+		"data":    res,
+	})
+}
+
+// UpdateAddress sửa địa chỉ giao hàng
+// @Summary Sửa địa chỉ
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Address ID"
+// @Param request body AddressRequest true "Địa chỉ"
+// @Success 200 {object} Address
+// @Router /me/addresses/{id} [put]
+func (h *Handler) UpdateAddress(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	var req AddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.UpdateAddress(c.Request.Context(), userID, uint(id), req)
+	if err != nil {
+		if err == errors.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy địa chỉ"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cập nhật địa chỉ thành công",
+		"data":    res,
+	})
+}
+
+// DeleteAddress xóa địa chỉ giao hàng
+// @Summary Xóa địa chỉ
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Address ID"
+// @Success 200 {object} map[string]string
+// @Router /me/addresses/{id} [delete]
+func (h *Handler) DeleteAddress(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	if err := h.service.DeleteAddress(c.Request.Context(), userID, uint(id)); err != nil {
+		if err == errors.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy địa chỉ"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Xóa địa chỉ thành công"})
+}
+
+// currentUserID đọc userID do AuthMiddleware gắn vào context
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
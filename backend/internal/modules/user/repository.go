@@ -17,6 +17,13 @@ type Repository interface {
 	CreateSession(ctx context.Context, session *Session) error
 	GetSessionByRefreshToken(ctx context.Context, refreshToken string) (*Session, error)
 	DeleteSession(ctx context.Context, id uuid.UUID) error
+
+	// Address book
+	GetAddresses(ctx context.Context, userID uuid.UUID) ([]Address, error)
+	GetAddress(ctx context.Context, userID uuid.UUID, id uint) (*Address, error)
+	GetDefaultAddress(ctx context.Context, userID uuid.UUID) (*Address, error)
+	SaveAddress(ctx context.Context, address *Address) error
+	DeleteAddress(ctx context.Context, userID uuid.UUID, id uint) (bool, error)
 }
 
 // repository implements Repository interface
@@ -67,3 +74,51 @@ func (r *repository) GetSessionByRefreshToken(ctx context.Context, refreshToken
 func (r *repository) DeleteSession(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&Session{}, id).Error
 }
+
+// GetAddresses trả về sổ địa chỉ, địa chỉ mặc định đứng đầu
+func (r *repository) GetAddresses(ctx context.Context, userID uuid.UUID) ([]Address, error) {
+	var addresses []Address
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_default DESC, id ASC").
+		Find(&addresses).Error
+	return addresses, err
+}
+
+func (r *repository) GetAddress(ctx context.Context, userID uuid.UUID, id uint) (*Address, error) {
+	var address Address
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&address, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (r *repository) GetDefaultAddress(ctx context.Context, userID uuid.UUID) (*Address, error) {
+	var address Address
+	err := r.db.WithContext(ctx).Where("user_id = ? AND is_default = ?", userID, true).First(&address).Error
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+// SaveAddress lưu địa chỉ; nếu là mặc định thì bỏ cờ mặc định của các địa chỉ khác
+func (r *repository) SaveAddress(ctx context.Context, address *Address) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if address.IsDefault {
+			err := tx.Model(&Address{}).
+				Where("user_id = ? AND id <> ?", address.UserID, address.ID).
+				Update("is_default", false).Error
+			if err != nil {
+				return err
+			}
+		}
+		return tx.Save(address).Error
+	})
+}
+
+func (r *repository) DeleteAddress(ctx context.Context, userID uuid.UUID, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&Address{}, id)
+	return result.RowsAffected > 0, result.Error
+}
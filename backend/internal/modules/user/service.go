@@ -10,6 +10,7 @@ import (
 	"go-ecommerce/pkg/token"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // Service interface định nghĩa các method mà tầng Handler sẽ gọi
@@ -19,6 +20,12 @@ type Service interface {
 	RefreshToken(ctx context.Context, refreshToken string) (*LoginResponse, error)
 	Logout(ctx context.Context, refreshToken string) error
 	GetProfile(ctx context.Context, userID uuid.UUID) (*UserResponse, error)
+
+	// Sổ địa chỉ
+	GetAddresses(ctx context.Context, userID uuid.UUID) ([]Address, error)
+	CreateAddress(ctx context.Context, userID uuid.UUID, req AddressRequest) (*Address, error)
+	UpdateAddress(ctx context.Context, userID uuid.UUID, id uint, req AddressRequest) (*Address, error)
+	DeleteAddress(ctx context.Context, userID uuid.UUID, id uint) error
 }
 
 // service struct implement interface trên
@@ -192,3 +199,77 @@ func (s *service) GetProfile(ctx context.Context, userID uuid.UUID) (*UserRespon
 		CreatedAt: user.CreatedAt,
 	}, nil
 }
+
+// GetAddresses lấy sổ địa chỉ của user
+func (s *service) GetAddresses(ctx context.Context, userID uuid.UUID) ([]Address, error) {
+	return s.repo.GetAddresses(ctx, userID)
+}
+
+// CreateAddress thêm địa chỉ; địa chỉ đầu tiên luôn là mặc định
+func (s *service) CreateAddress(ctx context.Context, userID uuid.UUID, req AddressRequest) (*Address, error) {
+	address := &Address{UserID: userID}
+	applyAddress(address, req)
+
+	if !address.IsDefault {
+		_, err := s.repo.GetDefaultAddress(ctx, userID)
+		if err == gorm.ErrRecordNotFound {
+			address.IsDefault = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.SaveAddress(ctx, address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// UpdateAddress sửa địa chỉ; không thể bỏ cờ mặc định, hãy chọn địa chỉ khác làm mặc định
+func (s *service) UpdateAddress(ctx context.Context, userID uuid.UUID, id uint, req AddressRequest) (*Address, error) {
+	address, err := s.repo.GetAddress(ctx, userID, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	wasDefault := address.IsDefault
+	applyAddress(address, req)
+	address.IsDefault = address.IsDefault || wasDefault
+
+	if err := s.repo.SaveAddress(ctx, address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// DeleteAddress xóa địa chỉ; nếu xóa địa chỉ mặc định thì địa chỉ cũ nhất còn lại thành mặc định
+// Đơn hàng lưu bản sao địa chỉ nên không bị ảnh hưởng.
+func (s *service) DeleteAddress(ctx context.Context, userID uuid.UUID, id uint) error {
+	address, err := s.repo.GetAddress(ctx, userID, id)
+	if err != nil {
+		return errors.ErrRecordNotFound
+	}
+	if _, err := s.repo.DeleteAddress(ctx, userID, id); err != nil {
+		return err
+	}
+	if !address.IsDefault {
+		return nil
+	}
+
+	remaining, err := s.repo.GetAddresses(ctx, userID)
+	if err != nil || len(remaining) == 0 {
+		return err
+	}
+	remaining[0].IsDefault = true
+	return s.repo.SaveAddress(ctx, &remaining[0])
+}
+
+func applyAddress(address *Address, req AddressRequest) {
+	address.RecipientName = req.RecipientName
+	address.RecipientPhone = req.RecipientPhone
+	address.Street = req.Street
+	address.City = req.City
+	address.District = req.District
+	address.Ward = req.Ward
+	address.IsDefault = req.IsDefault
+}
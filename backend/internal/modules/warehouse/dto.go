@@ -0,0 +1,11 @@
+package warehouse
+
+// WarehouseRequest - Request body for creating or updating a warehouse
+// Provinces replaces the list of provinces the warehouse ships to.
+type WarehouseRequest struct {
+	Code      string   `json:"code" binding:"required,max=20"`
+	Name      string   `json:"name" binding:"required,max=100"`
+	Address   string   `json:"address" binding:"max=255"`
+	IsDefault bool     `json:"is_default"`
+	Provinces []string `json:"provinces" binding:"dive,required,max=100"`
+}
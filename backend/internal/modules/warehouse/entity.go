@@ -0,0 +1,54 @@
+package warehouse
+
+import (
+	"strings"
+	"time"
+
+	"go-ecommerce/internal/shared/slug"
+)
+
+// Warehouse entity - a location stock is kept and shipped from
+type Warehouse struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Code      string    `gorm:"type:varchar(20);uniqueIndex;not null" json:"code"` // e.g. HN, HCM
+	Name      string    `gorm:"type:varchar(100);not null" json:"name"`
+	Address   string    `gorm:"type:varchar(255)" json:"address"`
+	IsDefault bool      `gorm:"default:false" json:"is_default"` // Ships to provinces no warehouse serves
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Provinces []Province `gorm:"foreignKey:WarehouseID;constraint:OnDelete:CASCADE" json:"provinces,omitempty"`
+}
+
+func (Warehouse) TableName() string {
+	return "warehouses"
+}
+
+// Province entity - a province served by a warehouse
+// Each province is served by at most one warehouse.
+type Province struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	WarehouseID uint   `gorm:"not null;index" json:"warehouse_id"`
+	Name        string `gorm:"type:varchar(100);not null" json:"name"`                              // As entered, e.g. "Hà Nội"
+	Key         string `gorm:"column:province_key;type:varchar(100);uniqueIndex;not null" json:"-"` // ProvinceKey(Name)
+}
+
+func (Province) TableName() string {
+	return "warehouse_provinces"
+}
+
+// provincePrefixes are dropped before comparing province names
+var provincePrefixes = []string{"thanh pho ", "tp. ", "tp.", "tp ", "tinh "}
+
+// ProvinceKey normalizes a province name for matching addresses
+// "TP. Hồ Chí Minh", "Thành phố Hồ Chí Minh" and "ho chi minh" all give "ho-chi-minh".
+func ProvinceKey(name string) string {
+	s := strings.ToLower(slug.RemoveAccents(strings.TrimSpace(name)))
+	for _, prefix := range provincePrefixes {
+		if strings.HasPrefix(s, prefix) {
+			s = s[len(prefix):]
+			break
+		}
+	}
+	return slug.Make(s)
+}
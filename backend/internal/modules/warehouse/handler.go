@@ -0,0 +1,102 @@
+package warehouse
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler handles warehouse HTTP requests
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new warehouse handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetAll handles GET /admin/warehouses
+// @Summary Danh sách kho
+// @Tags Warehouses
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} Warehouse
+// @Router /admin/warehouses [get]
+func (h *Handler) GetAll(c *gin.Context) {
+	res, err := h.service.GetAll(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách kho"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// Create handles POST /admin/warehouses
+// @Summary Thêm kho
+// @Description Đơn hàng được lấy từ kho phục vụ tỉnh/thành của địa chỉ giao hàng, không có thì từ kho mặc định
+// @Tags Warehouses
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body WarehouseRequest true "Kho"
+// @Success 201 {object} Warehouse
+// @Router /admin/warehouses [post]
+func (h *Handler) Create(c *gin.Context) {
+	var req WarehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Create(c.Request.Context(), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Thêm kho thành công",
+		"data":    res,
+	})
+}
+
+// Update handles PUT /admin/warehouses/:id
+func (h *Handler) Update(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	var req WarehouseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Update(c.Request.Context(), uint(id), req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cập nhật kho thành công",
+		"data":    res,
+	})
+}
+
+func respondError(c *gin.Context, err error) {
+	switch err {
+	case errors.ErrRecordNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy kho"})
+	case errors.ErrDuplicateWarehouse:
+		c.JSON(http.StatusConflict, gin.H{"error": "Mã kho đã tồn tại"})
+	case errors.ErrProvinceAssigned:
+		c.JSON(http.StatusConflict, gin.H{"error": "Tỉnh/thành đã thuộc kho khác"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+	}
+}
@@ -0,0 +1,100 @@
+package warehouse
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Repository interface
+type Repository interface {
+	GetAll(ctx context.Context) ([]Warehouse, error)
+	GetByID(ctx context.Context, id uint) (*Warehouse, error)
+	GetByCode(ctx context.Context, code string) (*Warehouse, error)
+	GetDefault(ctx context.Context) (*Warehouse, error)
+	GetProvinces(ctx context.Context, keys []string) ([]Province, error)
+
+	// Save stores the warehouse and replaces its provinces
+	Save(ctx context.Context, warehouse *Warehouse) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new warehouse repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func preloadProvinces(db *gorm.DB) *gorm.DB {
+	return db.Preload("Provinces", func(db *gorm.DB) *gorm.DB { return db.Order("name ASC") })
+}
+
+func (r *repository) GetAll(ctx context.Context) ([]Warehouse, error) {
+	var warehouses []Warehouse
+	err := r.db.WithContext(ctx).Scopes(preloadProvinces).Order("id ASC").Find(&warehouses).Error
+	return warehouses, err
+}
+
+func (r *repository) GetByID(ctx context.Context, id uint) (*Warehouse, error) {
+	var warehouse Warehouse
+	err := r.db.WithContext(ctx).Scopes(preloadProvinces).First(&warehouse, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *repository) GetByCode(ctx context.Context, code string) (*Warehouse, error) {
+	var warehouse Warehouse
+	err := r.db.WithContext(ctx).Where("code = ?", code).First(&warehouse).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *repository) GetDefault(ctx context.Context) (*Warehouse, error) {
+	var warehouse Warehouse
+	err := r.db.WithContext(ctx).Where("is_default = ?", true).First(&warehouse).Error
+	if err != nil {
+		return nil, err
+	}
+	return &warehouse, nil
+}
+
+func (r *repository) GetProvinces(ctx context.Context, keys []string) ([]Province, error) {
+	var provinces []Province
+	err := r.db.WithContext(ctx).Where("province_key IN ?", keys).Find(&provinces).Error
+	return provinces, err
+}
+
+// Save keeps a single default warehouse: saving one as default clears the flag on the others
+func (r *repository) Save(ctx context.Context, warehouse *Warehouse) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if warehouse.IsDefault {
+			err := tx.Model(&Warehouse{}).
+				Where("id <> ?", warehouse.ID).
+				Update("is_default", false).Error
+			if err != nil {
+				return err
+			}
+		}
+		if err := tx.Omit("Provinces").Save(warehouse).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("warehouse_id = ?", warehouse.ID).Delete(&Province{}).Error; err != nil {
+			return err
+		}
+		for i := range warehouse.Provinces {
+			warehouse.Provinces[i].ID = 0
+			warehouse.Provinces[i].WarehouseID = warehouse.ID
+		}
+		if len(warehouse.Provinces) > 0 {
+			return tx.Create(&warehouse.Provinces).Error
+		}
+		return nil
+	})
+}
@@ -0,0 +1,130 @@
+package warehouse
+
+import (
+	"context"
+	"strings"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"gorm.io/gorm"
+)
+
+// Service interface
+type Service interface {
+	GetAll(ctx context.Context) ([]Warehouse, error)
+	Create(ctx context.Context, req WarehouseRequest) (*Warehouse, error)
+	Update(ctx context.Context, id uint, req WarehouseRequest) (*Warehouse, error)
+
+	// EnsureDefault returns the default warehouse, creating one when there are no warehouses
+	EnsureDefault(ctx context.Context) (*Warehouse, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new warehouse service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) GetAll(ctx context.Context) ([]Warehouse, error) {
+	return s.repo.GetAll(ctx)
+}
+
+// Create adds a warehouse; the first one is always the default
+func (s *service) Create(ctx context.Context, req WarehouseRequest) (*Warehouse, error) {
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	if _, err := s.repo.GetByCode(ctx, code); err == nil {
+		return nil, errors.ErrDuplicateWarehouse
+	}
+
+	warehouse := &Warehouse{Code: code, Name: req.Name, Address: req.Address, IsDefault: req.IsDefault}
+	if !warehouse.IsDefault {
+		_, err := s.repo.GetDefault(ctx)
+		if err == gorm.ErrRecordNotFound {
+			warehouse.IsDefault = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	provinces, err := s.provinces(ctx, 0, req.Provinces)
+	if err != nil {
+		return nil, err
+	}
+	warehouse.Provinces = provinces
+
+	if err := s.repo.Save(ctx, warehouse); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, warehouse.ID)
+}
+
+// Update changes a warehouse; the default flag cannot be removed, make another warehouse default instead
+func (s *service) Update(ctx context.Context, id uint, req WarehouseRequest) (*Warehouse, error) {
+	warehouse, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	code := strings.ToUpper(strings.TrimSpace(req.Code))
+	if other, err := s.repo.GetByCode(ctx, code); err == nil && other.ID != id {
+		return nil, errors.ErrDuplicateWarehouse
+	}
+
+	provinces, err := s.provinces(ctx, id, req.Provinces)
+	if err != nil {
+		return nil, err
+	}
+
+	warehouse.Code = code
+	warehouse.Name = req.Name
+	warehouse.Address = req.Address
+	warehouse.IsDefault = warehouse.IsDefault || req.IsDefault
+	warehouse.Provinces = provinces
+
+	if err := s.repo.Save(ctx, warehouse); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *service) EnsureDefault(ctx context.Context) (*Warehouse, error) {
+	warehouse, err := s.repo.GetDefault(ctx)
+	if err != gorm.ErrRecordNotFound {
+		return warehouse, err
+	}
+	return s.Create(ctx, WarehouseRequest{Code: "HN", Name: "Kho Hà Nội", IsDefault: true, Provinces: []string{"Hà Nội"}})
+}
+
+// provinces builds the province list of a warehouse, rejecting duplicates and
+// provinces another warehouse already serves
+func (s *service) provinces(ctx context.Context, warehouseID uint, names []string) ([]Province, error) {
+	var provinces []Province
+	var keys []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		key := ProvinceKey(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		keys = append(keys, key)
+		provinces = append(provinces, Province{Name: strings.TrimSpace(name), Key: key})
+	}
+	if len(keys) == 0 {
+		return provinces, nil
+	}
+
+	taken, err := s.repo.GetProvinces(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range taken {
+		if p.WarehouseID != warehouseID {
+			return nil, errors.ErrProvinceAssigned
+		}
+	}
+	return provinces, nil
+}
@@ -0,0 +1,77 @@
+package wishlist
+
+import (
+	"context"
+	"fmt"
+
+	"go-ecommerce/internal/modules/product"
+	"go-ecommerce/internal/modules/user"
+
+	"github.com/google/uuid"
+)
+
+// ProductRepoAdapter adapts product.Repository to ProductGetter
+type ProductRepoAdapter struct {
+	repo product.Repository
+}
+
+func NewProductRepoAdapter(repo product.Repository) *ProductRepoAdapter {
+	return &ProductRepoAdapter{repo: repo}
+}
+
+// GetInfo returns the current state of a product, or of one of its variants
+func (a *ProductRepoAdapter) GetInfo(ctx context.Context, productID, variantID uint) (*ProductInfo, error) {
+	p, err := a.repo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ProductInfo{
+		ProductID: p.ID,
+		VariantID: variantID,
+		Name:      p.Name,
+		Slug:      p.Slug,
+		Stock:     p.TotalStock,
+		Available: p.Status == product.StatusPublished,
+	}
+	if len(p.Images) > 0 {
+		info.ImageURL = p.Images[0].ImageURL
+	}
+
+	if variantID == 0 {
+		// Whole product: cheapest variant
+		for i, v := range p.Variants {
+			if i == 0 || v.EffectivePrice().LessThan(info.Price) {
+				info.Price = v.EffectivePrice()
+			}
+		}
+		return info, nil
+	}
+
+	for _, v := range p.Variants {
+		if v.ID == variantID {
+			info.Variant = v.Size
+			info.Price = v.EffectivePrice()
+			info.Stock = v.Stock
+			return info, nil
+		}
+	}
+	return nil, fmt.Errorf("variant %d not found in product %d", variantID, productID)
+}
+
+// UserRepoAdapter adapts user.Repository to UserGetter
+type UserRepoAdapter struct {
+	repo user.Repository
+}
+
+func NewUserRepoAdapter(repo user.Repository) *UserRepoAdapter {
+	return &UserRepoAdapter{repo: repo}
+}
+
+func (a *UserRepoAdapter) GetEmail(ctx context.Context, id uuid.UUID) (string, error) {
+	u, err := a.repo.GetByID(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	return u.Email, nil
+}
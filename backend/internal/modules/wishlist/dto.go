@@ -0,0 +1,74 @@
+package wishlist
+
+import (
+	"time"
+
+	"go-ecommerce/pkg/money"
+)
+
+// AddItemRequest - Request body for saving a product or variant
+type AddItemRequest struct {
+	ProductID uint `json:"product_id" binding:"required"`
+	VariantID uint `json:"variant_id"` // Optional, 0 saves the whole product
+}
+
+// UpdatePreferenceRequest - Request body for alert opt-out
+type UpdatePreferenceRequest struct {
+	BackInStock *bool `json:"back_in_stock"`
+	PriceDrop   *bool `json:"price_drop"`
+}
+
+// ProductInfo - current state of a saved product or variant
+// For a whole product Price is the lowest variant price and Stock the total stock.
+type ProductInfo struct {
+	ProductID uint
+	VariantID uint
+	Name      string
+	Variant   string
+	Slug      string
+	Price     money.Money
+	Stock     int
+	ImageURL  string
+	Available bool // Published and not deleted
+}
+
+// WishlistItemResponse - Response DTO
+type WishlistItemResponse struct {
+	ID        uint        `json:"id"`
+	ProductID uint        `json:"product_id"`
+	VariantID uint        `json:"variant_id,omitempty"`
+	Name      string      `json:"name"`
+	Variant   string      `json:"variant,omitempty"`
+	Slug      string      `json:"slug"`
+	Price     money.Money `json:"price"`
+	Stock     int         `json:"stock"`
+	ImageURL  string      `json:"image_url"`
+	Available bool        `json:"available"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// PreferenceResponse - Response DTO
+type PreferenceResponse struct {
+	BackInStock bool `json:"back_in_stock"`
+	PriceDrop   bool `json:"price_drop"`
+}
+
+// ToWishlistItemResponse combines an item with the current product state
+func ToWishlistItemResponse(item *WishlistItem, info *ProductInfo) *WishlistItemResponse {
+	res := &WishlistItemResponse{
+		ID:        item.ID,
+		ProductID: item.ProductID,
+		VariantID: item.VariantID,
+		CreatedAt: item.CreatedAt,
+	}
+	if info != nil {
+		res.Name = info.Name
+		res.Variant = info.Variant
+		res.Slug = info.Slug
+		res.Price = info.Price
+		res.Stock = info.Stock
+		res.ImageURL = info.ImageURL
+		res.Available = info.Available
+	}
+	return res
+}
@@ -0,0 +1,39 @@
+package wishlist
+
+import (
+	"time"
+
+	"go-ecommerce/pkg/money"
+
+	"github.com/google/uuid"
+)
+
+// WishlistItem entity - a saved product, or one specific variant of it
+// VariantID is 0 when the whole product is saved. LastPrice/LastStock are what
+// the alert job saw last time; alerts fire when the current values move past them.
+type WishlistItem struct {
+	ID        uint        `gorm:"primaryKey" json:"id"`
+	UserID    uuid.UUID   `gorm:"type:uuid;not null;uniqueIndex:idx_wishlist_item,priority:1" json:"user_id"`
+	ProductID uint        `gorm:"not null;index;uniqueIndex:idx_wishlist_item,priority:2" json:"product_id"`
+	VariantID uint        `gorm:"not null;default:0;uniqueIndex:idx_wishlist_item,priority:3" json:"variant_id"`
+	LastPrice money.Money `gorm:"not null;default:0" json:"-"`
+	LastStock int         `gorm:"not null;default:0" json:"-"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+func (WishlistItem) TableName() string {
+	return "wishlist_items"
+}
+
+// AlertPreference entity - per-user opt-out of wishlist emails
+// Users without a row get every alert.
+type AlertPreference struct {
+	UserID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	BackInStock bool      `gorm:"not null" json:"back_in_stock"`
+	PriceDrop   bool      `gorm:"not null" json:"price_drop"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (AlertPreference) TableName() string {
+	return "wishlist_alert_preferences"
+}
@@ -0,0 +1,162 @@
+package wishlist
+
+import (
+	"net/http"
+	"strconv"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Handler handles wishlist HTTP requests
+type Handler struct {
+	service Service
+}
+
+// NewHandler creates a new wishlist handler
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetMine handles GET /me/wishlist
+// @Summary Danh sách yêu thích
+// @Tags Wishlist
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} WishlistItemResponse
+// @Router /me/wishlist [get]
+func (h *Handler) GetMine(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GetMine(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi lấy danh sách"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// Add handles POST /me/wishlist
+// @Summary Thêm vào danh sách yêu thích
+// @Description Lưu cả sản phẩm hoặc một biến thể cụ thể (variant_id)
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body AddItemRequest true "Sản phẩm"
+// @Success 201 {object} WishlistItemResponse
+// @Router /me/wishlist [post]
+func (h *Handler) Add(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req AddItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.Add(c.Request.Context(), userID, req)
+	if err != nil {
+		switch err {
+		case errors.ErrRecordNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy sản phẩm"})
+		case errors.ErrAlreadyInWishlist:
+			c.JSON(http.StatusConflict, gin.H{"error": "Sản phẩm đã có trong danh sách yêu thích"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Đã thêm vào danh sách yêu thích",
+		"data":    res,
+	})
+}
+
+// Remove handles DELETE /me/wishlist/:id
+func (h *Handler) Remove(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID không hợp lệ"})
+		return
+	}
+
+	if err := h.service.Remove(c.Request.Context(), userID, uint(id)); err != nil {
+		if err == errors.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Không tìm thấy sản phẩm trong danh sách yêu thích"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Đã xóa khỏi danh sách yêu thích"})
+}
+
+// GetPreference handles GET /me/wishlist/preferences
+func (h *Handler) GetPreference(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	res, err := h.service.GetPreference(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": res})
+}
+
+// UpdatePreference handles PUT /me/wishlist/preferences (email alert opt-out)
+func (h *Handler) UpdatePreference(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var req UpdatePreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	res, err := h.service.UpdatePreference(c.Request.Context(), userID, req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi hệ thống"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Cập nhật thành công",
+		"data":    res,
+	})
+}
+
+// currentUserID reads the user ID set by AuthMiddleware
+func currentUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return uuid.Nil, false
+	}
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid user ID"})
+		return uuid.Nil, false
+	}
+	return userID, true
+}
@@ -0,0 +1,126 @@
+package wishlist
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"go-ecommerce/internal/shared/jobs"
+	"go-ecommerce/pkg/mailer"
+)
+
+const alertBatchSize = 500
+
+// Notifier emails customers when a saved item is back in stock or cheaper
+// It compares the current state with the snapshot stored on each item, so it
+// catches changes from any source (admin edits, imports, orders).
+type Notifier struct {
+	repo     Repository
+	products ProductGetter
+	users    UserGetter
+	mailer   mailer.Mailer
+	interval time.Duration
+}
+
+// NewNotifier creates a new wishlist alert notifier
+func NewNotifier(repo Repository, products ProductGetter, users UserGetter, mailer mailer.Mailer, interval time.Duration) *Notifier {
+	return &Notifier{repo: repo, products: products, users: users, mailer: mailer, interval: interval}
+}
+
+// Start runs the notifier in the background until ctx is cancelled
+func (n *Notifier) Start(ctx context.Context) {
+	jobs.Every(ctx, "wishlist-alerts", n.interval, n.RunOnce)
+}
+
+// RunOnce checks every wishlist item once
+func (n *Notifier) RunOnce(ctx context.Context) error {
+	var afterID uint
+	for {
+		items, err := n.repo.GetBatchAfter(ctx, afterID, alertBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		// Several users often save the same product
+		infos := make(map[[2]uint]*ProductInfo)
+		for i := range items {
+			item := &items[i]
+			afterID = item.ID
+
+			key := [2]uint{item.ProductID, item.VariantID}
+			info, ok := infos[key]
+			if !ok {
+				info, _ = n.products.GetInfo(ctx, item.ProductID, item.VariantID)
+				infos[key] = info
+			}
+			if info == nil || !info.Available {
+				continue
+			}
+
+			n.check(ctx, item, info)
+		}
+	}
+}
+
+// check sends the alerts an item qualifies for and moves its snapshot forward
+func (n *Notifier) check(ctx context.Context, item *WishlistItem, info *ProductInfo) {
+	backInStock := item.LastStock <= 0 && info.Stock > 0
+	priceDrop := info.Price.LessThan(item.LastPrice)
+	if info.Price.Equal(item.LastPrice) && info.Stock == item.LastStock {
+		return
+	}
+
+	if backInStock || priceDrop {
+		if err := n.notify(ctx, item, info, backInStock, priceDrop); err != nil {
+			// Keep the old snapshot so the alert is retried next run
+			log.Printf("Wishlist: alert for item %d failed: %v", item.ID, err)
+			return
+		}
+	}
+
+	if err := n.repo.UpdateSnapshot(ctx, item.ID, info.Price, info.Stock); err != nil {
+		log.Printf("Wishlist: update snapshot of item %d failed: %v", item.ID, err)
+	}
+}
+
+func (n *Notifier) notify(ctx context.Context, item *WishlistItem, info *ProductInfo, backInStock, priceDrop bool) error {
+	pref, err := n.repo.GetPreference(ctx, item.UserID)
+	if err != nil {
+		return err
+	}
+	backInStock = backInStock && pref.BackInStock
+	priceDrop = priceDrop && pref.PriceDrop
+	if !backInStock && !priceDrop {
+		return nil
+	}
+
+	email, err := n.users.GetEmail(ctx, item.UserID)
+	if err != nil {
+		return err
+	}
+
+	name := info.Name
+	if info.Variant != "" {
+		name += " (" + info.Variant + ")"
+	}
+
+	var subject, body string
+	switch {
+	case backInStock && priceDrop:
+		subject = fmt.Sprintf("%s đã có hàng trở lại với giá mới", name)
+		body = fmt.Sprintf("%s đã có hàng trở lại, giá giảm từ %s xuống %s.\n", name, item.LastPrice, info.Price)
+	case backInStock:
+		subject = fmt.Sprintf("%s đã có hàng trở lại", name)
+		body = fmt.Sprintf("%s trong danh sách yêu thích của bạn đã có hàng trở lại.\n", name)
+	default:
+		subject = fmt.Sprintf("%s đang giảm giá", name)
+		body = fmt.Sprintf("Giá %s giảm từ %s xuống %s.\n", name, item.LastPrice, info.Price)
+	}
+	body += fmt.Sprintf("\nXem sản phẩm: /products/%s\n", info.Slug)
+
+	return n.mailer.Send(ctx, email, subject, body)
+}
@@ -0,0 +1,95 @@
+package wishlist
+
+import (
+	"context"
+
+	"go-ecommerce/pkg/money"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Repository interface
+type Repository interface {
+	Create(ctx context.Context, item *WishlistItem) error
+	Exists(ctx context.Context, userID uuid.UUID, productID, variantID uint) (bool, error)
+	GetByUser(ctx context.Context, userID uuid.UUID) ([]WishlistItem, error)
+	Delete(ctx context.Context, userID uuid.UUID, id uint) (bool, error)
+
+	// Alert job
+	GetBatchAfter(ctx context.Context, afterID uint, limit int) ([]WishlistItem, error)
+	UpdateSnapshot(ctx context.Context, id uint, price money.Money, stock int) error
+
+	// Preferences
+	GetPreference(ctx context.Context, userID uuid.UUID) (*AlertPreference, error)
+	SavePreference(ctx context.Context, pref *AlertPreference) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+// NewRepository creates a new wishlist repository
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, item *WishlistItem) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+func (r *repository) Exists(ctx context.Context, userID uuid.UUID, productID, variantID uint) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&WishlistItem{}).
+		Where("user_id = ? AND product_id = ? AND variant_id = ?", userID, productID, variantID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) GetByUser(ctx context.Context, userID uuid.UUID) ([]WishlistItem, error) {
+	var items []WishlistItem
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&items).Error
+	return items, err
+}
+
+// Delete removes an item of the user, reporting whether it existed
+func (r *repository) Delete(ctx context.Context, userID uuid.UUID, id uint) (bool, error) {
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&WishlistItem{}, id)
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetBatchAfter pages through all items by ID (keyset pagination)
+func (r *repository) GetBatchAfter(ctx context.Context, afterID uint, limit int) ([]WishlistItem, error) {
+	var items []WishlistItem
+	err := r.db.WithContext(ctx).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&items).Error
+	return items, err
+}
+
+func (r *repository) UpdateSnapshot(ctx context.Context, id uint, price money.Money, stock int) error {
+	return r.db.WithContext(ctx).Model(&WishlistItem{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"last_price": price,
+		"last_stock": stock,
+	}).Error
+}
+
+// GetPreference returns the user's preference, defaulting to all alerts on
+func (r *repository) GetPreference(ctx context.Context, userID uuid.UUID) (*AlertPreference, error) {
+	pref := AlertPreference{UserID: userID, BackInStock: true, PriceDrop: true}
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Limit(1).Find(&pref).Error
+	return &pref, err
+}
+
+func (r *repository) SavePreference(ctx context.Context, pref *AlertPreference) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"back_in_stock", "price_drop", "updated_at"}),
+	}).Create(pref).Error
+}
@@ -0,0 +1,123 @@
+package wishlist
+
+import (
+	"context"
+
+	"go-ecommerce/internal/shared/errors"
+
+	"github.com/google/uuid"
+)
+
+// ProductGetter interface for the current state of saved products
+type ProductGetter interface {
+	GetInfo(ctx context.Context, productID, variantID uint) (*ProductInfo, error)
+}
+
+// UserGetter interface for looking up alert recipients
+type UserGetter interface {
+	GetEmail(ctx context.Context, id uuid.UUID) (string, error)
+}
+
+// Service interface
+type Service interface {
+	GetMine(ctx context.Context, userID uuid.UUID) ([]WishlistItemResponse, error)
+	Add(ctx context.Context, userID uuid.UUID, req AddItemRequest) (*WishlistItemResponse, error)
+	Remove(ctx context.Context, userID uuid.UUID, id uint) error
+
+	// Alert preferences
+	GetPreference(ctx context.Context, userID uuid.UUID) (*PreferenceResponse, error)
+	UpdatePreference(ctx context.Context, userID uuid.UUID, req UpdatePreferenceRequest) (*PreferenceResponse, error)
+}
+
+type service struct {
+	repo     Repository
+	products ProductGetter
+}
+
+// NewService creates a new wishlist service
+func NewService(repo Repository, products ProductGetter) Service {
+	return &service{repo: repo, products: products}
+}
+
+// GetMine lists saved items with their current price and stock
+// Items whose product is gone are still listed, as unavailable.
+func (s *service) GetMine(ctx context.Context, userID uuid.UUID) ([]WishlistItemResponse, error) {
+	items, err := s.repo.GetByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []WishlistItemResponse{}
+	for i := range items {
+		info, _ := s.products.GetInfo(ctx, items[i].ProductID, items[i].VariantID)
+		responses = append(responses, *ToWishlistItemResponse(&items[i], info))
+	}
+	return responses, nil
+}
+
+// Add saves a published product or one of its variants
+func (s *service) Add(ctx context.Context, userID uuid.UUID, req AddItemRequest) (*WishlistItemResponse, error) {
+	info, err := s.products.GetInfo(ctx, req.ProductID, req.VariantID)
+	if err != nil || !info.Available {
+		return nil, errors.ErrRecordNotFound
+	}
+
+	exists, err := s.repo.Exists(ctx, userID, req.ProductID, req.VariantID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, errors.ErrAlreadyInWishlist
+	}
+
+	// The snapshot is the baseline for alerts
+	item := &WishlistItem{
+		UserID:    userID,
+		ProductID: req.ProductID,
+		VariantID: req.VariantID,
+		LastPrice: info.Price,
+		LastStock: info.Stock,
+	}
+	if err := s.repo.Create(ctx, item); err != nil {
+		return nil, err
+	}
+	return ToWishlistItemResponse(item, info), nil
+}
+
+func (s *service) Remove(ctx context.Context, userID uuid.UUID, id uint) error {
+	deleted, err := s.repo.Delete(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return errors.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *service) GetPreference(ctx context.Context, userID uuid.UUID) (*PreferenceResponse, error) {
+	pref, err := s.repo.GetPreference(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &PreferenceResponse{BackInStock: pref.BackInStock, PriceDrop: pref.PriceDrop}, nil
+}
+
+// UpdatePreference changes only the fields given in the request
+func (s *service) UpdatePreference(ctx context.Context, userID uuid.UUID, req UpdatePreferenceRequest) (*PreferenceResponse, error) {
+	pref, err := s.repo.GetPreference(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.BackInStock != nil {
+		pref.BackInStock = *req.BackInStock
+	}
+	if req.PriceDrop != nil {
+		pref.PriceDrop = *req.PriceDrop
+	}
+
+	if err := s.repo.SavePreference(ctx, pref); err != nil {
+		return nil, err
+	}
+	return &PreferenceResponse{BackInStock: pref.BackInStock, PriceDrop: pref.PriceDrop}, nil
+}
@@ -7,4 +7,87 @@ var (
 	ErrRecordNotFound      = errors.New("record not found")
 	ErrInvalidCredentials  = errors.New("invalid credentials")
 	ErrInternalServerError = errors.New("internal server error")
+
+	// Trash
+	ErrRestoreExpired = errors.New("retention window has passed, record can no longer be restored")
+	ErrParentDeleted  = errors.New("parent record is in trash, restore it first")
+
+	// Category
+	ErrCategoryHasProducts = errors.New("cannot delete category: products are using this category")
+	ErrCategoryHasChildren = errors.New("cannot delete category: it has child categories")
+	ErrInvalidParent       = errors.New("invalid parent category")
+
+	// Product
+	ErrProductHasNoOptions = errors.New("product has no options")
+	ErrDuplicateVariant    = errors.New("variant with these options already exists")
+	ErrInvalidVariant      = errors.New("invalid variant options")
+	ErrLastVariant         = errors.New("product must keep at least 1 variant")
+	ErrTooManyVariants     = errors.New("too many variants for one product")
+	ErrVariantReserved     = errors.New("variant has stock held or backordered for orders")
+	ErrLastImage           = errors.New("product must keep at least 1 image")
+	ErrTooManyImages       = errors.New("maximum 5 images allowed")
+	ErrInvalidImageOrder   = errors.New("image order must list every image of the product")
+	ErrProductNotReady     = errors.New("product is not ready to be published")
+	ErrInvalidSchedule     = errors.New("invalid publish schedule")
+	ErrInvalidPrice        = errors.New("sale price must be below the regular price and compare-at price above it")
+	ErrInvalidSalePeriod   = errors.New("sale must end in the future and after it starts")
+
+	// Inventory
+	ErrInvalidStockMovement = errors.New("quantity must be positive for receipt, return and damage")
+	ErrInvalidTransfer      = errors.New("transfer needs two different existing warehouses")
+	ErrInvalidBackorder     = errors.New("backorders need a limit above 0 and pre-orders an availability date")
+
+	// Warehouse
+	ErrDuplicateWarehouse = errors.New("warehouse code already exists")
+	ErrProvinceAssigned   = errors.New("province is already served by another warehouse")
+	ErrUnknownWarehouse   = errors.New("warehouse not found")
+
+	// Purchasing
+	ErrDuplicateSupplier = errors.New("supplier name already exists")
+	ErrUnknownSKU        = errors.New("no variant has this SKU")
+	ErrDuplicatePOLine   = errors.New("each SKU may appear only once per purchase order")
+	ErrInvalidCost       = errors.New("unit cost must be a non-negative amount in VND")
+	ErrPONotEditable     = errors.New("only draft purchase orders can be changed")
+	ErrPOStatus          = errors.New("purchase order cannot move to this status")
+	ErrInvalidReceipt    = errors.New("receipt lines must be distinct lines of the purchase order")
+	ErrOverReceipt       = errors.New("received quantity exceeds what is still outstanding on the line")
+
+	// Review
+	ErrNotPurchased    = errors.New("only customers who purchased this product can review it")
+	ErrAlreadyReviewed = errors.New("every purchase of this product has already been reviewed")
+	ErrAlreadyVoted    = errors.New("review already marked as helpful")
+	ErrOwnReview       = errors.New("cannot vote on your own review")
+
+	// Question
+	ErrNotBuyer = errors.New("only the seller or verified buyers can answer")
+
+	// Wishlist
+	ErrAlreadyInWishlist = errors.New("item already in wishlist")
+
+	// Currency
+	ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+	// Cart
+	ErrInsufficientStock = errors.New("not enough stock for the requested quantity")
+
+	// Order
+	ErrEmptyCart         = errors.New("cart is empty")
+	ErrCartChanged       = errors.New("cart has changed since it was last viewed, review it before checkout")
+	ErrInvalidTransition = errors.New("order cannot move to this status")
+	ErrOrderClosed       = errors.New("order is cancelled or returned")
+	ErrOrderNotDelivered = errors.New("order has not been delivered")
+
+	// Payment
+	ErrUnknownGateway     = errors.New("payment gateway is not available")
+	ErrGatewayUnavailable = errors.New("payment gateway could not be reached")
+	ErrOrderNotPayable    = errors.New("only pending orders within their reservation can be paid online")
+	ErrInvalidSignature   = errors.New("payment callback signature is invalid")
+	ErrPaymentAmount      = errors.New("paid amount does not match the payment")
+	ErrPaymentSettled     = errors.New("payment has already been settled")
+
+	// Cash on delivery
+	ErrCODUnavailable   = errors.New("cash on delivery is not available for this province")
+	ErrCODLimit         = errors.New("order total exceeds the cash on delivery limit for this province")
+	ErrDuplicateCODRule = errors.New("province already has a cash on delivery rule")
+	ErrInvalidCODRule   = errors.New("COD fee and limit must be non-negative amounts in VND")
 )
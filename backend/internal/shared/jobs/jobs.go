@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every runs fn immediately and then on every interval until ctx is cancelled
+// Errors are logged so a failing run does not stop the job.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := fn(ctx); err != nil {
+				log.Printf("Job %s failed: %v", name, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
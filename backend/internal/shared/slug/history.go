@@ -0,0 +1,124 @@
+package slug
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Entity types, also the table holding the current slug
+const (
+	EntityProduct  = "products"
+	EntityBrand    = "brands"
+	EntityCategory = "categories"
+)
+
+// History entity - a slug an entity used before it was renamed
+// Old slugs stay reserved so they keep redirecting to the entity.
+type History struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	EntityType string    `gorm:"type:varchar(50);not null;uniqueIndex:idx_slug_history,priority:1" json:"entity_type"`
+	Slug       string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_slug_history,priority:2" json:"slug"`
+	EntityID   uint      `gorm:"not null;index" json:"entity_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (History) TableName() string {
+	return "slug_histories"
+}
+
+// Registry hands out unique slugs and keeps slug history
+type Registry struct {
+	db *gorm.DB
+}
+
+// NewRegistry creates a new slug registry
+func NewRegistry(db *gorm.DB) *Registry {
+	return &Registry{db: db}
+}
+
+// Unique returns base, or base-2, base-3... if it is taken by another entity
+// of the same type, including trashed rows and old slugs in history.
+// Pass the entity's own ID as excludeID when renaming (0 when creating).
+// tx must be the transaction that stores the slug: each candidate is locked
+// until it commits, so concurrent creates of the same name wait for each other
+// and the later one moves on to the next suffix instead of hitting the unique index.
+func (r *Registry) Unique(tx *gorm.DB, entityType, base string, excludeID uint) (string, error) {
+	if base == "" {
+		base = "item"
+	}
+
+	candidate := base
+	for i := 2; ; i++ {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", entityType+"/"+candidate).Error; err != nil {
+			return "", err
+		}
+		taken, err := r.taken(tx, entityType, candidate, excludeID)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+func (r *Registry) taken(tx *gorm.DB, entityType, slug string, excludeID uint) (bool, error) {
+	var count int64
+	// Raw table query so soft-deleted rows count as taken
+	err := tx.Table(entityType).Where("slug = ? AND id <> ?", slug, excludeID).Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	err = tx.Model(&History{}).
+		Where("entity_type = ? AND slug = ? AND entity_id <> ?", entityType, slug, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Rename records oldSlug in history when an entity gets newSlug
+// If the entity takes back one of its own old slugs, that history row is dropped.
+func (r *Registry) Rename(tx *gorm.DB, entityType string, entityID uint, oldSlug, newSlug string) error {
+	if oldSlug == newSlug {
+		return nil
+	}
+
+	err := tx.Where("entity_type = ? AND slug = ? AND entity_id = ?", entityType, newSlug, entityID).
+		Delete(&History{}).Error
+	if err != nil {
+		return err
+	}
+
+	if oldSlug == "" {
+		return nil
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&History{
+		EntityType: entityType,
+		Slug:       oldSlug,
+		EntityID:   entityID,
+	}).Error
+}
+
+// Resolve finds the entity that used slug in the past
+func (r *Registry) Resolve(ctx context.Context, entityType, slug string) (uint, error) {
+	var history History
+	err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND slug = ?", entityType, slug).
+		First(&history).Error
+	if err != nil {
+		return 0, err
+	}
+	return history.EntityID, nil
+}
+
+// Forget removes all history of an entity (used when it is purged)
+func (r *Registry) Forget(ctx context.Context, entityType string, entityID uint) error {
+	return r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Delete(&History{}).Error
+}
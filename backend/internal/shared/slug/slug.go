@@ -0,0 +1,45 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// RemoveAccents removes Vietnamese diacritics
+// "Áo thun" -> "Ao thun", "Đồng hồ" -> "Dong ho"
+func RemoveAccents(s string) string {
+	t := transform.Chain(norm.NFD, transform.RemoveFunc(func(r rune) bool {
+		return unicode.Is(unicode.Mn, r) // Mn: Mark, nonspacing
+	}), norm.NFC)
+	result, _, _ := transform.String(t, s)
+
+	// Handle Đ/đ specifically (not a combining mark)
+	result = strings.ReplaceAll(result, "đ", "d")
+	result = strings.ReplaceAll(result, "Đ", "D")
+	return result
+}
+
+// Make creates a URL-friendly slug from a name
+// "Áo thun trắng" -> "ao-thun-trang", "Nike & Adidas!" -> "nike-adidas"
+func Make(name string) string {
+	s := strings.ToLower(RemoveAccents(strings.TrimSpace(name)))
+
+	var b strings.Builder
+	lastHyphen := true // Avoid a leading hyphen
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			lastHyphen = false
+			continue
+		}
+		// Everything else (spaces, punctuation, other scripts) becomes one hyphen
+		if !lastHyphen {
+			b.WriteRune('-')
+			lastHyphen = true
+		}
+	}
+	return strings.Trim(b.String(), "-")
+}
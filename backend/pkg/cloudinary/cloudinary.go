@@ -47,6 +47,23 @@ func (c *Client) Upload(ctx context.Context, file multipart.File, folder string)
 	}, nil
 }
 
+// UploadURL uploads a remote file by URL; Cloudinary fetches it directly
+func (c *Client) UploadURL(ctx context.Context, url string, folder string) (*UploadResult, error) {
+	uploadParams := uploader.UploadParams{
+		Folder: folder,
+	}
+
+	result, err := c.cld.Upload.Upload(ctx, url, uploadParams)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UploadResult{
+		URL:      result.SecureURL,
+		PublicID: result.PublicID,
+	}, nil
+}
+
 // Delete removes a file from Cloudinary
 func (c *Client) Delete(ctx context.Context, publicID string) error {
 	_, err := c.cld.Upload.Destroy(ctx, uploader.DestroyParams{
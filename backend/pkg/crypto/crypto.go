@@ -1,6 +1,7 @@
 package crypto
 
 import (
+	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
 )
@@ -13,3 +14,12 @@ func HashPassword(password string) string {
 func ComparePassword(hashedPassword, password string) bool {
 	return HashPassword(password) == hashedPassword
 }
+
+// RandomToken returns n random bytes hex-encoded, for opaque identifiers
+func RandomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,90 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"net/mail"
+	"net/smtp"
+	"strings"
+
+	"go-ecommerce/internal/config"
+)
+
+// Mailer sends plain-text emails
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
+
+// New returns an SMTP mailer, or a log-only mailer when no host is configured
+func New(cfg *config.MailConfig) Mailer {
+	if cfg.Host == "" {
+		return &LogMailer{}
+	}
+	return &SMTPMailer{cfg: cfg}
+}
+
+// SMTPMailer sends through an SMTP server with PLAIN auth
+type SMTPMailer struct {
+	cfg *config.MailConfig
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, to, subject, body string) error {
+	addr := fmt.Sprintf("%s:%d", m.cfg.Host, m.cfg.Port)
+
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.cfg.Host)
+	}
+
+	from, rcpt, msg, err := buildMessage(m.cfg.From, to, subject, body)
+	if err != nil {
+		return err
+	}
+	if err := smtp.SendMail(addr, auth, from, []string{rcpt}, msg); err != nil {
+		return fmt.Errorf("send mail to %s: %w", rcpt, err)
+	}
+	return nil
+}
+
+// buildMessage returns the envelope addresses and the message
+// Header values come from product and user text: addresses must parse on their own,
+// line breaks in the subject are dropped and non-ASCII is RFC 2047 encoded.
+func buildMessage(from, to, subject, body string) (string, string, []byte, error) {
+	sender, err := parseAddress(from)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid sender %q: %w", from, err)
+	}
+	rcpt, err := parseAddress(to)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("invalid recipient %q: %w", to, err)
+	}
+	subject = strings.Join(strings.FieldsFunc(subject, func(r rune) bool { return r == '\r' || r == '\n' }), " ")
+
+	msg := strings.Join([]string{
+		"From: " + sender.String(), // String encodes a non-ASCII display name
+		"To: " + rcpt.String(),
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+	return sender.Address, rcpt.Address, []byte(msg), nil
+}
+
+func parseAddress(s string) (*mail.Address, error) {
+	if strings.ContainsAny(s, "\r\n") {
+		return nil, fmt.Errorf("contains a line break")
+	}
+	return mail.ParseAddress(s)
+}
+
+// LogMailer only logs emails (local development)
+type LogMailer struct{}
+
+func (m *LogMailer) Send(ctx context.Context, to, subject, body string) error {
+	log.Printf("Mail to %s: %s\n%s", to, subject, body)
+	return nil
+}
@@ -0,0 +1,84 @@
+package mailer
+
+import (
+	"mime"
+	"strings"
+	"testing"
+)
+
+func TestBuildMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		from, to    string
+		subject     string
+		wantFrom    string
+		wantTo      string
+		wantHeaders []string
+		wantErr     string
+	}{
+		{
+			name: "ascii", from: "no-reply@shop.vn", to: "khach@example.com", subject: "Order shipped",
+			wantFrom: "no-reply@shop.vn", wantTo: "khach@example.com",
+			wantHeaders: []string{"From: <no-reply@shop.vn>", "To: <khach@example.com>", "Subject: Order shipped"},
+		},
+		{
+			name: "vietnamese subject and sender name", from: "Cửa hàng <no-reply@shop.vn>", to: "khach@example.com", subject: "Áo thun đã có hàng",
+			wantFrom: "no-reply@shop.vn", wantTo: "khach@example.com",
+			wantHeaders: []string{"From: =?utf-8?q?C=E1=BB=ADa_h=C3=A0ng?= <no-reply@shop.vn>", "Subject: =?utf-8?q?"},
+		},
+		{
+			name: "line breaks in the subject", from: "no-reply@shop.vn", to: "khach@example.com", subject: "Áo\r\nBcc: victim@example.com",
+			wantFrom: "no-reply@shop.vn", wantTo: "khach@example.com",
+		},
+		{name: "header injected through the recipient", from: "no-reply@shop.vn", to: "khach@example.com\r\nBcc: victim@example.com", subject: "x", wantErr: "invalid recipient"},
+		{name: "header injected through the sender", from: "no-reply@shop.vn\nBcc: victim@example.com", to: "khach@example.com", subject: "x", wantErr: "invalid sender"},
+		{name: "two recipients", from: "no-reply@shop.vn", to: "a@example.com, b@example.com", subject: "x", wantErr: "invalid recipient"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, msg, err := buildMessage(tt.from, tt.to, tt.subject, "Nội dung")
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if from != tt.wantFrom || to != tt.wantTo {
+				t.Errorf("envelope = %s -> %s, want %s -> %s", from, to, tt.wantFrom, tt.wantTo)
+			}
+
+			header, body, _ := strings.Cut(string(msg), "\r\n\r\n")
+			if body != "Nội dung" {
+				t.Errorf("body = %q", body)
+			}
+			lines := strings.Split(header, "\r\n")
+			if len(lines) != 5 {
+				t.Fatalf("header has %d lines, want 5: %q", len(lines), lines)
+			}
+			for _, line := range lines {
+				if strings.ContainsAny(line, "\r\n") || strings.HasPrefix(line, "Bcc") {
+					t.Errorf("injected header line %q", line)
+				}
+				if strings.HasPrefix(line, "Subject: ") {
+					decoded, err := new(mime.WordDecoder).DecodeHeader(strings.TrimPrefix(line, "Subject: "))
+					if err != nil {
+						t.Errorf("decode subject: %v", err)
+					}
+					want := strings.NewReplacer("\r\n", " ").Replace(tt.subject)
+					if decoded != want {
+						t.Errorf("subject decodes to %q, want %q", decoded, want)
+					}
+				}
+			}
+			for _, want := range tt.wantHeaders {
+				if !strings.Contains(header, want) {
+					t.Errorf("header %q does not contain %q", header, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package money
+
+import (
+	"strings"
+)
+
+// style describes how a currency is written
+type style struct {
+	symbol    string
+	prefix    bool // Symbol before the number
+	thousands string
+	decimal   string
+}
+
+var styles = map[Currency]style{
+	VND: {symbol: "₫", thousands: ".", decimal: ","},
+	USD: {symbol: "$", prefix: true, thousands: ",", decimal: "."},
+	EUR: {symbol: "€", thousands: ".", decimal: ","},
+}
+
+// Format writes an amount for display: "350.000 ₫", "$19.99", "1.234,50 €"
+// Currencies without a style fall back to "1,234.50 XYZ".
+func Format(m Money) string {
+	cur := m.currency()
+	st, ok := styles[cur]
+	if !ok {
+		st = style{symbol: string(cur), thousands: ",", decimal: "."}
+	}
+
+	number := groupDecimal(m.Decimal(), st.thousands, st.decimal)
+	if st.prefix {
+		if strings.HasPrefix(number, "-") {
+			return "-" + st.symbol + number[1:]
+		}
+		return st.symbol + number
+	}
+	return number + " " + st.symbol
+}
+
+// FormatVND formats an amount in đồng, e.g. FormatVND(350000) = "350.000 ₫"
+func FormatVND(amount int64) string {
+	return Format(VNDOf(amount))
+}
+
+// groupDecimal inserts thousands separators into a "-1234.56" style string
+func groupDecimal(s, thousands, decimal string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac, hasFrac := strings.Cut(s, ".")
+
+	var b strings.Builder
+	for i, d := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteString(thousands)
+		}
+		b.WriteRune(d)
+	}
+	if hasFrac {
+		b.WriteString(decimal)
+		b.WriteString(frac)
+	}
+	return sign + b.String()
+}
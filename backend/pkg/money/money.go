@@ -0,0 +1,310 @@
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code
+type Currency string
+
+const (
+	VND Currency = "VND"
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+
+	// Default is the store currency; every amount stored in the database is in it
+	Default = VND
+)
+
+// exponents lists the number of minor-unit digits; unknown currencies use 2
+var exponents = map[Currency]int{
+	VND:   0,
+	USD:   2,
+	EUR:   2,
+	"JPY": 0,
+	"KRW": 0,
+}
+
+// Exponent returns the number of digits after the decimal point
+func (c Currency) Exponent() int {
+	if exp, ok := exponents[c]; ok {
+		return exp
+	}
+	return 2
+}
+
+// Money is an exact amount in minor units of its currency (đồng for VND, cents for USD)
+// The zero value is 0 in the Default currency.
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+// New returns amount minor units of currency
+func New(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// VNDOf returns an amount in đồng
+func VNDOf(amount int64) Money {
+	return Money{Amount: amount, Currency: VND}
+}
+
+// Parse reads a decimal amount in major units, e.g. "350000" VND or "19.99" USD
+// Amounts with more decimals than the currency allows are rejected rather than rounded.
+func Parse(s string, currency Currency) (Money, error) {
+	if currency == "" {
+		currency = Default
+	}
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return Money{}, fmt.Errorf("money: invalid amount %q", s)
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(currency.Exponent())), nil)
+	r.Mul(r, new(big.Rat).SetInt(scale))
+	if !r.IsInt() {
+		return Money{}, fmt.Errorf("money: %q has too many decimals for %s", s, currency)
+	}
+	if !r.Num().IsInt64() {
+		return Money{}, fmt.Errorf("money: %q is out of range", s)
+	}
+	return Money{Amount: r.Num().Int64(), Currency: currency}, nil
+}
+
+// currency returns the currency, treating empty as Default
+func (m Money) currency() Currency {
+	if m.Currency == "" {
+		return Default
+	}
+	return m.Currency
+}
+
+// ErrCurrencyMismatch is returned when amounts in different currencies are combined
+var ErrCurrencyMismatch = errors.New("money: currency mismatch")
+
+// match returns ErrCurrencyMismatch unless both amounts are in the same currency
+func (m Money) match(o Money) error {
+	if m.currency() != o.currency() {
+		return fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.currency(), o.currency())
+	}
+	return nil
+}
+
+// mustMatch panics on mixed currencies
+// Add, Sub and Cmp are for amounts known to be in Default, e.g. loaded from the
+// database or checked with IsValidAmount; anything else goes through Plus and Compare.
+func (m Money) mustMatch(o Money) {
+	if err := m.match(o); err != nil {
+		panic(err.Error())
+	}
+}
+
+// Add returns m + o; both must be in the same currency
+func (m Money) Add(o Money) Money {
+	m.mustMatch(o)
+	return Money{Amount: m.Amount + o.Amount, Currency: m.currency()}
+}
+
+// Plus returns m + o, or ErrCurrencyMismatch
+func (m Money) Plus(o Money) (Money, error) {
+	if err := m.match(o); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount + o.Amount, Currency: m.currency()}, nil
+}
+
+// Sub returns m - o; both must be in the same currency
+func (m Money) Sub(o Money) Money {
+	m.mustMatch(o)
+	return Money{Amount: m.Amount - o.Amount, Currency: m.currency()}
+}
+
+// Mul returns m multiplied by a quantity
+func (m Money) Mul(n int64) Money {
+	return Money{Amount: m.Amount * n, Currency: m.currency()}
+}
+
+// MulRatio returns m * num / den rounded half away from zero, e.g. MulRatio(8, 100) for 8% VAT
+func (m Money) MulRatio(num, den int64) Money {
+	r := new(big.Rat).SetFrac(new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(num)), big.NewInt(den))
+	q, rem := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	// Round half away from zero: |2*rem| >= denom
+	if new(big.Int).Abs(new(big.Int).Mul(rem, big.NewInt(2))).Cmp(r.Denom()) >= 0 {
+		if r.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	return Money{Amount: q.Int64(), Currency: m.currency()}
+}
+
+// Cmp returns -1, 0 or 1; both must be in the same currency
+func (m Money) Cmp(o Money) int {
+	m.mustMatch(o)
+	c, _ := m.Compare(o)
+	return c
+}
+
+// Compare returns -1, 0 or 1, or ErrCurrencyMismatch
+func (m Money) Compare(o Money) (int, error) {
+	if err := m.match(o); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < o.Amount:
+		return -1, nil
+	case m.Amount > o.Amount:
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (m Money) Equal(o Money) bool       { return m.currency() == o.currency() && m.Amount == o.Amount }
+func (m Money) LessThan(o Money) bool    { return m.Cmp(o) < 0 }
+func (m Money) GreaterThan(o Money) bool { return m.Cmp(o) > 0 }
+func (m Money) IsZero() bool             { return m.Amount == 0 }
+func (m Money) IsNegative() bool         { return m.Amount < 0 }
+
+// IsValidAmount reports whether m can be stored as a price, cost or fee:
+// not negative and in Default
+func (m Money) IsValidAmount() bool {
+	return !m.IsNegative() && m.currency() == Default
+}
+
+// Float64 returns the amount in major units, for spreadsheets and charts only
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / math.Pow10(m.currency().Exponent())
+}
+
+// Decimal returns the amount in major units as an exact string, e.g. "19.99"
+func (m Money) Decimal() string {
+	exp := m.currency().Exponent()
+	digits := strconv.FormatInt(abs(m.Amount), 10)
+	sign := ""
+	if m.Amount < 0 {
+		sign = "-"
+	}
+	if exp == 0 {
+		return sign + digits
+	}
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-exp] + "." + digits[len(digits)-exp:]
+}
+
+// String formats the amount for display, see Format
+func (m Money) String() string {
+	return Format(m)
+}
+
+// jsonMoney is the wire format: minor units, currency and a display string
+type jsonMoney struct {
+	Amount    int64    `json:"amount"`
+	Currency  Currency `json:"currency"`
+	Formatted string   `json:"formatted,omitempty"`
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonMoney{Amount: m.Amount, Currency: m.currency(), Formatted: Format(m)})
+}
+
+// UnmarshalJSON accepts the object form {"amount": 350000, "currency": "VND"}
+// (amount in minor units) or a plain number/string in major units of Default.
+// Only Default amounts are accepted: inputs are compared with and added to
+// stored prices, which are all in Default.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) > 0 && data[0] == '{':
+		var v jsonMoney
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		if v.Currency != "" && v.Currency != Default {
+			return fmt.Errorf("money: amounts must be in %s, got %s", Default, v.Currency)
+		}
+		*m = Money{Amount: v.Amount, Currency: Default}
+		return nil
+	case len(data) > 0 && data[0] == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		return m.UnmarshalParam(s)
+	default:
+		return m.UnmarshalParam(string(data))
+	}
+}
+
+// UnmarshalParam lets gin bind form and query values in major units of Default
+// An empty value is zero.
+func (m *Money) UnmarshalParam(param string) error {
+	if strings.TrimSpace(param) == "" {
+		*m = Money{Currency: Default}
+		return nil
+	}
+	parsed, err := Parse(param, Default)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// GormDataType stores money as a bigint of minor units
+func (Money) GormDataType() string {
+	return "bigint"
+}
+
+// Value stores the minor units; only Default amounts can be stored
+func (m Money) Value() (driver.Value, error) {
+	if m.currency() != Default {
+		return nil, fmt.Errorf("money: cannot store %s amount in a %s column", m.Currency, Default)
+	}
+	return m.Amount, nil
+}
+
+// Scan reads minor units of Default
+func (m *Money) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case int64:
+		*m = Money{Amount: v, Currency: Default}
+	case []byte:
+		return m.scanString(string(v))
+	case string:
+		return m.scanString(v)
+	case float64:
+		*m = Money{Amount: int64(math.Round(v)), Currency: Default}
+	default:
+		return fmt.Errorf("money: cannot scan %T", value)
+	}
+	return nil
+}
+
+// scanString handles numeric columns, which drivers return as text
+func (m *Money) scanString(s string) error {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || !r.IsInt() || !r.Num().IsInt64() {
+		return fmt.Errorf("money: cannot scan %q", s)
+	}
+	*m = Money{Amount: r.Num().Int64(), Currency: Default}
+	return nil
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
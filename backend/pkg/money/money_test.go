@@ -0,0 +1,275 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in       string
+		currency Currency
+		want     Money
+		wantErr  bool
+	}{
+		{in: "350000", currency: VND, want: VNDOf(350000)},
+		{in: " 350000 ", currency: "", want: VNDOf(350000)},
+		{in: "0", currency: VND, want: VNDOf(0)},
+		{in: "-5000", currency: VND, want: VNDOf(-5000)},
+		{in: "19.99", currency: USD, want: New(1999, USD)},
+		{in: "19.9", currency: USD, want: New(1990, USD)},
+		{in: "350000.5", currency: VND, wantErr: true},
+		{in: "19.999", currency: USD, wantErr: true},
+		{in: "abc", currency: VND, wantErr: true},
+		{in: "", currency: VND, wantErr: true},
+		{in: "99999999999999999999", currency: VND, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in, tt.currency)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) err = %v, want error %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   Money
+		json string
+	}{
+		{name: "vnd", in: VNDOf(350000), json: `{"amount":350000,"currency":"VND","formatted":"350.000 ₫"}`},
+		{name: "zero value", in: Money{}, json: `{"amount":0,"currency":"VND","formatted":"0 ₫"}`},
+		{name: "negative", in: VNDOf(-15000), json: `{"amount":-15000,"currency":"VND","formatted":"-15.000 ₫"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.in)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tt.json {
+				t.Errorf("Marshal = %s, want %s", data, tt.json)
+			}
+			var back Money
+			if err := json.Unmarshal(data, &back); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !back.Equal(tt.in) || back.Currency != Default {
+				t.Errorf("round trip = %+v, want %+v", back, tt.in)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    Money
+		wantErr bool
+	}{
+		{name: "object", json: `{"amount":350000,"currency":"VND"}`, want: VNDOf(350000)},
+		{name: "object without currency", json: `{"amount":350000}`, want: VNDOf(350000)},
+		{name: "number", json: `350000`, want: VNDOf(350000)},
+		{name: "string", json: `"350000"`, want: VNDOf(350000)},
+		{name: "empty string", json: `""`, want: VNDOf(0)},
+		{name: "object in USD", json: `{"amount":1999,"currency":"USD"}`, wantErr: true},
+		{name: "object in unknown currency", json: `{"amount":1,"currency":"XYZ"}`, wantErr: true},
+		{name: "decimals", json: `350000.5`, wantErr: true},
+		{name: "not a number", json: `"abc"`, wantErr: true},
+		{name: "bool", json: `true`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := json.Unmarshal([]byte(tt.json), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Unmarshal(%s) err = %v, want error %v", tt.json, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("Unmarshal(%s) = %+v, want %+v", tt.json, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalInStruct(t *testing.T) {
+	var req struct {
+		Price *Money `json:"price"`
+		Fee   Money  `json:"fee"`
+	}
+	if err := json.Unmarshal([]byte(`{"price":{"amount":5,"currency":"EUR"}}`), &req); err == nil {
+		t.Fatal("EUR price was accepted")
+	}
+	if err := json.Unmarshal([]byte(`{"price":"199000","fee":15000}`), &req); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if *req.Price != VNDOf(199000) || req.Fee != VNDOf(15000) {
+		t.Errorf("got price %+v fee %+v", *req.Price, req.Fee)
+	}
+}
+
+func TestSQL(t *testing.T) {
+	v, err := VNDOf(350000).Value()
+	if err != nil || v != int64(350000) {
+		t.Errorf("Value = %v, %v; want 350000", v, err)
+	}
+	if _, err := New(1999, USD).Value(); err == nil {
+		t.Error("USD amount was stored")
+	}
+
+	tests := []struct {
+		name    string
+		in      interface{}
+		want    Money
+		wantErr bool
+	}{
+		{name: "bigint", in: int64(350000), want: VNDOf(350000)},
+		{name: "numeric text", in: []byte("350000"), want: VNDOf(350000)},
+		{name: "numeric string", in: "-15000", want: VNDOf(-15000)},
+		{name: "float", in: float64(350000), want: VNDOf(350000)},
+		{name: "fraction", in: "1.5", wantErr: true},
+		{name: "null", in: nil, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			err := got.Scan(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan(%v) err = %v, want error %v", tt.in, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("Scan(%v) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{in: VNDOf(0), want: "0 ₫"},
+		{in: VNDOf(999), want: "999 ₫"},
+		{in: VNDOf(350000), want: "350.000 ₫"},
+		{in: VNDOf(1234567), want: "1.234.567 ₫"},
+		{in: VNDOf(-15000), want: "-15.000 ₫"},
+		{in: Money{Amount: 1000}, want: "1.000 ₫"},
+		{in: New(1999, USD), want: "$19.99"},
+		{in: New(5, USD), want: "$0.05"},
+		{in: New(-123456, USD), want: "-$1,234.56"},
+		{in: New(123450, EUR), want: "1.234,50 €"},
+		{in: New(1500, "JPY"), want: "1,500 JPY"},
+		{in: New(123456, "GBP"), want: "1,234.56 GBP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := Format(tt.in); got != tt.want {
+				t.Errorf("Format(%+v) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+	if got := FormatVND(350000); got != "350.000 ₫" {
+		t.Errorf("FormatVND = %q", got)
+	}
+}
+
+func TestMulRatio(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		num, den int64
+		want     int64
+	}{
+		{amount: 100000, num: 8, den: 100, want: 8000},
+		{amount: 12345, num: 8, den: 100, want: 988},   // 987.6
+		{amount: 50, num: 1, den: 100, want: 1},        // 0.5 rounds away from zero
+		{amount: -50, num: 1, den: 100, want: -1},      // -0.5 too
+		{amount: 149, num: 1, den: 100, want: 1},       // 1.49
+		{amount: 350000, num: 1, den: 3, want: 116667}, // 116666.67
+	}
+	for _, tt := range tests {
+		if got := VNDOf(tt.amount).MulRatio(tt.num, tt.den); got.Amount != tt.want {
+			t.Errorf("%d * %d/%d = %d, want %d", tt.amount, tt.num, tt.den, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	a, b := VNDOf(350000), VNDOf(15000)
+	if got := a.Add(b); got != VNDOf(365000) {
+		t.Errorf("Add = %+v", got)
+	}
+	if got := a.Sub(b); got != VNDOf(335000) {
+		t.Errorf("Sub = %+v", got)
+	}
+	if got := b.Mul(3); got != VNDOf(45000) {
+		t.Errorf("Mul = %+v", got)
+	}
+	if !b.LessThan(a) || !a.GreaterThan(b) || a.Cmp(a) != 0 {
+		t.Error("comparisons are wrong")
+	}
+	if !(Money{}).Equal(VNDOf(0)) || VNDOf(1).Equal(New(1, USD)) {
+		t.Error("Equal is wrong")
+	}
+}
+
+func TestIsValidAmount(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want bool
+	}{
+		{in: VNDOf(350000), want: true},
+		{in: VNDOf(0), want: true},
+		{in: Money{Amount: 15000}, want: true},
+		{in: VNDOf(-1), want: false},
+		{in: New(1999, USD), want: false},
+		{in: New(0, EUR), want: false},
+	}
+	for _, tt := range tests {
+		if got := tt.in.IsValidAmount(); got != tt.want {
+			t.Errorf("%+v.IsValidAmount() = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestCurrencyMismatch(t *testing.T) {
+	vnd, usd := VNDOf(25000), New(100, USD)
+
+	if _, err := vnd.Plus(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Plus err = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := vnd.Compare(usd); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Compare err = %v, want ErrCurrencyMismatch", err)
+	}
+	if got, err := vnd.Plus(Money{Amount: 5000}); err != nil || got != VNDOf(30000) {
+		t.Errorf("Plus with the zero currency = %+v, %v", got, err)
+	}
+	if c, err := vnd.Compare(VNDOf(30000)); err != nil || c != -1 {
+		t.Errorf("Compare = %d, %v", c, err)
+	}
+
+	for name, op := range map[string]func(){
+		"Add": func() { vnd.Add(usd) },
+		"Sub": func() { vnd.Sub(usd) },
+		"Cmp": func() { vnd.Cmp(usd) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if r == nil || !strings.Contains(r.(string), "currency mismatch") {
+					t.Errorf("%s did not panic on mixed currencies: %v", name, r)
+				}
+			}()
+			op()
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"go-ecommerce/internal/config"
+	"go-ecommerce/pkg/mailer"
+)
+
+// Channel delivers operational alerts to the shop staff
+type Channel interface {
+	Notify(ctx context.Context, subject, body string) error
+}
+
+// New returns the channel selected by cfg.Channel ("email" or "webhook")
+// Anything else, or a channel without its address, only logs.
+func New(cfg *config.NotifyConfig, m mailer.Mailer) Channel {
+	switch {
+	case cfg.Channel == "email" && cfg.Email != "":
+		return &EmailChannel{mailer: m, to: cfg.Email}
+	case cfg.Channel == "webhook" && cfg.WebhookURL != "":
+		return &WebhookChannel{url: cfg.WebhookURL, client: &http.Client{Timeout: 10 * time.Second}}
+	}
+	return &LogChannel{}
+}
+
+// EmailChannel mails alerts to one address, e.g. a shared inbox
+type EmailChannel struct {
+	mailer mailer.Mailer
+	to     string
+}
+
+func (c *EmailChannel) Notify(ctx context.Context, subject, body string) error {
+	return c.mailer.Send(ctx, c.to, subject, body)
+}
+
+// WebhookChannel posts alerts as JSON; "text" makes it work with Slack-style incoming webhooks
+type WebhookChannel struct {
+	url    string
+	client *http.Client
+}
+
+func (c *WebhookChannel) Notify(ctx context.Context, subject, body string) error {
+	payload, err := json.Marshal(map[string]string{
+		"subject": subject,
+		"body":    body,
+		"text":    subject + "\n" + body,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("post webhook: status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// LogChannel only logs alerts (local development)
+type LogChannel struct{}
+
+func (c *LogChannel) Notify(ctx context.Context, subject, body string) error {
+	log.Printf("Alert: %s\n%s", subject, body)
+	return nil
+}
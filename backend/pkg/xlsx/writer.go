@@ -0,0 +1,156 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`
+
+	rootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+
+	workbookRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+
+	workbookXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+	sheetHeaderXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	sheetFooterXML = `</sheetData></worksheet>`
+)
+
+// Writer streams a single-sheet workbook row by row
+// Rows go straight to the underlying writer, so memory use does not grow with the sheet.
+type Writer struct {
+	zw    *zip.Writer
+	sheet *bufio.Writer
+}
+
+// NewWriter starts a workbook with one sheet named sheetName
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	zw := zip.NewWriter(w)
+
+	var escaped bufferString
+	if err := xml.EscapeText(&escaped, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", fmt.Sprintf(workbookXML, escaped)},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML},
+	}
+	for _, p := range parts {
+		f, err := zw.Create(p.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, p.body); err != nil {
+			return nil, err
+		}
+	}
+
+	// The sheet is the last zip entry and stays open until Close
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	sheet := bufio.NewWriter(f)
+	if _, err := sheet.WriteString(sheetHeaderXML); err != nil {
+		return nil, err
+	}
+
+	return &Writer{zw: zw, sheet: sheet}, nil
+}
+
+// WriteRow appends a row; ints and floats become number cells, everything else text
+func (w *Writer) WriteRow(values []interface{}) error {
+	if _, err := w.sheet.WriteString("<row>"); err != nil {
+		return err
+	}
+	for _, v := range values {
+		var err error
+		switch n := v.(type) {
+		case int:
+			err = w.writeNumber(strconv.Itoa(n))
+		case int64:
+			err = w.writeNumber(strconv.FormatInt(n, 10))
+		case uint:
+			err = w.writeNumber(strconv.FormatUint(uint64(n), 10))
+		case float64:
+			err = w.writeNumber(strconv.FormatFloat(n, 'f', -1, 64))
+		case nil:
+			_, err = w.sheet.WriteString("<c/>")
+		default:
+			err = w.writeString(fmt.Sprint(v))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err := w.sheet.WriteString("</row>")
+	return err
+}
+
+// Flush pushes buffered rows to the underlying writer
+func (w *Writer) Flush() error {
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zw.Flush()
+}
+
+// Close finishes the sheet and the zip archive
+func (w *Writer) Close() error {
+	if _, err := w.sheet.WriteString(sheetFooterXML); err != nil {
+		return err
+	}
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
+
+func (w *Writer) writeNumber(s string) error {
+	_, err := w.sheet.WriteString("<c><v>" + s + "</v></c>")
+	return err
+}
+
+func (w *Writer) writeString(s string) error {
+	if _, err := w.sheet.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`); err != nil {
+		return err
+	}
+	// EscapeText also replaces characters XML cannot hold
+	if err := xml.EscapeText(w.sheet, []byte(s)); err != nil {
+		return err
+	}
+	_, err := w.sheet.WriteString("</t></is></c>")
+	return err
+}
+
+// bufferString is a tiny io.Writer for escaping short strings
+type bufferString string
+
+func (b *bufferString) Write(p []byte) (int, error) {
+	*b += bufferString(p)
+	return len(p), nil
+}
@@ -0,0 +1,265 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Limits on what an uploaded workbook may make us allocate
+const (
+	maxColumns   = 16384    // XFD, the last column Excel allows
+	maxCells     = 1 << 22  // Including the empty cells padding sparse rows
+	maxEntrySize = 64 << 20 // Inflated size of one part, against zip bombs
+)
+
+// ReadFirstSheet returns the cell values of the first worksheet as rows of strings
+// Only what a data sheet needs is supported: shared, inline and plain values.
+// Formulas yield their cached value; styles and dates are not interpreted.
+// Sheets with rows past maxRows are rejected without reading them into memory.
+func ReadFirstSheet(r io.ReaderAt, size int64, maxRows int) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("not a valid xlsx file: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if shared, err = readSharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("worksheet %s not found", sheetPath)
+	}
+	return readSheet(f, shared, maxRows)
+}
+
+type workbook struct {
+	Sheets []struct {
+		RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relationships struct {
+	Items []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// firstSheetPath follows workbook.xml and its rels to the first sheet in tab order
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	wbFile, ok := files["xl/workbook.xml"]
+	relsFile, hasRels := files["xl/_rels/workbook.xml.rels"]
+	if !ok || !hasRels {
+		return fallback, nil
+	}
+
+	var wb workbook
+	if err := decodeFile(wbFile, &wb); err != nil {
+		return "", err
+	}
+	var rels relationships
+	if err := decodeFile(relsFile, &rels); err != nil {
+		return "", err
+	}
+	if len(wb.Sheets) == 0 {
+		return "", fmt.Errorf("workbook has no sheets")
+	}
+
+	for _, rel := range rels.Items {
+		if rel.ID != wb.Sheets[0].RID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return fallback, nil
+}
+
+// richText is a string item: plain <t> or rich text runs <r><t>
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (s richText) String() string {
+	if len(s.Runs) == 0 {
+		return s.T
+	}
+	var b strings.Builder
+	for _, r := range s.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	var sst struct {
+		Items []richText `xml:"si"`
+	}
+	if err := decodeFile(f, &sst); err != nil {
+		return nil, err
+	}
+
+	shared := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		shared[i] = item.String()
+	}
+	return shared, nil
+}
+
+type sheetXML struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			Ref    string    `xml:"r,attr"`
+			Type   string    `xml:"t,attr"`
+			Value  string    `xml:"v"`
+			Inline *richText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+func readSheet(f *zip.File, shared []string, maxRows int) ([][]string, error) {
+	var sheet sheetXML
+	if err := decodeFile(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	cells := 0
+	for i, row := range sheet.Rows {
+		// Empty rows are omitted from the XML; keep row numbers aligned
+		rowNum := row.R
+		if rowNum == 0 {
+			rowNum = i + 1
+		}
+		if rowNum > maxRows && len(row.Cells) == 0 {
+			continue // Formatted but empty, as Excel writes them
+		}
+		if rowNum < 0 || rowNum > maxRows {
+			return nil, fmt.Errorf("sheet has more than %d rows", maxRows)
+		}
+		for len(rows) < rowNum-1 {
+			rows = append(rows, nil)
+		}
+
+		var values []string
+		for j, cell := range row.Cells {
+			col := j
+			if cell.Ref != "" {
+				c, err := columnIndex(cell.Ref)
+				if err != nil {
+					return nil, err
+				}
+				col = c
+			}
+			if col >= maxColumns {
+				return nil, fmt.Errorf("cell %s is past the last column", cell.Ref)
+			}
+			if col >= len(values) {
+				cells += col + 1 - len(values)
+			}
+			if cells > maxCells {
+				return nil, fmt.Errorf("sheet has more than %d cells", maxCells)
+			}
+			for len(values) < col {
+				values = append(values, "")
+			}
+
+			value, err := cellValue(cell.Type, cell.Value, cell.Inline, shared)
+			if err != nil {
+				return nil, fmt.Errorf("cell %s: %w", cell.Ref, err)
+			}
+			if col < len(values) {
+				values[col] = value
+			} else {
+				values = append(values, value)
+			}
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+func cellValue(cellType, value string, inline *richText, shared []string) (string, error) {
+	switch cellType {
+	case "s":
+		idx, err := strconv.Atoi(value)
+		if err != nil || idx < 0 || idx >= len(shared) {
+			return "", fmt.Errorf("invalid shared string index %q", value)
+		}
+		return shared[idx], nil
+	case "inlineStr":
+		if inline == nil {
+			return "", nil
+		}
+		return inline.String(), nil
+	case "b":
+		if value == "1" {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	default:
+		// n, str, e and untyped cells carry the value as is
+		return value, nil
+	}
+}
+
+// columnIndex converts a cell reference like "AB12" to a 0-based column index
+func columnIndex(ref string) (int, error) {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		n++
+		if col > maxColumns {
+			return 0, fmt.Errorf("cell %s is past the last column", ref)
+		}
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("invalid cell reference %q", ref)
+	}
+	return col - 1, nil
+}
+
+func decodeFile(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	// A part inflating past the limit is cut off and fails to parse
+	if err := xml.NewDecoder(io.LimitReader(rc, maxEntrySize)).Decode(v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", f.Name, err)
+	}
+	return nil
+}
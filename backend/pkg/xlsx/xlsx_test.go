@@ -0,0 +1,192 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// buildSheet zips a workbook holding only the given sheet and shared strings parts
+func buildSheet(t *testing.T, sheet, sharedStrings string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	parts := map[string]string{"xl/worksheets/sheet1.xml": sheet}
+	if sharedStrings != "" {
+		parts["xl/sharedStrings.xml"] = sharedStrings
+	}
+	for name, body := range parts {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		if _, err := f.Write([]byte(body)); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func sheetXMLOf(rows string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?><worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+		rows + `</sheetData></worksheet>`
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, "Products")
+	if err != nil {
+		t.Fatalf("NewWriter: %v", err)
+	}
+	rows := [][]interface{}{
+		{"name", "price", "stock"},
+		{"Áo thun <cổ tròn> & \"basic\"", int64(150000), 12},
+		{"  Quần jean  ", 1.5, nil},
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row); err != nil {
+			t.Fatalf("WriteRow: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	got, err := ReadFirstSheet(bytes.NewReader(buf.Bytes()), int64(buf.Len()), 10)
+	if err != nil {
+		t.Fatalf("ReadFirstSheet: %v", err)
+	}
+	want := [][]string{
+		{"name", "price", "stock"},
+		{"Áo thun <cổ tròn> & \"basic\"", "150000", "12"},
+		{"  Quần jean  ", "1.5", ""},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rows = %q, want %q", got, want)
+	}
+}
+
+func TestReadFirstSheet(t *testing.T) {
+	shared := `<sst><si><t>name</t></si><si><r><t>Áo </t></r><r><t>thun</t></r></si></sst>`
+	tests := []struct {
+		name    string
+		rows    string
+		maxRows int
+		want    [][]string
+		wantErr string
+	}{
+		{
+			name:    "shared, inline and typed values",
+			rows:    `<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c></row><row r="2"><c r="A2" t="inlineStr"><is><t>x</t></is></c><c r="B2" t="b"><v>1</v></c><c r="C2"><v>42</v></c></row>`,
+			maxRows: 10,
+			want:    [][]string{{"name", "Áo thun"}, {"x", "TRUE", "42"}},
+		},
+		{
+			name:    "sparse rows and cells keep their positions",
+			rows:    `<row r="1"><c r="C1"><v>1</v></c></row><row r="3"><c r="B3"><v>2</v></c></row>`,
+			maxRows: 10,
+			want:    [][]string{{"", "", "1"}, nil, {"", "2"}},
+		},
+		{
+			name:    "last row allowed",
+			rows:    `<row r="3"><c r="A3"><v>1</v></c></row>`,
+			maxRows: 3,
+			want:    [][]string{nil, nil, {"1"}},
+		},
+		{
+			name:    "empty formatted rows past the limit are ignored",
+			rows:    `<row r="1"><c r="A1"><v>1</v></c></row><row r="1048576"/>`,
+			maxRows: 3,
+			want:    [][]string{{"1"}},
+		},
+		{
+			name:    "row past the limit",
+			rows:    `<row r="1"><c r="A1"><v>1</v></c></row><row r="1048576"><c r="A1048576"><v>1</v></c></row>`,
+			maxRows: 3,
+			wantErr: "more than 3 rows",
+		},
+		{
+			name:    "too many unnumbered rows",
+			rows:    strings.Repeat(`<row><c><v>1</v></c></row>`, 4),
+			maxRows: 3,
+			wantErr: "more than 3 rows",
+		},
+		{
+			name:    "last column allowed",
+			rows:    `<row r="1"><c r="XFD1"><v>1</v></c></row>`,
+			maxRows: 3,
+		},
+		{
+			name:    "column past XFD",
+			rows:    `<row r="1"><c r="XFE1"><v>1</v></c></row>`,
+			maxRows: 3,
+			wantErr: "past the last column",
+		},
+		{
+			name:    "huge column reference",
+			rows:    `<row r="1"><c r="ZZZZZZZZZZZZZZ1"><v>1</v></c></row>`,
+			maxRows: 3,
+			wantErr: "past the last column",
+		},
+		{
+			name:    "invalid shared string index",
+			rows:    `<row r="1"><c r="A1" t="s"><v>7</v></c></row>`,
+			maxRows: 3,
+			wantErr: "invalid shared string index",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := buildSheet(t, sheetXMLOf(tt.rows), shared)
+			got, err := ReadFirstSheet(r, r.Size(), tt.maxRows)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rows = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadFirstSheetCellLimit(t *testing.T) {
+	// Every row padded out to the last column
+	rows := &strings.Builder{}
+	n := maxCells/maxColumns + 1
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(rows, `<row r="%d"><c r="XFD%d"><v>1</v></c></row>`, i, i)
+	}
+	r := buildSheet(t, sheetXMLOf(rows.String()), "")
+	if _, err := ReadFirstSheet(r, r.Size(), n); err == nil || !strings.Contains(err.Error(), "cells") {
+		t.Fatalf("err = %v, want too many cells", err)
+	}
+}
+
+func TestReadFirstSheetEntrySizeLimit(t *testing.T) {
+	// Whitespace compresses to almost nothing but inflates past the limit
+	sheet := sheetXMLOf(`<row r="1"><c r="A1"><v>1</v></c></row>` + strings.Repeat(" ", maxEntrySize))
+	r := buildSheet(t, sheet, "")
+	if _, err := ReadFirstSheet(r, r.Size(), 10); err == nil || !strings.Contains(err.Error(), "failed to parse") {
+		t.Fatalf("err = %v, want a parse error", err)
+	}
+}
+
+func TestReadFirstSheetNotZip(t *testing.T) {
+	r := bytes.NewReader([]byte("name,price\n"))
+	if _, err := ReadFirstSheet(r, r.Size(), 10); err == nil || !strings.Contains(err.Error(), "not a valid xlsx file") {
+		t.Fatalf("err = %v, want not a valid xlsx file", err)
+	}
+}